@@ -12,6 +12,7 @@ import (
 
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/storage"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
 	"github.com/elastic/elastic-agent/internal/pkg/core/status"
 	"github.com/elastic/elastic-agent/internal/pkg/sorted"
 
@@ -30,6 +31,7 @@ type Application interface {
 	Stop() error
 	AgentInfo() *info.AgentInfo
 	Routes() *sorted.Set
+	Composable() composable.Controller
 }
 
 type reexecManager interface {
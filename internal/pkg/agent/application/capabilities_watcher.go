@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package application
+
+import (
+	"os"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/configuration"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/capabilities"
+	"github.com/elastic/elastic-agent/internal/pkg/filewatcher"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// capabilitiesWatcher reloads the capabilities when their file changes, the emitter subscribed to
+// the capabilities applies the last configuration again.
+type capabilitiesWatcher struct {
+	log     *logger.Logger
+	file    string
+	caps    capabilities.Reloadable
+	period  time.Duration
+	done    chan struct{}
+	watcher *filewatcher.Watch
+	// failed is true when the last reload failed, the file is loaded again even when unchanged.
+	failed bool
+}
+
+func newCapabilitiesWatcher(log *logger.Logger, file string, caps capabilities.Capability, cfg *configuration.CapabilitiesConfig) *capabilitiesWatcher {
+	reloadable, ok := caps.(capabilities.Reloadable)
+	if !ok || cfg == nil || cfg.Reload == nil || !cfg.Reload.Enabled {
+		log.Debug("Reloading of capabilities is off")
+		return nil
+	}
+
+	w, err := filewatcher.New(log, filewatcher.DefaultComparer)
+
+	// this should not happen.
+	if err != nil {
+		panic(err)
+	}
+
+	return &capabilitiesWatcher{
+		log:     log,
+		file:    file,
+		caps:    reloadable,
+		period:  cfg.Reload.Period,
+		done:    make(chan struct{}),
+		watcher: w,
+	}
+}
+
+// Start watches the capabilities file for changes.
+func (c *capabilitiesWatcher) Start() error {
+	if c == nil {
+		return nil
+	}
+
+	// capabilities are already loaded, the first update only records the state of the file.
+	if _, err := c.update(); err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			t := time.NewTimer(c.period)
+			select {
+			case <-c.done:
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			if err := c.work(); err != nil {
+				c.log.Error(err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops watching the capabilities file.
+func (c *capabilitiesWatcher) Stop() {
+	if c == nil {
+		return
+	}
+	close(c.done)
+}
+
+func (c *capabilitiesWatcher) work() error {
+	st, err := c.update()
+	if err != nil {
+		return err
+	}
+
+	if !st.NeedUpdate && !c.failed {
+		return nil
+	}
+
+	c.log.Infof("Capabilities changes detected in %s", c.file)
+	if err := c.caps.Reload(); err != nil {
+		// keep running with the previous capabilities and retry on next tick.
+		c.failed = true
+		return errors.New(err, "could not reload capabilities", errors.TypeConfig, errors.M(errors.MetaKeyPath, c.file))
+	}
+
+	c.failed = false
+	return nil
+}
+
+// update scans the capabilities file, a created or removed file is reported as a change.
+func (c *capabilitiesWatcher) update() (filewatcher.Status, error) {
+	c.watcher.Reset()
+	if _, err := os.Stat(c.file); err == nil {
+		c.watcher.Watch(c.file)
+	}
+
+	st, err := c.watcher.Update()
+	if err != nil {
+		return st, errors.New(err, "could not update the capabilities file state", errors.TypeFilesystem, errors.M(errors.MetaKeyPath, c.file))
+	}
+	return st, nil
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package application
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/configuration"
+	"github.com/elastic/elastic-agent/internal/pkg/capabilities"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestCapabilitiesWatcher(t *testing.T) {
+	log, _ := logger.New("", false)
+	statusCtrl := status.NewController(log)
+	file := filepath.Join(t.TempDir(), "capabilities.yml")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	caps, err := capabilities.Load(file, log, statusCtrl)
+	require.NoError(t, err)
+
+	disabled := configuration.DefaultCapabilitiesConfig()
+	disabled.Reload.Enabled = false
+	assert.Nil(t, newCapabilitiesWatcher(log, file, caps, disabled))
+
+	w := newCapabilitiesWatcher(log, file, caps, configuration.DefaultCapabilitiesConfig())
+	require.NotNil(t, w)
+	_, err = w.update()
+	require.NoError(t, err)
+
+	changed := caps.(capabilities.Reloadable).Subscribe(ctx)
+	runningInputs := func() int {
+		out, err := caps.Apply(map[string]interface{}{
+			"inputs": []interface{}{
+				map[string]interface{}{"type": "system/logs"},
+				map[string]interface{}{"type": "system/metrics"},
+			},
+		})
+		require.NoError(t, err)
+		switch inputs := out.(map[string]interface{})["inputs"].(type) {
+		case []map[string]interface{}:
+			return len(inputs)
+		case []interface{}:
+			return len(inputs)
+		}
+		return 0
+	}
+	assert.Equal(t, 2, runningInputs())
+
+	// nothing changed
+	require.NoError(t, w.work())
+	assert.Len(t, changed, 0)
+
+	// created file
+	require.NoError(t, ioutil.WriteFile(file, []byte(`
+capabilities:
+- rule: deny
+  input: system/logs
+`), 0600))
+	require.NoError(t, w.work())
+	assert.Len(t, changed, 1)
+	<-changed
+	assert.Equal(t, 1, runningInputs())
+
+	// invalid file keeps the previous capabilities
+	require.NoError(t, ioutil.WriteFile(file, []byte(`capabilities: [`), 0600))
+	require.Error(t, w.work())
+	assert.Len(t, changed, 0)
+	assert.Equal(t, 1, runningInputs())
+	assert.Equal(t, status.Degraded, statusCtrl.Status().Status)
+
+	// removed file
+	require.NoError(t, os.Remove(file))
+	require.NoError(t, w.work())
+	assert.Len(t, changed, 1)
+	assert.Equal(t, 2, runningInputs())
+	assert.Equal(t, status.Healthy, statusCtrl.Status().Status)
+}
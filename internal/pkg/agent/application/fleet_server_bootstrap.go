@@ -23,6 +23,7 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/agent/configuration"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/operation"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
 	"github.com/elastic/elastic-agent/internal/pkg/config"
 	"github.com/elastic/elastic-agent/internal/pkg/core/monitoring"
 	monitoringCfg "github.com/elastic/elastic-agent/internal/pkg/core/monitoring/config"
@@ -129,6 +130,11 @@ func (b *FleetServerBootstrap) Routes() *sorted.Set {
 	return b.router.Routes()
 }
 
+// Composable returns nil, the fleet server bootstrap does not run the composable providers.
+func (b *FleetServerBootstrap) Composable() composable.Controller {
+	return nil
+}
+
 // Start starts a managed elastic-agent.
 func (b *FleetServerBootstrap) Start() error {
 	b.log.Info("Agent is starting")
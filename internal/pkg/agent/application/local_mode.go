@@ -49,8 +49,11 @@ type Local struct {
 	log         *logger.Logger
 	router      pipeline.Router
 	source      source
+	specs       *specsWatcher
+	caps        *capabilitiesWatcher
 	agentInfo   *info.AgentInfo
 	srv         *server.Server
+	composable  composable.Controller
 }
 
 type source interface {
@@ -113,10 +116,11 @@ func newLocal(
 	}
 	localApplication.router = router
 
-	composableCtrl, err := composable.New(log, rawConfig)
+	composableCtrl, err := composable.New(log, rawConfig, statusCtrl)
 	if err != nil {
 		return nil, errors.New(err, "failed to initialize composable controller")
 	}
+	localApplication.composable = composableCtrl
 
 	discover := discoverer(pathConfigFile, cfg.Settings.Path, externalConfigsGlob())
 	emit, err := emitter.New(
@@ -148,6 +152,8 @@ func newLocal(
 	}
 
 	localApplication.source = cfgSource
+	localApplication.specs = newSpecsWatcher(log, cfg.Settings.Specs, statusCtrl)
+	localApplication.caps = newCapabilitiesWatcher(log, paths.AgentCapabilitiesPath(), caps, cfg.Settings.Capabilities)
 
 	// create a upgrader to use in local mode
 	upgrader := upgrade.NewUpgrader(
@@ -173,6 +179,11 @@ func (l *Local) Routes() *sorted.Set {
 	return l.router.Routes()
 }
 
+// Composable returns the controller of the composable providers.
+func (l *Local) Composable() composable.Controller {
+	return l.composable
+}
+
 // Start starts a local agent.
 func (l *Local) Start() error {
 	l.log.Info("Agent is starting")
@@ -181,6 +192,12 @@ func (l *Local) Start() error {
 	if err := l.srv.Start(); err != nil {
 		return err
 	}
+	if err := l.specs.Start(); err != nil {
+		return err
+	}
+	if err := l.caps.Start(); err != nil {
+		return err
+	}
 	if err := l.source.Start(); err != nil {
 		return err
 	}
@@ -191,6 +208,8 @@ func (l *Local) Start() error {
 // Stop stops a local agent.
 func (l *Local) Stop() error {
 	err := l.source.Stop()
+	l.specs.Stop()
+	l.caps.Stop()
 	l.cancelCtxFn()
 	l.router.Shutdown()
 	l.srv.Stop()
@@ -70,6 +70,9 @@ type Managed struct {
 	srv         *server.Server
 	stateStore  stateStore
 	upgrader    *upgrade.Upgrader
+	specs       *specsWatcher
+	caps        *capabilitiesWatcher
+	composable  composable.Controller
 }
 
 func newManaged(
@@ -137,11 +140,14 @@ func newManaged(
 		return nil, errors.New(err, "fail to initialize pipeline router")
 	}
 	managedApplication.router = router
+	managedApplication.specs = newSpecsWatcher(log, cfg.Settings.Specs, statusCtrl)
+	managedApplication.caps = newCapabilitiesWatcher(log, paths.AgentCapabilitiesPath(), caps, cfg.Settings.Capabilities)
 
-	composableCtrl, err := composable.New(log, rawConfig)
+	composableCtrl, err := composable.New(log, rawConfig, statusCtrl)
 	if err != nil {
 		return nil, errors.New(err, "failed to initialize composable controller")
 	}
+	managedApplication.composable = composableCtrl
 
 	emit, err := emitter.New(
 		managedApplication.bgContext,
@@ -294,6 +300,11 @@ func (m *Managed) Routes() *sorted.Set {
 	return m.router.Routes()
 }
 
+// Composable returns the controller of the composable providers.
+func (m *Managed) Composable() composable.Controller {
+	return m.composable
+}
+
 // Start starts a managed elastic-agent.
 func (m *Managed) Start() error {
 	m.log.Info("Agent is starting")
@@ -312,6 +323,13 @@ func (m *Managed) Start() error {
 		m.log.Warnf("failed to ack update %v", err)
 	}
 
+	if err := m.specs.Start(); err != nil {
+		return err
+	}
+	if err := m.caps.Start(); err != nil {
+		return err
+	}
+
 	err = m.gateway.Start()
 	if err != nil {
 		return err
@@ -322,6 +340,8 @@ func (m *Managed) Start() error {
 // Stop stops a managed elastic-agent.
 func (m *Managed) Stop() error {
 	defer m.log.Info("Agent is stopped")
+	m.specs.Stop()
+	m.caps.Stop()
 	m.cancelCtxFn()
 	m.router.Shutdown()
 	m.srv.Stop()
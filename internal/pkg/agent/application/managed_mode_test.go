@@ -25,6 +25,7 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/agent/configrequest"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/storage"
 	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
 	"github.com/elastic/elastic-agent/internal/pkg/fleetapi"
 	"github.com/elastic/elastic-agent/pkg/core/logger"
 )
@@ -45,7 +46,7 @@ func TestManagedModeRouting(t *testing.T) {
 	router, _ := router.New(log, streamFn)
 	agentInfo, _ := info.NewAgentInfo(true)
 	nullStore := &storage.NullStore{}
-	composableCtrl, _ := composable.New(log, nil)
+	composableCtrl, _ := composable.New(log, nil, status.NewController(log))
 	emit, err := emitter.New(ctx, log, agentInfo, composableCtrl, router, &pipeline.ConfigModifiers{Decorators: []pipeline.DecoratorFunc{modifiers.InjectMonitoring}}, nil)
 	require.NoError(t, err)
 
@@ -26,6 +26,15 @@ const defaultAgentActionStoreFile = "action_store.yml"
 // defaultAgentStateStoreFile is the file that will contain the action that can be replayed after restart.
 const defaultAgentStateStoreFile = "state.yml"
 
+// defaultAgentRulesDir is the directory containing the user-defined rules.
+const defaultAgentRulesDir = "rules.d"
+
+// defaultAgentSpecsDir is the directory containing the program specs loaded at runtime.
+const defaultAgentSpecsDir = "specs.d"
+
+// defaultProgramOutputDir is the directory containing the captured stdout and stderr of the programs.
+const defaultProgramOutputDir = "output"
+
 // AgentConfigFile is a name of file used to store agent information
 func AgentConfigFile() string {
 	return filepath.Join(Config(), defaultAgentFleetFile)
@@ -49,6 +58,21 @@ func AgentCapabilitiesPath() string {
 	return filepath.Join(Config(), defaultAgentCapabilitiesFile)
 }
 
+// AgentRulesPath is the directory containing the user-defined rules available to the specs.
+func AgentRulesPath() string {
+	return filepath.Join(Data(), defaultAgentRulesDir)
+}
+
+// AgentSpecsPath is the default directory containing the program specs loaded at runtime.
+func AgentSpecsPath() string {
+	return filepath.Join(Data(), defaultAgentSpecsDir)
+}
+
+// ProgramOutputPath is the directory containing the captured stdout and stderr of the programs.
+func ProgramOutputPath() string {
+	return filepath.Join(Home(), "logs", defaultProgramOutputDir)
+}
+
 // AgentActionStoreFile is the file that contains the action that can be replayed after restart.
 func AgentActionStoreFile() string {
 	return filepath.Join(Home(), defaultAgentActionStoreFile)
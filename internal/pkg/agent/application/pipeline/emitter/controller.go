@@ -41,6 +41,10 @@ type Controller struct {
 	config     *config.Config
 	ast        *transpiler.AST
 	vars       []*transpiler.Vars
+	// varsReceived is true once the composable controller provided the vars.
+	varsReceived bool
+	// analyzed is true once the conditions of the current configuration were analyzed.
+	analyzed bool
 }
 
 // NewController creates a new emitter controller.
@@ -112,8 +116,10 @@ func (e *Controller) Update(ctx context.Context, c *config.Config) (err error) {
 	e.lock.Lock()
 	e.config = c
 	e.ast = rawAst
+	e.analyzed = false
 	e.lock.Unlock()
 
+	e.analyzeConditions()
 	return e.update(ctx)
 }
 
@@ -133,14 +139,62 @@ func (e *Controller) set(ctx context.Context, vars []*transpiler.Vars) (err erro
 	e.lock.Lock()
 	ast := e.ast
 	e.vars = vars
+	e.varsReceived = true
 	e.lock.Unlock()
 
+	e.analyzeConditions()
 	if ast != nil {
 		return e.update(ctx)
 	}
 	return nil
 }
 
+// Refresh renders the last configuration again so the programs are reconciled with the current specs.
+func (e *Controller) Refresh(ctx context.Context) {
+	e.lock.RLock()
+	ast := e.ast
+	e.lock.RUnlock()
+
+	if ast == nil {
+		return
+	}
+	if err := e.update(ctx); err != nil {
+		e.logger.Errorf("Failed to render configuration with latest program specs: %s", err)
+	}
+}
+
+// Reapply applies the last configuration again so changed capabilities take effect.
+func (e *Controller) Reapply(ctx context.Context) {
+	e.lock.RLock()
+	c := e.config
+	e.lock.RUnlock()
+
+	if c == nil {
+		return
+	}
+	if err := e.Update(ctx, c); err != nil {
+		e.logger.Errorf("Failed to apply configuration with latest capabilities: %s", err)
+	}
+}
+
+// analyzeConditions warns about the conditions of the configuration that cannot evaluate as expected,
+// the analysis waits for the vars so missing variables are reported as well.
+func (e *Controller) analyzeConditions() {
+	e.lock.Lock()
+	if e.ast == nil || !e.varsReceived || e.analyzed {
+		e.lock.Unlock()
+		return
+	}
+	e.analyzed = true
+	ast := e.ast
+	varsArray := e.vars
+	e.lock.Unlock()
+
+	for _, w := range transpiler.AnalyzeConditions(ast, varsArray) {
+		e.logger.Warnf("Configuration %s", w)
+	}
+}
+
 func (e *Controller) update(ctx context.Context) (err error) {
 	span, ctx := apm.StartSpan(ctx, "update", "app.internal")
 	defer func() {
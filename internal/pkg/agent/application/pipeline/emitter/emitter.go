@@ -32,6 +32,25 @@ func New(ctx context.Context, log *logger.Logger, agentInfo *info.AgentInfo, con
 	if err != nil {
 		return nil, errors.New(err, "failed to start composable controller")
 	}
+
+	specsChanged := program.SubscribeSpecs(ctx)
+	var capsChanged <-chan struct{}
+	if r, ok := caps.(capabilities.Reloadable); ok {
+		capsChanged = r.Subscribe(ctx)
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-specsChanged:
+				ctrl.Refresh(ctx)
+			case <-capsChanged:
+				ctrl.Reapply(ctx)
+			}
+		}
+	}()
+
 	return func(ctx context.Context, c *config.Config) (err error) {
 		span, ctx := apm.StartSpan(ctx, "update", "app.internal")
 		defer func() {
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/elastic/elastic-agent/internal/pkg/agent/application/pipeline"
@@ -108,14 +109,23 @@ func (r *router) Route(ctx context.Context, id string, grpProg map[pipeline.Rout
 }
 
 // Shutdown shutdowns the router because Agent is stopping.
+//
+// The streams are shutdown in parallel, so the applications draining their queued events on stop take
+// at most one drain timeout overall instead of one per stream.
 func (r *router) Shutdown() {
+	var wg sync.WaitGroup
 	keys := r.routes.Keys()
 	for _, k := range keys {
 		p, ok := r.routes.Get(k)
 		if !ok {
 			continue
 		}
-		p.(pipeline.Stream).Shutdown()
+		wg.Add(1)
+		go func(s pipeline.Stream) {
+			defer wg.Done()
+			s.Shutdown()
+		}(p.(pipeline.Stream))
 		r.routes.Remove(k)
 	}
+	wg.Wait()
 }
@@ -6,7 +6,9 @@ package router
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -174,6 +176,50 @@ func TestRouter(t *testing.T) {
 	})
 }
 
+func TestRouterShutdown(t *testing.T) {
+	programs := []program.Program{{Spec: program.Supported[1]}}
+	keys := []pipeline.RoutingKey{pipeline.DefaultRK, "k1", "k2"}
+
+	// every stream blocks its shutdown until all of them are shutting down
+	var started sync.WaitGroup
+	started.Add(len(keys))
+	factory := func(_ *logger.Logger, rk pipeline.RoutingKey) (pipeline.Stream, error) {
+		return &shutdownStream{started: &started}, nil
+	}
+
+	r, err := New(nil, factory)
+	require.NoError(t, err)
+	routes := make(map[pipeline.RoutingKey][]program.Program, len(keys))
+	for _, k := range keys {
+		routes[k] = programs
+	}
+	require.NoError(t, r.Route(context.Background(), "hello", routes))
+
+	done := make(chan struct{})
+	go func() {
+		r.Shutdown()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("streams are not shutdown in parallel")
+	}
+}
+
+type shutdownStream struct {
+	started *sync.WaitGroup
+}
+
+func (s *shutdownStream) Execute(context.Context, configrequest.Request) error { return nil }
+
+func (s *shutdownStream) Close() error { return nil }
+
+func (s *shutdownStream) Shutdown() {
+	s.started.Done()
+	s.started.Wait()
+}
+
 type recorder struct {
 	events []event
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/elastic/elastic-agent/internal/pkg/agent/application/pipeline"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/configrequest"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/operation"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
 	"github.com/elastic/elastic-agent/internal/pkg/core/state"
 	"github.com/elastic/elastic-agent/pkg/core/logger"
@@ -29,6 +30,10 @@ type specer interface {
 	Specs() map[string]program.Spec
 }
 
+type journaler interface {
+	Journal() []operation.JournalEntry
+}
+
 func (b *operatorStream) Close() error {
 	return b.configHandler.Close()
 }
@@ -48,6 +53,13 @@ func (b *operatorStream) Specs() map[string]program.Spec {
 	return nil
 }
 
+func (b *operatorStream) Journal() []operation.JournalEntry {
+	if j, ok := b.configHandler.(journaler); ok {
+		return j.Journal()
+	}
+	return nil
+}
+
 func (b *operatorStream) Execute(ctx context.Context, cfg configrequest.Request) (err error) {
 	span, ctx := apm.StartSpan(ctx, "route", "app.internal")
 	defer func() {
@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package application
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/application/paths"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/configuration"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/internal/pkg/filewatcher"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// specsWatcher loads the program specs from a directory and keeps them up to date, programs
+// affected by a change are reconciled by the emitter subscribed to the specs.
+type specsWatcher struct {
+	log      *logger.Logger
+	dir      string
+	reload   *configuration.ReloadConfig
+	done     chan struct{}
+	watcher  *filewatcher.Watch
+	reporter status.Reporter
+	// failed is true when the last load failed, specs are loaded again even when files are unchanged.
+	failed bool
+}
+
+func newSpecsWatcher(log *logger.Logger, cfg *configuration.SpecsConfig, statusCtrl status.Controller) *specsWatcher {
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	w, err := filewatcher.New(log, filewatcher.DefaultComparer)
+
+	// this should not happen.
+	if err != nil {
+		panic(err)
+	}
+
+	dir := cfg.Path
+	if dir == "" {
+		dir = paths.AgentSpecsPath()
+	}
+
+	return &specsWatcher{
+		log:      log,
+		dir:      dir,
+		reload:   cfg.Reload,
+		done:     make(chan struct{}),
+		watcher:  w,
+		reporter: statusCtrl.RegisterComponent("specs"),
+	}
+}
+
+// Start loads the specs and watches the directory for changes when reload is enabled.
+func (s *specsWatcher) Start() error {
+	if s == nil {
+		return nil
+	}
+
+	if err := s.work(); err != nil {
+		s.log.Error(err)
+	}
+
+	if s.reload == nil || !s.reload.Enabled {
+		s.log.Debug("Reloading of program specs is off")
+		return nil
+	}
+
+	go func() {
+		for {
+			t := time.NewTimer(s.reload.Period)
+			select {
+			case <-s.done:
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			if err := s.work(); err != nil {
+				s.log.Error(err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops watching the directory.
+func (s *specsWatcher) Stop() {
+	if s == nil {
+		return
+	}
+	close(s.done)
+	s.reporter.Unregister()
+}
+
+func (s *specsWatcher) work() error {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.yml"))
+	if err != nil {
+		return errors.New(err, "could not discover program specs", errors.TypeConfig)
+	}
+
+	s.watcher.Reset()
+	for _, f := range files {
+		s.watcher.Watch(f)
+	}
+
+	st, err := s.watcher.Update()
+	if err != nil {
+		return errors.New(err, "could not update the program specs states", errors.TypeConfig)
+	}
+
+	if !st.NeedUpdate && !s.failed {
+		return nil
+	}
+
+	s.log.Infof("Program specs changes detected in %s", s.dir)
+	specs, err := program.LoadSpecs(s.dir)
+	if err != nil {
+		// keep running with the previous specs and retry on next tick.
+		s.failed = true
+		s.reporter.Update(state.Degraded, err.Error(), nil)
+		return errors.New(err, "could not load program specs", errors.TypeConfig, errors.M(errors.MetaKeyPath, s.dir))
+	}
+
+	s.log.Infof("Loaded %d program specs from %s", len(specs), s.dir)
+	program.SetExternalSpecs(specs)
+	s.failed = false
+	s.reporter.Update(state.Healthy, "", nil)
+	return nil
+}
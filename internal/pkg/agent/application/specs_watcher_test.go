@@ -0,0 +1,64 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package application
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/configuration"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestSpecsWatcher(t *testing.T) {
+	log, _ := logger.New("", false)
+	statusCtrl := status.NewController(log)
+	dir := t.TempDir()
+	defer program.SetExternalSpecs(nil)
+
+	assert.Nil(t, newSpecsWatcher(log, configuration.DefaultSpecsConfig(), statusCtrl))
+
+	cfg := configuration.DefaultSpecsConfig()
+	cfg.Enabled = true
+	cfg.Path = dir
+	w := newSpecsWatcher(log, cfg, statusCtrl)
+	require.NotNil(t, w)
+
+	specFile := filepath.Join(dir, "internalbeat.yml")
+	require.NoError(t, ioutil.WriteFile(specFile, []byte(`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+when: length(${inputs}) > 0
+`), 0600))
+
+	require.NoError(t, w.work())
+	spec, ok := program.SpecByCmd("internalbeat")
+	require.True(t, ok)
+	assert.Equal(t, "beats/internalbeat", spec.Artifact)
+
+	// invalid spec keeps the previous specs
+	require.NoError(t, ioutil.WriteFile(specFile, []byte(`
+name: Internalbeat
+cmd: internalbeat
+`), 0600))
+	require.Error(t, w.work())
+	_, ok = program.SpecByCmd("internalbeat")
+	assert.True(t, ok)
+	assert.Equal(t, status.Degraded, statusCtrl.Status().Status)
+
+	// removed spec
+	require.NoError(t, os.Remove(specFile))
+	require.NoError(t, w.work())
+	_, ok = program.SpecByCmd("internalbeat")
+	assert.False(t, ok)
+}
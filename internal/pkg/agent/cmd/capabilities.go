@@ -0,0 +1,285 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/capabilities"
+	"github.com/elastic/elastic-agent/internal/pkg/cli"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/internal/pkg/fleetapi"
+)
+
+var capabilitiesCheckOutputs = map[string]outputter{
+	"human": humanCapabilitiesCheckOutput,
+	"json":  jsonOutput,
+	"yaml":  yamlOutput,
+}
+
+// CapabilitiesCheckResult is the outcome of applying a capabilities file to a policy or an upgrade action.
+type CapabilitiesCheckResult struct {
+	Decisions []capabilities.Decision `json:"decisions" yaml:"decisions"`
+	// Blocked is true when the upgrade action is blocked.
+	Blocked bool `json:"blocked" yaml:"blocked"`
+	// LocalHost is true when the host conditions are evaluated against the host running the check.
+	LocalHost bool `json:"local_host" yaml:"local_host"`
+	// LocalTime is true when the upgrade windows are evaluated at the current time.
+	LocalTime bool `json:"local_time" yaml:"local_time"`
+}
+
+func newCapabilitiesCommandWithArgs(s []string, streams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "Work with capabilities files",
+		Long:  "Work with capabilities files restricting what the agent runs.",
+	}
+
+	cmd.AddCommand(newCapabilitiesCheckCommandWithArgs(s, streams))
+
+	return cmd
+}
+
+func newCapabilitiesCheckCommandWithArgs(_ []string, streams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check <capabilities.yml>",
+		Short: "Check a capabilities file against a policy or an upgrade action",
+		Long: `Check applies a capabilities file to a policy or to an upgrade action and reports the
+inputs and outputs removed, the upgrades blocked and the rules taking each decision.
+
+Host conditions are evaluated against the host running the check and upgrade windows at the current
+time unless --host, --host-file or --time are set, the decisions depending on them are marked.`,
+		Example: `elastic-agent capabilities check capabilities.yml --policy elastic-agent.yml
+elastic-agent capabilities check capabilities.yml --upgrade action.json --host platform=windows --time 2022-03-04T03:00:00+01:00`,
+		Args: cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			if err := capabilitiesCheckCmd(streams, c, args); err != nil {
+				fmt.Fprintf(streams.Err, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().String("policy", "", "Policy the capabilities are applied to")
+	cmd.Flags().String("upgrade", "", "Upgrade action in JSON the capabilities are applied to")
+	cmd.Flags().StringSlice("host", []string{}, "Host information the host conditions are evaluated against, as name=value (e.g. platform=windows)")
+	cmd.Flags().String("host-file", "", "YAML file with the host information the host conditions are evaluated against, --host values take precedence")
+	cmd.Flags().String("time", "", "Time in RFC3339 the upgrade windows are evaluated at, windows without timezone use its offset")
+	cmd.Flags().String("output", "human", "Output the result in either human, json, or yaml (default: human)")
+
+	return cmd
+}
+
+func capabilitiesCheckCmd(streams *cli.IOStreams, cmd *cobra.Command, args []string) error {
+	policyPath, _ := cmd.Flags().GetString("policy")
+	upgradePath, _ := cmd.Flags().GetString("upgrade")
+	if (policyPath == "") == (upgradePath == "") {
+		return fmt.Errorf("one of --policy or --upgrade is required")
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	outputFunc, ok := capabilitiesCheckOutputs[output]
+	if !ok {
+		return fmt.Errorf("unsupported output: %s", output)
+	}
+
+	capsPath := args[0]
+	if _, err := os.Stat(capsPath); err != nil {
+		return errors.New(err, "could not read capabilities file", errors.TypeFilesystem, errors.M(errors.MetaKeyPath, capsPath))
+	}
+
+	var in interface{}
+	var err error
+	if policyPath != "" {
+		in, err = loadCheckPolicy(policyPath)
+	} else {
+		in, err = loadCheckUpgrade(upgradePath)
+	}
+	if err != nil {
+		return err
+	}
+
+	env, err := loadCheckEnv(cmd)
+	if err != nil {
+		return err
+	}
+
+	l, err := newErrorLogger()
+	if err != nil {
+		return err
+	}
+
+	_, decisions, err := capabilities.Check(capsPath, l, in, env)
+	result := &CapabilitiesCheckResult{
+		Decisions: decisions,
+		LocalHost: env.Host == nil,
+		LocalTime: env.Time.IsZero(),
+	}
+	if stderrors.Is(err, capabilities.ErrBlocked) {
+		result.Blocked = true
+	} else if err != nil {
+		return errors.New(err, "could not apply capabilities", errors.TypeConfig, errors.M(errors.MetaKeyPath, capsPath))
+	}
+
+	return outputFunc(streams.Out, result)
+}
+
+// loadCheckEnv reads the host and the time the capabilities are checked for, they are left empty
+// when not set so the host running the check and the current time are used.
+func loadCheckEnv(cmd *cobra.Command) (capabilities.CheckEnv, error) {
+	var env capabilities.CheckEnv
+
+	hostPath, _ := cmd.Flags().GetString("host-file")
+	if hostPath != "" {
+		rawConfig, err := config.LoadFile(hostPath)
+		if err != nil {
+			return env, errors.New(err, "could not read host information", errors.TypeConfig, errors.M(errors.MetaKeyPath, hostPath))
+		}
+		env.Host, err = rawConfig.ToMapStr()
+		if err != nil {
+			return env, errors.New(err, "could not parse host information", errors.TypeConfig, errors.M(errors.MetaKeyPath, hostPath))
+		}
+	}
+
+	hostValues, _ := cmd.Flags().GetStringSlice("host")
+	for _, kv := range hostValues {
+		if !strings.Contains(kv, "=") {
+			return env, fmt.Errorf("invalid --host '%s', expected name=value", kv)
+		}
+	}
+	for name, value := range mapFromEnvList(hostValues) {
+		if env.Host == nil {
+			env.Host = make(map[string]interface{})
+		}
+		env.Host[name] = value
+	}
+
+	at, _ := cmd.Flags().GetString("time")
+	if at != "" {
+		t, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return env, fmt.Errorf("invalid --time '%s', expected RFC3339 e.g. 2022-03-04T03:00:00Z: %w", at, err)
+		}
+		env.Time = t
+	}
+
+	return env, nil
+}
+
+func loadCheckPolicy(path string) (map[string]interface{}, error) {
+	rawConfig, err := config.LoadFile(path)
+	if err != nil {
+		return nil, errors.New(err, "could not read policy", errors.TypeConfig, errors.M(errors.MetaKeyPath, path))
+	}
+
+	policy, err := rawConfig.ToMapStr()
+	if err != nil {
+		return nil, errors.New(err, "could not parse policy", errors.TypeConfig, errors.M(errors.MetaKeyPath, path))
+	}
+
+	return policy, nil
+}
+
+// loadCheckUpgrade reads an upgrade action either as sent by fleet, with the version under data,
+// or with the version at the top level.
+func loadCheckUpgrade(path string) (*fleetapi.ActionUpgrade, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(err, "could not read upgrade action", errors.TypeFilesystem, errors.M(errors.MetaKeyPath, path))
+	}
+
+	var action struct {
+		fleetapi.ActionUpgrade
+		Data *struct {
+			Version   string `json:"version"`
+			SourceURI string `json:"source_uri"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(content, &action); err != nil {
+		return nil, errors.New(err, "could not parse upgrade action", errors.TypeConfig, errors.M(errors.MetaKeyPath, path))
+	}
+
+	upgrade := action.ActionUpgrade
+	if action.Data != nil {
+		upgrade.Version = action.Data.Version
+		upgrade.SourceURI = action.Data.SourceURI
+	}
+	if upgrade.Version == "" {
+		return nil, errors.New("upgrade action has no version", errors.TypeConfig, errors.M(errors.MetaKeyPath, path))
+	}
+
+	return &upgrade, nil
+}
+
+func humanCapabilitiesCheckOutput(w io.Writer, obj interface{}) error {
+	result, ok := obj.(*CapabilitiesCheckResult)
+	if !ok {
+		return fmt.Errorf("unable to cast %T as *CapabilitiesCheckResult", obj)
+	}
+
+	if len(result.Decisions) == 0 {
+		fmt.Fprintln(w, "No rule matched.")
+	} else {
+		tw := tabwriter.NewWriter(w, 4, 1, 2, ' ', 0)
+		fmt.Fprintln(tw, "KIND\tTARGET\tDECISION\tRULE\tDEPENDS ON")
+		for _, d := range result.Decisions {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", d.Kind, d.Target, decisionString(d), d.Rule, dependsString(d))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if result.LocalHost && decisionsDependOn(result.Decisions, capabilities.DependsOnHost) {
+		fmt.Fprintln(w, "Decisions depending on host use the host running the check, set --host or --host-file to check another host.")
+	}
+	if result.LocalTime && decisionsDependOn(result.Decisions, capabilities.DependsOnTime) {
+		fmt.Fprintln(w, "Decisions depending on time use the current time, set --time to check another time.")
+	}
+
+	if result.Blocked {
+		fmt.Fprintln(w, "Upgrade is blocked.")
+	}
+	return nil
+}
+
+func decisionString(d capabilities.Decision) string {
+	if d.Allowed {
+		return "allowed"
+	}
+	if d.Kind == capabilities.UpgradeKind {
+		return "blocked"
+	}
+	return "removed"
+}
+
+func dependsString(d capabilities.Decision) string {
+	if len(d.Depends) == 0 {
+		return "-"
+	}
+	return strings.Join(d.Depends, ",")
+}
+
+func decisionsDependOn(decisions []capabilities.Decision, dependency string) bool {
+	for _, d := range decisions {
+		for _, dep := range d.Depends {
+			if dep == dependency {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/capabilities"
+)
+
+func TestLoadCheckUpgrade(t *testing.T) {
+	testCases := map[string]struct {
+		action  string
+		version string
+		err     bool
+	}{
+		"fleet action": {
+			action:  `{"id": "abc", "type": "UPGRADE", "data": {"version": "8.1.0", "source_uri": "https://example.com"}}`,
+			version: "8.1.0",
+		},
+		"flat action": {
+			action:  `{"id": "abc", "type": "UPGRADE", "version": "8.2.0"}`,
+			version: "8.2.0",
+		},
+		"no version": {
+			action: `{"id": "abc", "type": "UPGRADE"}`,
+			err:    true,
+		},
+		"invalid json": {
+			action: `{"id": `,
+			err:    true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "action.json")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.action), 0600))
+
+			action, err := loadCheckUpgrade(path)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.version, action.Version)
+		})
+	}
+}
+
+func TestHumanCapabilitiesCheckOutput(t *testing.T) {
+	result := &CapabilitiesCheckResult{
+		Decisions: []capabilities.Decision{
+			{Kind: capabilities.InputKind, Target: "system/logs", Rule: "I deny(system/logs)"},
+			{Kind: capabilities.UpgradeKind, Target: "8.1.0", Rule: "UA(${version} == '8.0.0')", Depends: []string{capabilities.DependsOnHost}},
+			{Kind: capabilities.UpgradeKind, Target: "8.1.0", Rule: "UW allow(02:00-05:00)", Depends: []string{capabilities.DependsOnTime}},
+		},
+		Blocked:   true,
+		LocalHost: true,
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, humanCapabilitiesCheckOutput(&b, result))
+	expected := `KIND     TARGET       DECISION  RULE                       DEPENDS ON
+input    system/logs  removed   I deny(system/logs)        -
+upgrade  8.1.0        blocked   UA(${version} == '8.0.0')  host
+upgrade  8.1.0        blocked   UW allow(02:00-05:00)      time
+Decisions depending on host use the host running the check, set --host or --host-file to check another host.
+Upgrade is blocked.
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestLoadCheckEnv(t *testing.T) {
+	hostFile := filepath.Join(t.TempDir(), "host.yml")
+	require.NoError(t, ioutil.WriteFile(hostFile, []byte("name: db-01\nplatform: linux\nip: [10.0.0.1]\n"), 0600))
+
+	testCases := map[string]struct {
+		args []string
+		host map[string]interface{}
+		time string
+		err  bool
+	}{
+		"local": {},
+		"host values": {
+			args: []string{"--host", "platform=windows", "--host", "name=web-01"},
+			host: map[string]interface{}{"platform": "windows", "name": "web-01"},
+		},
+		"host file": {
+			args: []string{"--host-file", hostFile, "--host", "platform=windows"},
+			host: map[string]interface{}{"platform": "windows", "name": "db-01", "ip": []interface{}{"10.0.0.1"}},
+		},
+		"time": {
+			args: []string{"--time", "2022-03-04T03:00:00+09:00"},
+			time: "2022-03-04T03:00:00+09:00",
+		},
+		"invalid host": {
+			args: []string{"--host", "windows"},
+			err:  true,
+		},
+		"invalid time": {
+			args: []string{"--time", "03:00"},
+			err:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cmd := newCapabilitiesCheckCommandWithArgs(nil, nil)
+			require.NoError(t, cmd.ParseFlags(tc.args))
+
+			env, err := loadCheckEnv(cmd)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.host, env.Host)
+			if tc.time == "" {
+				assert.True(t, env.Time.IsZero())
+			} else {
+				assert.Equal(t, tc.time, env.Time.Format(time.RFC3339))
+			}
+		})
+	}
+}
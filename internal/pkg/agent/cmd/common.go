@@ -66,6 +66,15 @@ func NewCommandWithArgs(args []string, streams *cli.IOStreams) *cobra.Command {
 	cmd.AddCommand(newContainerCommand(args, streams))
 	cmd.AddCommand(newStatusCommand(args, streams))
 	cmd.AddCommand(newDiagnosticsCommand(args, streams))
+	cmd.AddCommand(newCapabilitiesCommandWithArgs(args, streams))
+	cmd.AddCommand(newVarsCommandWithArgs(args, streams))
+	cmd.AddCommand(newSecretsCommandWithArgs(args, streams))
+
+	// hidden sub-command executing programs in their cgroup
+	cmd.AddCommand(newCgroupCommand(args, streams))
+
+	// hidden sub-command executing sandboxed programs
+	cmd.AddCommand(newSandboxCommand(args, streams))
 
 	// windows special hidden sub-command (only added on windows)
 	reexec := newReExecWindowsCommand(args, streams)
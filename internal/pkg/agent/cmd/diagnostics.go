@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	stderrors "errors"
@@ -32,6 +33,9 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/config/operations"
 )
 
+// outputTailSize is the size of the end of the captured output of the programs added to the archive.
+const outputTailSize = 1024 * 1024
+
 var diagOutputs = map[string]outputter{
 	"human": humanDiagnosticsOutput,
 	"json":  jsonOutput,
@@ -227,7 +231,7 @@ func diagnosticsCollectCmd(streams *cli.IOStreams, fileName, outputFormat string
 		fmt.Fprintf(streams.Err, "Failed to gather metrics data from elastic-agent: %v\n", err)
 	}
 
-	cfg, err := gatherConfig()
+	cfg, err := gatherConfig(streams.Err)
 	if err != nil {
 		errs = append(errs, fmt.Errorf("unable to gather config data: %w", err))
 		fmt.Fprintf(streams.Err, "Failed to gather config data from elastic-agent: %v\n", err)
@@ -391,7 +395,7 @@ func outputDiagnostics(w io.Writer, d DiagnosticsInfo) error {
 	return nil
 }
 
-func gatherConfig() (AgentConfig, error) {
+func gatherConfig(warnings io.Writer) (AgentConfig, error) {
 	cfg := AgentConfig{}
 	localCFG, err := loadConfig(nil)
 	if err != nil {
@@ -433,7 +437,7 @@ func gatherConfig() (AgentConfig, error) {
 
 	// Get process config - uses same approach as inspect output command.
 	// Does not contact server process to request configs.
-	pMap, err := getProgramsFromConfig(log, agentInfo, renderedCFG, isStandalone)
+	pMap, err := getProgramsFromConfig(log, warnings, agentInfo, renderedCFG, isStandalone)
 	if err != nil {
 		return AgentConfig{}, err
 	}
@@ -526,6 +530,10 @@ func createZip(fileName, outputFormat string, diag DiagnosticsInfo, cfg AgentCon
 		return closeHandlers(err, zw, f)
 	}
 
+	if err := zipProgramOutput(zw, paths.ProgramOutputPath()); err != nil {
+		return closeHandlers(err, zw, f)
+	}
+
 	if pprof != nil {
 		err := zipProfs(zw, pprof)
 		if err != nil {
@@ -566,6 +574,10 @@ func zipLogs(zw *zip.Writer) error {
 		if name == "" {
 			return nil
 		}
+		// only the tail of the captured output is added, by zipProgramOutput
+		if filepath.Clean(path) == filepath.Clean(paths.ProgramOutputPath()) {
+			return filepath.SkipDir
+		}
 
 		if d.IsDir() {
 			_, err := zw.Create("logs/" + name + "/")
@@ -592,6 +604,79 @@ func zipLogs(zw *zip.Writer) error {
 	})
 }
 
+// zipProgramOutput copies the end of the files capturing the stdout and stderr of the programs into zw
+// in "output/".
+func zipProgramOutput(zw *zip.Writer, outputPath string) error {
+	outputPath = filepath.Clean(outputPath) + string(filepath.Separator)
+	return filepath.WalkDir(outputPath, func(path string, d fs.DirEntry, fErr error) error {
+		if stderrors.Is(fErr, fs.ErrNotExist) {
+			return nil
+		}
+		if fErr != nil {
+			return fmt.Errorf("unable to walk output dir: %w", fErr)
+		}
+
+		name := filepath.ToSlash(strings.TrimPrefix(path, outputPath))
+		if name == "" {
+			_, err := zw.Create("output/")
+			return err
+		}
+		if d.IsDir() {
+			_, err := zw.Create("output/" + name + "/")
+			if err != nil {
+				return fmt.Errorf("unable to create output directory in archive: %w", err)
+			}
+			return nil
+		}
+
+		of, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("unable to open output file: %w", err)
+		}
+		if err := seekTail(of, outputTailSize); err != nil {
+			return closeHandlers(fmt.Errorf("unable to read output file: %w", err), of)
+		}
+		zf, err := zw.Create("output/" + name)
+		if err != nil {
+			return closeHandlers(fmt.Errorf("unable to create output file in archive: %w", err), of)
+		}
+		_, err = io.Copy(zf, of)
+		if err != nil {
+			return closeHandlers(fmt.Errorf("output file copy failed: %w", err), of)
+		}
+
+		return of.Close()
+	})
+}
+
+// seekTail moves to the start of the last size bytes of the file, the first truncated line is skipped.
+func seekTail(f *os.File, size int64) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() <= size {
+		return nil
+	}
+	offset := info.Size() - size
+	buf := make([]byte, 4096)
+	for {
+		n, err := f.ReadAt(buf, offset)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			offset += int64(i) + 1
+			break
+		}
+		offset += int64(n)
+		if err != nil {
+			// no line in the tail, keep it whole
+			offset = info.Size() - size
+			break
+		}
+	}
+	_, err = f.Seek(offset, io.SeekStart)
+	return err
+}
+
 // writeFile writes json or yaml data from the interface to the writer.
 func writeFile(w io.Writer, outputFormat string, v interface{}) error {
 	if outputFormat == "json" {
@@ -5,9 +5,18 @@
 package cmd
 
 import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/elastic/elastic-agent/internal/pkg/agent/control/client"
 )
 
@@ -73,3 +82,39 @@ func Example_humanDiagnosticsOutput() {
 	//   *  name: metricbeat                      route_key: test
 	//      error: failed to get metricbeat data
 }
+
+func TestZipProgramOutput(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "default"), 0750))
+	long := strings.Repeat("truncated line\n", outputTailSize/15) + "panic: runtime error\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "default", "filebeat--8.2.0.stderr.log"), []byte(long), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "default", "filebeat--8.2.0.stdout.log"), []byte("out\n"), 0600))
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	require.NoError(t, zipProgramOutput(zw, dir))
+	require.NoError(t, zw.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		content, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		files[f.Name] = string(content)
+	}
+
+	stderr := files["output/default/filebeat--8.2.0.stderr.log"]
+	assert.LessOrEqual(t, len(stderr), outputTailSize)
+	assert.True(t, strings.HasPrefix(stderr, "truncated line\n"))
+	assert.True(t, strings.HasSuffix(stderr, "panic: runtime error\n"))
+	assert.Equal(t, "out\n", files["output/default/filebeat--8.2.0.stdout.log"])
+	assert.Contains(t, files, "output/default/")
+}
+
+func TestZipProgramOutputMissing(t *testing.T) {
+	zw := zip.NewWriter(ioutil.Discard)
+	assert.NoError(t, zipProgramOutput(zw, filepath.Join(t.TempDir(), "missing")))
+}
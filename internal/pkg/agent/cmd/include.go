@@ -7,13 +7,19 @@ package cmd
 import (
 	// include the composable providers
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/agent"
+	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/cloud"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/docker"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/env"
+	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/filedynamic"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/host"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/kubernetes"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/kubernetesleaderelection"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/kubernetessecrets"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/local"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/localdynamic"
+	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/network"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/path"
+	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/process"
+	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/secrets"
+	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/systemd"
 )
@@ -6,8 +6,13 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -19,13 +24,16 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/agent/application/pipeline"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/application/pipeline/emitter"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/application/pipeline/emitter/modifiers"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/configrequest"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/configuration"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/stateresolver"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
 	"github.com/elastic/elastic-agent/internal/pkg/capabilities"
 	"github.com/elastic/elastic-agent/internal/pkg/cli"
 	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/composable/providers/secrets"
 	"github.com/elastic/elastic-agent/internal/pkg/config"
 	"github.com/elastic/elastic-agent/internal/pkg/config/operations"
 	"github.com/elastic/elastic-agent/internal/pkg/core/monitoring/noop"
@@ -42,7 +50,7 @@ func newInspectCommandWithArgs(s []string, streams *cli.IOStreams) *cobra.Comman
 		Long:  "Shows current configuration of the agent",
 		Args:  cobra.ExactArgs(0),
 		Run: func(c *cobra.Command, args []string) {
-			if err := inspectConfig(paths.ConfigFile()); err != nil {
+			if err := inspectConfig(streams, paths.ConfigFile()); err != nil {
 				fmt.Fprintf(streams.Err, "Error: %v\n%s\n", err, troubleshootMessage())
 				os.Exit(1)
 			}
@@ -50,6 +58,7 @@ func newInspectCommandWithArgs(s []string, streams *cli.IOStreams) *cobra.Comman
 	}
 
 	cmd.AddCommand(newInspectOutputCommandWithArgs(s, streams))
+	cmd.AddCommand(newInspectDiffCommandWithArgs(s, streams))
 
 	return cmd
 }
@@ -63,6 +72,7 @@ func newInspectOutputCommandWithArgs(_ []string, streams *cli.IOStreams) *cobra.
 		RunE: func(c *cobra.Command, args []string) error {
 			outName, _ := c.Flags().GetString("output")
 			program, _ := c.Flags().GetString("program")
+			trace, _ := c.Flags().GetBool("trace")
 			cfgPath := paths.ConfigFile()
 			agentInfo, err := info.NewAgentInfo(false)
 			if err != nil {
@@ -70,20 +80,46 @@ func newInspectOutputCommandWithArgs(_ []string, streams *cli.IOStreams) *cobra.
 			}
 
 			if outName == "" {
-				return inspectOutputs(cfgPath, agentInfo)
+				return inspectOutputs(streams, cfgPath, agentInfo)
 			}
 
-			return inspectOutput(cfgPath, outName, program, agentInfo)
+			return inspectOutput(streams, cfgPath, outName, program, trace, agentInfo)
 		},
 	}
 
 	cmd.Flags().StringP("output", "o", "", "name of the output to be inspected")
 	cmd.Flags().StringP("program", "p", "", "type of program to inspect, needs to be combined with output. e.g filebeat")
+	cmd.Flags().Bool("trace", false, "display the changes made by every rule of the program spec, needs to be combined with output")
 
 	return cmd
 }
 
-func inspectConfig(cfgPath string) error {
+func newInspectDiffCommandWithArgs(_ []string, streams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <policy>",
+		Short: "Displays changes a policy would apply to the running programs",
+		Long:  "Displays changes a policy would apply to the running programs.\nFor every output and program the rendered configuration diff and the steps the operator would execute are displayed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			outName, _ := c.Flags().GetString("output")
+			program, _ := c.Flags().GetString("program")
+			cfgPath := paths.ConfigFile()
+			agentInfo, err := info.NewAgentInfo(false)
+			if err != nil {
+				return err
+			}
+
+			return inspectDiff(streams, cfgPath, args[0], outName, program, agentInfo)
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "name of the output to be inspected")
+	cmd.Flags().StringP("program", "p", "", "type of program to inspect. e.g filebeat")
+
+	return cmd
+}
+
+func inspectConfig(streams *cli.IOStreams, cfgPath string) error {
 	err := tryContainerLoadPaths()
 	if err != nil {
 		return err
@@ -94,7 +130,12 @@ func inspectConfig(cfgPath string) error {
 		return err
 	}
 
-	return printConfig(fullCfg)
+	if err := printConfig(fullCfg); err != nil {
+		return err
+	}
+
+	// vars are only known to the running agent, variables are checked by `inspect output`.
+	return printConditionWarnings(streams.Err, fullCfg, nil)
 }
 
 func printMapStringConfig(mapStr map[string]interface{}) error {
@@ -138,7 +179,7 @@ func newErrorLogger() (*logger.Logger, error) {
 	return logger.NewWithLogpLevel("", logp.ErrorLevel, false)
 }
 
-func inspectOutputs(cfgPath string, agentInfo *info.AgentInfo) error {
+func inspectOutputs(streams *cli.IOStreams, cfgPath string, agentInfo *info.AgentInfo) error {
 	l, err := newErrorLogger()
 	if err != nil {
 		return err
@@ -159,11 +200,11 @@ func inspectOutputs(cfgPath string, agentInfo *info.AgentInfo) error {
 		return err
 	}
 
-	return listOutputsFromMap(l, agentInfo, fleetConfig, isStandalone)
+	return listOutputsFromMap(l, streams.Err, agentInfo, fleetConfig, isStandalone)
 }
 
-func listOutputsFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, cfg *config.Config, isStandalone bool) error {
-	programsGroup, err := getProgramsFromConfig(log, agentInfo, cfg, isStandalone)
+func listOutputsFromConfig(log *logger.Logger, warnings io.Writer, agentInfo *info.AgentInfo, cfg *config.Config, isStandalone bool) error {
+	programsGroup, err := getProgramsFromConfig(log, warnings, agentInfo, cfg, isStandalone)
 	if err != nil {
 		return err
 
@@ -176,16 +217,16 @@ func listOutputsFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, cfg *c
 	return nil
 }
 
-func listOutputsFromMap(log *logger.Logger, agentInfo *info.AgentInfo, cfg map[string]interface{}, isStandalone bool) error {
+func listOutputsFromMap(log *logger.Logger, warnings io.Writer, agentInfo *info.AgentInfo, cfg map[string]interface{}, isStandalone bool) error {
 	c, err := config.NewConfigFrom(cfg)
 	if err != nil {
 		return err
 	}
 
-	return listOutputsFromConfig(log, agentInfo, c, isStandalone)
+	return listOutputsFromConfig(log, warnings, agentInfo, c, isStandalone)
 }
 
-func inspectOutput(cfgPath, output, program string, agentInfo *info.AgentInfo) error {
+func inspectOutput(streams *cli.IOStreams, cfgPath, output, program string, trace bool, agentInfo *info.AgentInfo) error {
 	l, err := newErrorLogger()
 	if err != nil {
 		return err
@@ -201,11 +242,11 @@ func inspectOutput(cfgPath, output, program string, agentInfo *info.AgentInfo) e
 		return err
 	}
 
-	return printOutputFromMap(l, agentInfo, output, program, fleetConfig, true)
+	return printOutputFromMap(l, streams.Err, agentInfo, output, program, trace, fleetConfig, true)
 }
 
-func printOutputFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, output, programName string, cfg *config.Config, isStandalone bool) error {
-	programsGroup, err := getProgramsFromConfig(log, agentInfo, cfg, isStandalone)
+func printOutputFromConfig(log *logger.Logger, warnings io.Writer, agentInfo *info.AgentInfo, output, programName string, trace bool, cfg *config.Config, isStandalone bool) error {
+	programsGroup, ast, err := getProgramsAndASTFromConfig(log, warnings, agentInfo, cfg, isStandalone)
 	if err != nil {
 		return err
 
@@ -225,6 +266,11 @@ func printOutputFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, output
 			programFound = true
 			fmt.Printf("[%s] %s:\n", k, p.Spec.Cmd)
 			printMapStringConfig(p.Configuration())
+			if trace {
+				if err := printProgramTrace(agentInfo, ast, k, p.Spec); err != nil {
+					return err
+				}
+			}
 			fmt.Println("---")
 		}
 
@@ -241,35 +287,287 @@ func printOutputFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, output
 
 }
 
-func printOutputFromMap(log *logger.Logger, agentInfo *info.AgentInfo, output, programName string, cfg map[string]interface{}, isStandalone bool) error {
+func printOutputFromMap(log *logger.Logger, warnings io.Writer, agentInfo *info.AgentInfo, output, programName string, trace bool, cfg map[string]interface{}, isStandalone bool) error {
 	c, err := config.NewConfigFrom(cfg)
 	if err != nil {
 		return err
 	}
 
-	return printOutputFromConfig(log, agentInfo, output, programName, c, isStandalone)
+	return printOutputFromConfig(log, warnings, agentInfo, output, programName, trace, c, isStandalone)
+}
+
+func printProgramTrace(agentInfo *info.AgentInfo, ast *transpiler.AST, output string, spec program.Spec) error {
+	if ast == nil || spec.Rules == nil {
+		// programs not generated from the spec rules, e.g fleet-server
+		fmt.Println("no rules applied")
+		return nil
+	}
+
+	trace, err := program.TraceProgram(agentInfo, ast, output, spec)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return errors.New(err, "could not marshal trace to JSON")
+	}
+
+	fmt.Println("trace:")
+	fmt.Println(string(data))
+	return nil
+}
+
+func inspectDiff(streams *cli.IOStreams, cfgPath, policyPath, output, programName string, agentInfo *info.AgentInfo) error {
+	l, err := newErrorLogger()
+	if err != nil {
+		return err
+	}
+
+	fullCfg, err := operations.LoadFullAgentConfig(cfgPath, true)
+	if err != nil {
+		return err
+	}
+
+	isStandalone, err := isStandalone(fullCfg)
+	if err != nil {
+		return err
+	}
+
+	candidateCfg, err := operations.LoadCandidateAgentConfig(cfgPath, policyPath)
+	if err != nil {
+		return errors.New(err,
+			fmt.Sprintf("could not read policy %s", policyPath),
+			errors.TypeFilesystem,
+			errors.M(errors.MetaKeyPath, policyPath))
+	}
+
+	current, err := getProgramsFromConfig(l, streams.Err, agentInfo, fullCfg, isStandalone)
+	if err != nil {
+		return err
+	}
+
+	candidate, err := getProgramsFromConfig(l, streams.Err, agentInfo, candidateCfg, isStandalone)
+	if err != nil {
+		return err
+	}
+
+	return printProgramsDiff(streams.Out, l, current, candidate, output, programName)
+}
+
+// printProgramsDiff prints for each output the configuration diff of every program and the steps
+// the operator would execute to move from the current programs to the candidate programs.
+func printProgramsDiff(w io.Writer, log *logger.Logger, current, candidate map[string][]program.Program, output, programName string) error {
+	outputs := make(map[string]bool)
+	for k := range current {
+		outputs[k] = true
+	}
+	for k := range candidate {
+		outputs[k] = true
+	}
+
+	if output != "" && !outputs[output] {
+		return fmt.Errorf("output '%s' is not recognized, try running `elastic-agent inspect output` to find available outputs", output)
+	}
+
+	keys := make([]string, 0, len(outputs))
+	for k := range outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if output != "" && k != output {
+			continue
+		}
+
+		steps, err := resolveSteps(log, current[k], candidate[k])
+		if err != nil {
+			return err
+		}
+
+		for _, name := range programNames(current[k], candidate[k]) {
+			if programName != "" && programName != name {
+				continue
+			}
+
+			fmt.Fprintf(w, "[%s] %s:\n", k, name)
+			diff, err := configDiff(programConfig(current[k], name), programConfig(candidate[k], name))
+			if err != nil {
+				return err
+			}
+			if diff == "" {
+				fmt.Fprintln(w, "no configuration changes")
+			} else {
+				fmt.Fprintf(w, "configuration changes (-current +candidate):\n%s", diff)
+			}
+
+			var found bool
+			for _, step := range steps {
+				if step.ProgramSpec.Cmd != name {
+					continue
+				}
+				found = true
+				fmt.Fprintf(w, "step: %s\n", step.String())
+			}
+			if !found {
+				fmt.Fprintln(w, "no steps")
+			}
+			fmt.Fprintln(w, "---")
+		}
+	}
+
+	return nil
+}
+
+// resolveSteps converges the current programs and then the candidate programs through a state resolver,
+// returning the steps needed to go from the first to the latter.
+func resolveSteps(log *logger.Logger, current, candidate []program.Program) ([]configrequest.Step, error) {
+	resolver, err := stateresolver.NewStateResolver(log)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, _, ack, err := resolver.Resolve(configrequest.New("current", time.Now(), current))
+	if err != nil {
+		return nil, err
+	}
+	ack()
+
+	_, _, steps, _, err := resolver.Resolve(configrequest.New("candidate", time.Now(), candidate))
+	if err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+// configDiff returns a line based diff of the YAML representation of both configurations,
+// an empty string is returned when both are identical.
+func configDiff(current, candidate map[string]interface{}) (string, error) {
+	toLines := func(cfg map[string]interface{}) ([]string, error) {
+		if cfg == nil {
+			return nil, nil
+		}
+		data, err := yaml.Marshal(cfg)
+		if err != nil {
+			return nil, errors.New(err, "could not marshal to YAML")
+		}
+		return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+	}
+
+	a, err := toLines(current)
+	if err != nil {
+		return "", err
+	}
+	b, err := toLines(candidate)
+	if err != nil {
+		return "", err
+	}
+
+	// longest common subsequence of lines, configurations are small enough for the quadratic table.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var changed bool
+	var str strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			str.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			str.WriteString("- " + a[i] + "\n")
+			changed = true
+			i++
+		default:
+			str.WriteString("+ " + b[j] + "\n")
+			changed = true
+			j++
+		}
+	}
+
+	if !changed {
+		return "", nil
+	}
+	return str.String(), nil
 }
 
-func getProgramsFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, cfg *config.Config, isStandalone bool) (map[string][]program.Program, error) {
+func programNames(lists ...[]program.Program) []string {
+	set := make(map[string]bool)
+	for _, programs := range lists {
+		for _, p := range programs {
+			set[p.Spec.Cmd] = true
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func programConfig(programs []program.Program, name string) map[string]interface{} {
+	for _, p := range programs {
+		if p.Spec.Cmd == name {
+			return p.Configuration()
+		}
+	}
+	return nil
+}
+
+func getProgramsFromConfig(log *logger.Logger, warnings io.Writer, agentInfo *info.AgentInfo, cfg *config.Config, isStandalone bool) (map[string][]program.Program, error) {
+	programs, _, err := getProgramsAndASTFromConfig(log, warnings, agentInfo, cfg, isStandalone)
+	return programs, err
+}
+
+// getProgramsAndASTFromConfig returns the programs generated from the configuration and the rendered
+// configuration they are generated from.
+func getProgramsAndASTFromConfig(log *logger.Logger, warnings io.Writer, agentInfo *info.AgentInfo, cfg *config.Config, isStandalone bool) (map[string][]program.Program, *transpiler.AST, error) {
+	if err := program.LoadRules(paths.AgentRulesPath()); err != nil {
+		return nil, nil, err
+	}
+	if err := loadExternalSpecs(cfg); err != nil {
+		return nil, nil, err
+	}
+
 	monitor := noop.NewMonitor()
 	router := &inmemRouter{}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	composableCtrl, err := composable.New(log, cfg)
+	composableCtrl, err := composable.New(log, cfg, status.NewController(log))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	composableWaiter := newWaitForCompose(composableCtrl)
 	configModifiers := &pipeline.ConfigModifiers{
-		Decorators: []pipeline.DecoratorFunc{modifiers.InjectMonitoring},
+		Decorators: []pipeline.DecoratorFunc{modifiers.InjectMonitoring, router.captureAST},
 		Filters:    []pipeline.FilterFunc{filters.StreamChecker},
 	}
 
 	if !isStandalone {
 		sysInfo, err := sysinfo.Host()
 		if err != nil {
-			return nil, errors.New(err,
+			return nil, nil, errors.New(err,
 				"fail to get system information",
 				errors.TypeUnexpected)
 		}
@@ -278,7 +576,7 @@ func getProgramsFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, cfg *c
 
 	caps, err := capabilities.Load(paths.AgentCapabilitiesPath(), log, status.NewController(log))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	emit, err := emitter.New(
@@ -292,24 +590,27 @@ func getProgramsFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, cfg *c
 		monitor,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := emit(ctx, cfg); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	vars := composableWaiter.Wait()
+	if err := printConditionWarnings(warnings, cfg, vars); err != nil {
+		return nil, nil, err
 	}
-	composableWaiter.Wait()
 
 	// add the fleet-server input to default programs list
 	// this does not correspond to the actual config that fleet-server uses as it's in fleet.yml and not part of the assembled config (cfg)
 	fleetCFG, err := cfg.ToMapStr()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if fleetInput := getFleetInput(fleetCFG); fleetInput != nil {
 		ast, err := transpiler.NewAST(fleetInput)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		router.programs["default"] = append(router.programs["default"], program.Program{
 			Spec: program.Spec{
@@ -320,7 +621,42 @@ func getProgramsFromConfig(log *logger.Logger, agentInfo *info.AgentInfo, cfg *c
 		})
 	}
 
-	return router.programs, nil
+	return redactPrograms(router.programs, router.ast)
+}
+
+// redactPrograms replaces the secrets resolved while rendering the configuration, the programs
+// generated here are only displayed or written in diagnostics.
+func redactPrograms(programsGroup map[string][]program.Program, ast *transpiler.AST) (map[string][]program.Program, *transpiler.AST, error) {
+	redactAST := func(ast *transpiler.AST) (*transpiler.AST, error) {
+		m, err := ast.Map()
+		if err != nil {
+			return nil, err
+		}
+		redacted, _ := secrets.Redact(m).(map[string]interface{})
+		return transpiler.NewAST(redacted)
+	}
+
+	for _, programs := range programsGroup {
+		for i, p := range programs {
+			if p.Config == nil {
+				continue
+			}
+			cfg, err := redactAST(p.Config)
+			if err != nil {
+				return nil, nil, err
+			}
+			programs[i].Config = cfg
+		}
+	}
+
+	if ast == nil {
+		return programsGroup, nil, nil
+	}
+	redacted, err := redactAST(ast)
+	if err != nil {
+		return nil, nil, err
+	}
+	return programsGroup, redacted, nil
 }
 
 func getFleetInput(o map[string]interface{}) map[string]interface{} {
@@ -346,6 +682,13 @@ func getFleetInput(o map[string]interface{}) map[string]interface{} {
 
 type inmemRouter struct {
 	programs map[string][]program.Program
+	ast      *transpiler.AST
+}
+
+// captureAST is a decorator keeping the rendered configuration the programs are generated from.
+func (r *inmemRouter) captureAST(_ *info.AgentInfo, _ string, ast *transpiler.AST, programs []program.Program) ([]program.Program, error) {
+	r.ast = ast
+	return programs, nil
 }
 
 func (r *inmemRouter) Routes() *sorted.Set {
@@ -361,26 +704,76 @@ func (r *inmemRouter) Shutdown() {}
 
 type waitForCompose struct {
 	controller composable.Controller
-	done       chan bool
+	done       chan []*transpiler.Vars
 }
 
 func newWaitForCompose(wrapped composable.Controller) *waitForCompose {
 	return &waitForCompose{
 		controller: wrapped,
-		done:       make(chan bool),
+		done:       make(chan []*transpiler.Vars),
 	}
 }
 
 func (w *waitForCompose) Run(ctx context.Context, cb composable.VarsCallback) error {
 	err := w.controller.Run(ctx, func(vars []*transpiler.Vars) {
 		cb(vars)
-		w.done <- true
+		w.done <- vars
 	})
 	return err
 }
 
-func (w *waitForCompose) Wait() {
-	<-w.done
+func (w *waitForCompose) Snapshot() composable.Snapshot {
+	return w.controller.Snapshot()
+}
+
+// Wait waits for the vars of the providers and returns them.
+func (w *waitForCompose) Wait() []*transpiler.Vars {
+	return <-w.done
+}
+
+// printConditionWarnings prints the problems found in the conditions of the configuration to w,
+// variables are only checked when vars are given.
+func printConditionWarnings(w io.Writer, cfg *config.Config, vars []*transpiler.Vars) error {
+	m, err := cfg.ToMapStr()
+	if err != nil {
+		return err
+	}
+
+	ast, err := transpiler.NewAST(m)
+	if err != nil {
+		return errors.New(err, "could not create the AST from the configuration", errors.TypeConfig)
+	}
+
+	for _, warning := range transpiler.AnalyzeConditions(ast, vars) {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+	return nil
+}
+
+// loadExternalSpecs loads the program specs from the specs directory when enabled, so the inspected
+// programs match the ones run by the agent.
+func loadExternalSpecs(cfg *config.Config) error {
+	c, err := configuration.NewFromConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	specsCfg := c.Settings.Specs
+	if specsCfg == nil || !specsCfg.Enabled {
+		return nil
+	}
+
+	dir := specsCfg.Path
+	if dir == "" {
+		dir = paths.AgentSpecsPath()
+	}
+
+	specs, err := program.LoadSpecs(dir)
+	if err != nil {
+		return err
+	}
+	program.SetExternalSpecs(specs)
+	return nil
 }
 
 func isStandalone(cfg *config.Config) (bool, error) {
@@ -5,7 +5,17 @@
 package cmd
 
 import (
+	"bytes"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/configrequest"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
 )
 
 func TestGetFleetInput(t *testing.T) {
@@ -49,3 +59,82 @@ func TestGetFleetInput(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintProgramsDiff(t *testing.T) {
+	log, err := logger.New("", false)
+	require.NoError(t, err)
+
+	fb := func(path string) program.Program {
+		spec, ok := program.FindSpecByName("Filebeat")
+		require.True(t, ok)
+		return program.Program{
+			Spec: spec,
+			Config: transpiler.MustNewAST(map[string]interface{}{
+				"filebeat": map[string]interface{}{
+					"path": path,
+				},
+			}),
+		}
+	}
+	mb := func() program.Program {
+		spec, ok := program.FindSpecByName("Metricbeat")
+		require.True(t, ok)
+		return program.Program{
+			Spec: spec,
+			Config: transpiler.MustNewAST(map[string]interface{}{
+				"metricbeat": map[string]interface{}{},
+			}),
+		}
+	}
+
+	current := map[string][]program.Program{
+		"default": {fb("/var/log/a.log"), mb()},
+	}
+	candidate := map[string][]program.Program{
+		"default": {fb("/var/log/b.log")},
+	}
+
+	t.Run("changed and removed programs", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, printProgramsDiff(&b, log, current, candidate, "", ""))
+
+		out := b.String()
+		assert.Contains(t, out, "[default] filebeat:")
+		assert.Contains(t, out, "-   path: /var/log/a.log\n+   path: /var/log/b.log\n")
+		assert.Contains(t, out, "step: [ID:"+configrequest.StepRun+", PROCESS: filebeat")
+		assert.Contains(t, out, "[default] metricbeat:")
+		assert.Contains(t, out, "step: [ID:"+configrequest.StepRemove+", PROCESS: metricbeat")
+	})
+
+	t.Run("unchanged programs", func(t *testing.T) {
+		var b bytes.Buffer
+		require.NoError(t, printProgramsDiff(&b, log, current, current, "", "filebeat"))
+
+		out := b.String()
+		assert.Contains(t, out, "no configuration changes")
+		assert.Contains(t, out, "no steps")
+		assert.NotContains(t, out, "metricbeat")
+	})
+
+	t.Run("unknown output", func(t *testing.T) {
+		var b bytes.Buffer
+		require.Error(t, printProgramsDiff(&b, log, current, candidate, "missing", ""))
+	})
+}
+
+func TestPrintConditionWarnings(t *testing.T) {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"inputs": []interface{}{
+			map[string]interface{}{
+				"type":      "logfile",
+				"condition": "${host.name} == 'mac' and unknown()",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, printConditionWarnings(&out, cfg, nil))
+	assert.Contains(t, out.String(), "Warning: ")
+	assert.Contains(t, out.String(), "call to unknown function unknown")
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/agent/configuration"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/control/server"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/storage"
 	"github.com/elastic/elastic-agent/internal/pkg/cli"
 	"github.com/elastic/elastic-agent/internal/pkg/config"
@@ -126,6 +127,11 @@ func run(streams *cli.IOStreams, override cfgOverrider) error {
 		logger.Error(errors.New(err, "failed to invoke rollback watcher"))
 	}
 
+	// built-in specs do not depend on user-defined rules, do not prevent the agent from running them
+	if err := program.LoadRules(paths.AgentRulesPath()); err != nil {
+		logger.Error(errors.New(err, "failed to load user-defined rules", errors.M(errors.MetaKeyPath, paths.AgentRulesPath())))
+	}
+
 	if allowEmptyPgp, _ := release.PGP(); allowEmptyPgp {
 		logger.Info("Artifact has been built with security disabled. Elastic Agent will not verify signatures of the artifacts.")
 	}
@@ -166,6 +172,7 @@ func run(streams *cli.IOStreams, override cfgOverrider) error {
 	}
 
 	control.SetRouteFn(app.Routes)
+	control.SetComposable(app.Composable())
 	control.SetMonitoringCfg(cfg.Settings.MonitoringConfig)
 
 	serverStopFn, err := setupMetrics(agentInfo, logger, cfg.Settings.DownloadConfig.OS(), cfg.Settings.MonitoringConfig, app, tracer)
@@ -0,0 +1,34 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-agent/internal/pkg/cli"
+	"github.com/elastic/elastic-agent/internal/pkg/core/process"
+)
+
+func newSandboxCommand(_ []string, streams *cli.IOStreams) *cobra.Command {
+	return &cobra.Command{
+		Hidden: true,
+		Use:    process.SandboxCommand + " -- <path> [args...]",
+		Short:  "Execute a program in its sandbox",
+		Long:   "This applies the sandbox of a program started by the agent, then replaces itself with the program.",
+		// arguments belong to the program
+		DisableFlagParsing: true,
+		Run: func(c *cobra.Command, args []string) {
+			if len(args) > 0 && args[0] == "--" {
+				args = args[1:]
+			}
+			err := process.ExecSandboxed(args)
+			fmt.Fprintf(streams.Err, "Error: %v\n", err)
+			os.Exit(1)
+		},
+	}
+}
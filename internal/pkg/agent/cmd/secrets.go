@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/elastic-agent/internal/pkg/cli"
+	"github.com/elastic/elastic-agent/internal/pkg/composable/providers/secrets"
+)
+
+func newSecretsCommandWithArgs(_ []string, streams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage the secrets of the vault backend of the secrets provider",
+		Long: `Secrets manages the encrypted vault read by the vault backend of the secrets provider, the
+running Elastic Agent reads the vault again once it changes.`,
+	}
+
+	cmd.PersistentFlags().String("vault", "", "Path of the vault (default: <config>/secrets.vault)")
+	cmd.PersistentFlags().String("password-file", "", "File containing the password of the vault")
+	_ = cmd.MarkPersistentFlagRequired("password-file")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "set <name>",
+		Short: "Set a secret in the vault, the value is read from stdin",
+		Long: `Set adds or replaces a secret in the vault, the vault is created when it does not exist. The
+value is read from stdin so it is not kept in the shell history, a trailing newline is removed.`,
+		Example: `printf '%s' "$API_KEY" | elastic-agent secrets set es_api_key --password-file /etc/elastic-agent/secrets.password`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			if err := secretsSetCmd(streams, c, args[0]); err != nil {
+				fmt.Fprintf(streams.Err, "Error: %v\n%s\n", err, troubleshootMessage())
+				os.Exit(1)
+			}
+		},
+	})
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a secret from the vault",
+		Args:  cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			if err := secretsRemoveCmd(streams, c, args[0]); err != nil {
+				fmt.Fprintf(streams.Err, "Error: %v\n%s\n", err, troubleshootMessage())
+				os.Exit(1)
+			}
+		},
+	})
+
+	return cmd
+}
+
+func secretsSetCmd(streams *cli.IOStreams, cmd *cobra.Command, name string) error {
+	value, err := readSecretValue(streams.In)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("empty value for secret '%s', use remove to remove it", name)
+	}
+	if err := setVaultSecrets(cmd, map[string]string{name: value}); err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out, "Secret '%s' set\n", name)
+	return nil
+}
+
+func secretsRemoveCmd(streams *cli.IOStreams, cmd *cobra.Command, name string) error {
+	if err := setVaultSecrets(cmd, map[string]string{name: ""}); err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out, "Secret '%s' removed\n", name)
+	return nil
+}
+
+func setVaultSecrets(cmd *cobra.Command, values map[string]string) error {
+	if err := tryContainerLoadPaths(); err != nil {
+		return err
+	}
+
+	vault, _ := cmd.Flags().GetString("vault")
+	passwordFile, _ := cmd.Flags().GetString("password-file")
+	if vault == "" {
+		var cfg secrets.Config
+		cfg.InitDefaults()
+		vault = cfg.Vault.Path
+	}
+	return secrets.SetVaultSecrets(vault, passwordFile, values)
+}
+
+func readSecretValue(r io.Reader) (string, error) {
+	value, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("could not read the value of the secret: %w", err)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(value), "\n"), "\r"), nil
+}
@@ -0,0 +1,56 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/cli"
+	"github.com/elastic/elastic-agent/internal/pkg/composable/providers/secrets"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	corecomp "github.com/elastic/elastic-agent/internal/pkg/core/composable"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestSecretsCommand(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	vaultFile := filepath.Join(dir, "secrets.vault")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("vault_password\n"), 0600))
+
+	run := func(stdin string, args ...string) string {
+		streams, in, out, _ := cli.NewTestingIOStreams()
+		in.WriteString(stdin)
+		cmd := newSecretsCommandWithArgs(nil, streams)
+		cmd.SetArgs(append(args, "--vault", vaultFile, "--password-file", passwordFile))
+		require.NoError(t, cmd.Execute())
+		return out.String()
+	}
+	assert.Equal(t, "Secret 'api_key' set\n", run("from_stdin\n", "set", "api_key"))
+	assert.Equal(t, "Secret 'other' set\n", run("value", "set", "other"))
+	assert.Equal(t, "Secret 'other' removed\n", run("", "remove", "other"))
+
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"backend": "vault",
+		"vault":   map[string]interface{}{"path": vaultFile, "password_file": passwordFile},
+	})
+	require.NoError(t, err)
+	log, err := logger.New("secrets", false)
+	require.NoError(t, err)
+	p, err := secrets.ContextProviderBuilder(log, cfg)
+	require.NoError(t, err)
+	fp := p.(corecomp.FetchContextProvider)
+
+	value, found := fp.Fetch("secrets.api_key")
+	assert.True(t, found)
+	assert.Equal(t, "from_stdin", value)
+	_, found = fp.Fetch("secrets.other")
+	assert.False(t, found)
+}
@@ -107,7 +107,11 @@ func outputStatus(w io.Writer, status *client.AgentStatus) error {
 		fmt.Fprint(w, "Applications:\n")
 		tw := tabwriter.NewWriter(w, 4, 1, 2, ' ', 0)
 		for _, app := range status.Applications {
-			fmt.Fprintf(tw, "  * %s\t(%s)\n", app.Name, app.Status)
+			if restarts := appRestarts(app); restarts > 0 {
+				fmt.Fprintf(tw, "  * %s\t(%s, restarts: %d)\n", app.Name, app.Status, restarts)
+			} else {
+				fmt.Fprintf(tw, "  * %s\t(%s)\n", app.Name, app.Status)
+			}
 			if app.Message == "" {
 				fmt.Fprint(tw, "\t(no message)\n")
 			} else {
@@ -119,6 +123,15 @@ func outputStatus(w io.Writer, status *client.AgentStatus) error {
 	return nil
 }
 
+// appRestarts returns the number of times the application was restarted, reported in its payload.
+func appRestarts(app *client.ApplicationStatus) int {
+	// numbers of the payload are decoded from JSON
+	if restarts, ok := app.Payload["restarts"].(float64); ok {
+		return int(restarts)
+	}
+	return 0
+}
+
 func jsonOutput(w io.Writer, out interface{}) error {
 	bytes, err := json.MarshalIndent(out, "", "    ")
 	if err != nil {
@@ -57,6 +57,34 @@ func ExamplehumanStatusOutput() {
 	//                            Running
 }
 
+func ExamplehumanStatusOutput_restarts() {
+	humanStatusOutput(os.Stdout, &client.AgentStatus{
+		Status:  client.Degraded,
+		Message: "",
+		Applications: []*client.ApplicationStatus{{
+			ID:      "id_1",
+			Name:    "filebeat",
+			Status:  client.Failed,
+			Message: "exited with code: 1, crash looping, restarted 10 times within 10m0s",
+			Payload: map[string]interface{}{"restarts": float64(10)},
+		}, {
+			ID:      "id_2",
+			Name:    "metricbeat",
+			Status:  client.Healthy,
+			Message: "Running",
+			Payload: map[string]interface{}{"restarts": float64(1)},
+		}},
+	})
+	// Output:
+	// Status: DEGRADED
+	// Message: (no message)
+	// Applications:
+	//   * filebeat    (FAILED, restarts: 10)
+	//                 exited with code: 1, crash looping, restarted 10 times within 10m0s
+	//   * metricbeat  (HEALTHY, restarts: 1)
+	//                 Running
+}
+
 func ExamplejsonOutput() {
 	jsonOutput(os.Stdout, testStatus)
 	// Output:
@@ -0,0 +1,182 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/control/client"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/cli"
+)
+
+var varsOutputs = map[string]outputter{
+	"human": humanVarsOutput,
+	"json":  jsonOutput,
+	"yaml":  yamlOutput,
+}
+
+func newVarsCommandWithArgs(_ []string, streams *cli.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "vars",
+		Short: "Show the variables of the composable providers of the running Elastic Agent daemon",
+		Long: `Vars shows the current output of every context provider and every dynamic provider mapping of
+the running Elastic Agent daemon, with the processors and the priority of the mappings.
+
+A variable expression or an EQL condition can be evaluated against every set of variables the
+configuration is rendered with.
+
+Secrets and the values of the environment variables are redacted, expressions and conditions are
+evaluated against the redacted values.`,
+		Example: `elastic-agent vars
+elastic-agent vars --expression '${docker.container.name}'
+elastic-agent vars --condition "\${host.platform} == 'linux'"`,
+		Args: cobra.NoArgs,
+		Run: func(c *cobra.Command, args []string) {
+			if err := varsCmd(streams, c); err != nil {
+				fmt.Fprintf(streams.Err, "Error: %v\n%s\n", err, troubleshootMessage())
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().String("expression", "", "Variable expression to evaluate against the variables")
+	cmd.Flags().String("condition", "", "EQL condition to evaluate against the variables")
+	cmd.Flags().String("output", "human", "Output the variables in either human, json, or yaml (default: human)")
+
+	return cmd
+}
+
+func varsCmd(streams *cli.IOStreams, cmd *cobra.Command) error {
+	err := tryContainerLoadPaths()
+	if err != nil {
+		return err
+	}
+
+	expression, _ := cmd.Flags().GetString("expression")
+	condition, _ := cmd.Flags().GetString("condition")
+	if expression != "" && condition != "" {
+		return fmt.Errorf("only one of --expression or --condition can be used")
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	outputFunc, ok := varsOutputs[output]
+	if !ok {
+		return fmt.Errorf("unsupported output: %s", output)
+	}
+
+	ctx := handleSignal(context.Background())
+	innerCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	vars, err := getDaemonVars(innerCtx, expression, condition)
+	if err == context.DeadlineExceeded {
+		return errors.New("timed out after 30 seconds trying to connect to Elastic Agent daemon")
+	} else if err == context.Canceled {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to communicate with Elastic Agent daemon: %s", err)
+	}
+
+	return outputFunc(streams.Out, vars)
+}
+
+func getDaemonVars(ctx context.Context, expression, condition string) (*client.Vars, error) {
+	daemon := client.New()
+	err := daemon.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer daemon.Disconnect()
+	return daemon.Vars(ctx, expression, condition)
+}
+
+func humanVarsOutput(w io.Writer, obj interface{}) error {
+	vars, ok := obj.(*client.Vars)
+	if !ok {
+		return fmt.Errorf("unable to cast %T as *client.Vars", obj)
+	}
+
+	tw := tabwriter.NewWriter(w, 4, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "Context providers:")
+	names := make([]string, 0, len(vars.Context))
+	for name := range vars.Context {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if len(vars.Context[name]) == 0 {
+			fmt.Fprintf(tw, "  %s\t(empty)\n", name)
+			continue
+		}
+		writeFlattened(tw, "  ", common.MapStr{name: vars.Context[name]})
+	}
+
+	if len(vars.Dynamic) == 0 {
+		fmt.Fprintln(tw, "Dynamic providers: (none)")
+	} else {
+		fmt.Fprintln(tw, "Dynamic providers:")
+		for _, d := range vars.Dynamic {
+			fmt.Fprintf(tw, "  * %s\t(id: %s, priority: %d)\n", d.Provider, d.ID, d.Priority)
+			writeFlattened(tw, "      ", common.MapStr{d.Provider: d.Mapping})
+			if len(d.Processors) > 0 {
+				processors, err := json.Marshal(d.Processors)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(tw, "      processors\t%s\n", processors)
+			}
+		}
+	}
+
+	if len(vars.Evaluations) > 0 {
+		fmt.Fprintln(tw, "Evaluations:")
+		for _, e := range vars.Evaluations {
+			source := "context providers"
+			if e.Provider != "" {
+				source = fmt.Sprintf("%s (id: %s)", e.Provider, e.ID)
+			}
+			switch {
+			case e.Error != "":
+				fmt.Fprintf(tw, "  * %s\terror: %s\n", source, e.Error)
+			case !e.Matched:
+				fmt.Fprintf(tw, "  * %s\tnot matched\n", source)
+			case e.Value != nil:
+				value, err := json.Marshal(e.Value)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(tw, "  * %s\tmatched: %s\n", source, value)
+			default:
+				fmt.Fprintf(tw, "  * %s\tmatched\n", source)
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// writeFlattened writes the values of the mapping one per line, under their dotted keys.
+func writeFlattened(w io.Writer, indent string, m common.MapStr) {
+	flat := m.Flatten()
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s%s\t%v\n", indent, k, flat[k])
+	}
+}
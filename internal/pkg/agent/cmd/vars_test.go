@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/control/client"
+)
+
+func TestHumanVarsOutput(t *testing.T) {
+	vars := &client.Vars{
+		Context: map[string]map[string]interface{}{
+			"host": {"name": "agent-1", "platform": "linux"},
+			"env":  {},
+		},
+		Dynamic: []client.DynamicVars{
+			{
+				Provider: "docker",
+				ID:       "abc",
+				Priority: 0,
+				Mapping:  map[string]interface{}{"container": map[string]interface{}{"name": "nginx"}},
+				Processors: []map[string]interface{}{
+					{"add_fields": map[string]interface{}{"target": "container", "fields": map[string]interface{}{"id": "abc"}}},
+				},
+			},
+		},
+		Evaluations: []client.VarsEvaluation{
+			{Matched: false},
+			{Provider: "docker", ID: "abc", Matched: true, Value: "nginx"},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, humanVarsOutput(&b, vars))
+	expected := `Context providers:
+  env            (empty)
+  host.name      agent-1
+  host.platform  linux
+Dynamic providers:
+  * docker                   (id: abc, priority: 0)
+      docker.container.name  nginx
+      processors             [{"add_fields":{"fields":{"id":"abc"},"target":"container"}}]
+Evaluations:
+  * context providers  not matched
+  * docker (id: abc)   matched: "nginx"
+`
+	assert.Equal(t, expected, b.String())
+}
+
+func TestHumanVarsOutputNoDynamic(t *testing.T) {
+	vars := &client.Vars{
+		Context: map[string]map[string]interface{}{
+			"host": {"name": "agent-1"},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, humanVarsOutput(&b, vars))
+	expected := `Context providers:
+  host.name  agent-1
+Dynamic providers: (none)
+`
+	assert.Equal(t, expected, b.String())
+}
@@ -0,0 +1,17 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package configuration
+
+// CapabilitiesConfig defines how the capabilities file is watched for changes.
+type CapabilitiesConfig struct {
+	Reload *ReloadConfig `config:"reload" yaml:"reload" json:"reload"`
+}
+
+// DefaultCapabilitiesConfig creates a config reloading the capabilities file when it changes.
+func DefaultCapabilitiesConfig() *CapabilitiesConfig {
+	return &CapabilitiesConfig{
+		Reload: DefaultReloadConfig(),
+	}
+}
@@ -27,6 +27,15 @@ type SettingsConfig struct {
 	MonitoringConfig *monitoringCfg.MonitoringConfig `yaml:"monitoring" config:"monitoring" json:"monitoring"`
 	LoggingConfig    *logger.Config                  `yaml:"logging,omitempty" config:"logging,omitempty" json:"logging,omitempty"`
 
+	// DryRun makes the operator record operations into a journal instead of running processes.
+	DryRun bool `yaml:"dry_run" config:"dry_run" json:"dry_run"`
+
+	// Specs configures the loading of program specs at runtime.
+	Specs *SpecsConfig `yaml:"specs" config:"specs" json:"specs"`
+
+	// Capabilities configures the reloading of the capabilities file.
+	Capabilities *CapabilitiesConfig `yaml:"capabilities" config:"capabilities" json:"capabilities"`
+
 	// standalone config
 	Reload *ReloadConfig `config:"reload" yaml:"reload" json:"reload"`
 	Path   string        `config:"path" yaml:"path" json:"path"`
@@ -42,5 +51,7 @@ func DefaultSettingsConfig() *SettingsConfig {
 		MonitoringConfig: monitoringCfg.DefaultConfig(),
 		GRPC:             server.DefaultGRPCConfig(),
 		Reload:           DefaultReloadConfig(),
+		Specs:            DefaultSpecsConfig(),
+		Capabilities:     DefaultCapabilitiesConfig(),
 	}
 }
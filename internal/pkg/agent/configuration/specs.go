@@ -0,0 +1,23 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package configuration
+
+// SpecsConfig defines the loading of program specs from a directory, loaded specs are added to
+// the embedded specs and replace the embedded specs running the same command.
+type SpecsConfig struct {
+	Enabled bool `config:"enabled" yaml:"enabled" json:"enabled"`
+	// Path is the directory containing the `*.yml` specs, defaults to the `specs.d` directory of
+	// the data path.
+	Path   string        `config:"path" yaml:"path" json:"path"`
+	Reload *ReloadConfig `config:"reload" yaml:"reload" json:"reload"`
+}
+
+// DefaultSpecsConfig creates a config with loading of specs disabled.
+func DefaultSpecsConfig() *SpecsConfig {
+	return &SpecsConfig{
+		Enabled: false,
+		Reload:  DefaultReloadConfig(),
+	}
+}
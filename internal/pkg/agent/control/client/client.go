@@ -80,6 +80,43 @@ type ProcPProf struct {
 	Error    string
 }
 
+// JournalEntry is an operation recorded by an operator running in dry-run mode.
+type JournalEntry struct {
+	Time      time.Time
+	RouteKey  string
+	AppName   string
+	Version   string
+	Operation string
+	Skipped   bool
+	Config    map[string]interface{}
+}
+
+// Vars is the current output of the composable providers.
+type Vars struct {
+	Context     map[string]map[string]interface{} `json:"context" yaml:"context"`
+	Dynamic     []DynamicVars                     `json:"dynamic" yaml:"dynamic"`
+	Evaluations []VarsEvaluation                  `json:"evaluations,omitempty" yaml:"evaluations,omitempty"`
+}
+
+// DynamicVars is a mapping of a dynamic provider.
+type DynamicVars struct {
+	Provider   string                   `json:"provider" yaml:"provider"`
+	ID         string                   `json:"id" yaml:"id"`
+	Priority   int                      `json:"priority" yaml:"priority"`
+	Mapping    map[string]interface{}   `json:"mapping" yaml:"mapping"`
+	Processors []map[string]interface{} `json:"processors,omitempty" yaml:"processors,omitempty"`
+}
+
+// VarsEvaluation is the result of an evaluation against the set of variables built from a dynamic
+// provider mapping, or from the context providers only when Provider is empty.
+type VarsEvaluation struct {
+	Provider string      `json:"provider,omitempty" yaml:"provider,omitempty"`
+	ID       string      `json:"id,omitempty" yaml:"id,omitempty"`
+	Matched  bool        `json:"matched" yaml:"matched"`
+	Value    interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+	Error    string      `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
 // AgentStatus is the current status of the Elastic Agent.
 type AgentStatus struct {
 	Status       Status
@@ -107,6 +144,11 @@ type Client interface {
 	Pprof(ctx context.Context, d time.Duration, pprofTypes []proto.PprofOption, appName, routeKey string) (map[string][]ProcPProf, error)
 	// ProcMetrics gathers /buffer data and from the agent and each running process and returns the result.
 	ProcMetrics(ctx context.Context) (*proto.ProcMetricsResponse, error)
+	// Journal gathers the operations recorded by the operators running in dry-run mode.
+	Journal(ctx context.Context) ([]JournalEntry, error)
+	// Vars gathers the current output of the composable providers, the expression or the condition is
+	// evaluated against them when not empty.
+	Vars(ctx context.Context, expression, condition string) (*Vars, error)
 }
 
 // client manages the state and communication to the Elastic Agent.
@@ -290,3 +332,92 @@ func (c *client) Pprof(ctx context.Context, d time.Duration, pprofTypes []proto.
 func (c *client) ProcMetrics(ctx context.Context) (*proto.ProcMetricsResponse, error) {
 	return c.client.ProcMetrics(ctx, &proto.Empty{})
 }
+
+// Journal gathers the operations recorded by the operators running in dry-run mode.
+func (c *client) Journal(ctx context.Context) ([]JournalEntry, error) {
+	res, err := c.client.Journal(ctx, &proto.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]JournalEntry, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		t, err := time.Parse(control.TimeFormat(), e.Time)
+		if err != nil {
+			return nil, err
+		}
+
+		var cfg map[string]interface{}
+		if e.Config != "" {
+			if err := json.Unmarshal([]byte(e.Config), &cfg); err != nil {
+				return nil, err
+			}
+		}
+
+		entries = append(entries, JournalEntry{
+			Time:      t,
+			RouteKey:  e.RouteKey,
+			AppName:   e.AppName,
+			Version:   e.Version,
+			Operation: e.Operation,
+			Skipped:   e.Skipped,
+			Config:    cfg,
+		})
+	}
+	return entries, nil
+}
+
+// Vars gathers the current output of the composable providers, the expression or the condition is
+// evaluated against them when not empty.
+func (c *client) Vars(ctx context.Context, expression, condition string) (*Vars, error) {
+	res, err := c.client.Vars(ctx, &proto.VarsRequest{
+		Expression: expression,
+		Condition:  condition,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vars := &Vars{
+		Context: make(map[string]map[string]interface{}, len(res.Context)),
+		Dynamic: make([]DynamicVars, 0, len(res.Dynamic)),
+	}
+	for _, ctxVars := range res.Context {
+		var mapping map[string]interface{}
+		if err := json.Unmarshal([]byte(ctxVars.Mapping), &mapping); err != nil {
+			return nil, err
+		}
+		vars.Context[ctxVars.Name] = mapping
+	}
+	for _, d := range res.Dynamic {
+		dynamic := DynamicVars{
+			Provider: d.Provider,
+			ID:       d.Id,
+			Priority: int(d.Priority),
+		}
+		if err := json.Unmarshal([]byte(d.Mapping), &dynamic.Mapping); err != nil {
+			return nil, err
+		}
+		if d.Processors != "" {
+			if err := json.Unmarshal([]byte(d.Processors), &dynamic.Processors); err != nil {
+				return nil, err
+			}
+		}
+		vars.Dynamic = append(vars.Dynamic, dynamic)
+	}
+	for _, e := range res.Evaluations {
+		evaluation := VarsEvaluation{
+			Provider: e.Provider,
+			ID:       e.Id,
+			Matched:  e.Matched,
+			Error:    e.Error,
+		}
+		if e.Value != "" {
+			if err := json.Unmarshal([]byte(e.Value), &evaluation.Value); err != nil {
+				return nil, err
+			}
+		}
+		vars.Evaluations = append(vars.Evaluations, evaluation)
+	}
+	return vars, nil
+}
@@ -12,6 +12,7 @@ import (
 	"net"
 	"net/http"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -25,18 +26,26 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/agent/control"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/control/proto"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/operation"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/composable/providers/secrets"
 	"github.com/elastic/elastic-agent/internal/pkg/core/monitoring/beats"
 	monitoring "github.com/elastic/elastic-agent/internal/pkg/core/monitoring/beats"
 	monitoringCfg "github.com/elastic/elastic-agent/internal/pkg/core/monitoring/config"
 	"github.com/elastic/elastic-agent/internal/pkg/core/socket"
 	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/internal/pkg/eql"
 	"github.com/elastic/elastic-agent/internal/pkg/fleetapi"
 	"github.com/elastic/elastic-agent/internal/pkg/release"
 	"github.com/elastic/elastic-agent/internal/pkg/sorted"
 	"github.com/elastic/elastic-agent/pkg/core/logger"
 )
 
+// envProviderName is the name of the context provider of the environment variables.
+const envProviderName = "env"
+
 // Server is the daemon side of the control protocol.
 type Server struct {
 	logger        *logger.Logger
@@ -44,6 +53,7 @@ type Server struct {
 	statusCtrl    status.Controller
 	up            *upgrade.Upgrader
 	routeFn       func() *sorted.Set
+	composable    composable.Controller
 	monitoringCfg *monitoringCfg.MonitoringConfig
 	listener      net.Listener
 	server        *grpc.Server
@@ -55,6 +65,10 @@ type specer interface {
 	Specs() map[string]program.Spec
 }
 
+type journaler interface {
+	Journal() []operation.JournalEntry
+}
+
 type specInfo struct {
 	spec program.Spec
 	app  string
@@ -86,6 +100,13 @@ func (s *Server) SetRouteFn(routesFetchFn func() *sorted.Set) {
 	s.routeFn = routesFetchFn
 }
 
+// SetComposable sets the controller of the composable providers used by the running agent.
+func (s *Server) SetComposable(ctrl composable.Controller) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.composable = ctrl
+}
+
 // SetMonitoringCfg sets a reference to the monitoring config used by the running agent.
 // the controller references this config to find out if pprof is enabled for the agent or not
 func (s *Server) SetMonitoringCfg(cfg *monitoringCfg.MonitoringConfig) {
@@ -336,6 +357,142 @@ func (s *Server) ProcMetrics(ctx context.Context, _ *proto.Empty) (*proto.ProcMe
 	return resp, nil
 }
 
+// Journal returns the operations recorded by every operator running in dry-run mode.
+func (s *Server) Journal(_ context.Context, _ *proto.Empty) (*proto.JournalResponse, error) {
+	if s.routeFn == nil {
+		return nil, errors.New("route function is nil")
+	}
+
+	resp := &proto.JournalResponse{
+		Entries: []*proto.JournalEntry{},
+	}
+
+	routes := s.routeFn()
+	for _, rk := range routes.Keys() {
+		r, ok := routes.Get(rk)
+		if !ok {
+			continue
+		}
+		j, ok := r.(journaler)
+		if !ok {
+			s.logger.With("route_key", rk, "route", r).Warn("Unable to cast route as journaler.")
+			continue
+		}
+
+		for _, e := range j.Journal() {
+			entry := &proto.JournalEntry{
+				Time:      e.Time.Format(control.TimeFormat()),
+				RouteKey:  rk,
+				AppName:   e.App,
+				Version:   e.Version,
+				Operation: e.Operation,
+				Skipped:   e.Skipped,
+			}
+			if e.Config != nil {
+				cfg, err := json.Marshal(e.Config)
+				if err != nil {
+					return nil, errors.New(err, "failed to encode journal configuration")
+				}
+				entry.Config = string(cfg)
+			}
+			resp.Entries = append(resp.Entries, entry)
+		}
+	}
+
+	return resp, nil
+}
+
+// Vars returns the current output of the composable providers, the requested expression or condition
+// is evaluated against every set of variables built from them. Secrets and the values of the environment
+// are redacted, the expressions and the conditions only see the redacted environment so a condition cannot
+// be used to guess a value.
+func (s *Server) Vars(_ context.Context, req *proto.VarsRequest) (*proto.VarsResponse, error) {
+	s.lock.RLock()
+	ctrl := s.composable
+	s.lock.RUnlock()
+	if ctrl == nil {
+		return nil, errors.New("composable controller is not running")
+	}
+	if req.Expression != "" && req.Condition != "" {
+		return nil, errors.New("expression and condition cannot be evaluated at the same time")
+	}
+
+	snapshot := ctrl.Snapshot()
+	if env, ok := snapshot.Context[envProviderName]; ok {
+		snapshot.Context[envProviderName] = redactEnv(env)
+	}
+	resp := &proto.VarsResponse{
+		Context:     []*proto.VarsContext{},
+		Dynamic:     []*proto.VarsDynamic{},
+		Evaluations: []*proto.VarsEvaluation{},
+	}
+
+	contextNames := make([]string, 0, len(snapshot.Context))
+	for name := range snapshot.Context {
+		contextNames = append(contextNames, name)
+	}
+	sort.Strings(contextNames)
+	for _, name := range contextNames {
+		mapping, err := marshalRedacted(snapshot.Context[name])
+		if err != nil {
+			return nil, errors.New(err, fmt.Sprintf("failed to encode mapping of provider '%s'", name))
+		}
+		resp.Context = append(resp.Context, &proto.VarsContext{
+			Name:    name,
+			Mapping: mapping,
+		})
+	}
+
+	dynamicNames := make([]string, 0, len(snapshot.Dynamic))
+	for name := range snapshot.Dynamic {
+		dynamicNames = append(dynamicNames, name)
+	}
+	sort.Strings(dynamicNames)
+	for _, name := range dynamicNames {
+		for _, m := range snapshot.Dynamic[name] {
+			mapping, err := marshalRedacted(m.Mapping)
+			if err != nil {
+				return nil, errors.New(err, fmt.Sprintf("failed to encode mapping '%s' of provider '%s'", m.ID, name))
+			}
+			dynamic := &proto.VarsDynamic{
+				Provider: name,
+				Id:       m.ID,
+				Priority: int32(m.Priority),
+				Mapping:  mapping,
+			}
+			if m.Processors != nil {
+				dynamic.Processors, err = marshalRedacted(m.Processors)
+				if err != nil {
+					return nil, errors.New(err, fmt.Sprintf("failed to encode processors '%s' of provider '%s'", m.ID, name))
+				}
+			}
+			resp.Dynamic = append(resp.Dynamic, dynamic)
+		}
+	}
+
+	if req.Expression == "" && req.Condition == "" {
+		return resp, nil
+	}
+	vars, sources := snapshot.Vars()
+	for i, v := range vars {
+		evaluation := &proto.VarsEvaluation{
+			Provider: sources[i].Provider,
+			Id:       sources[i].ID,
+		}
+		var err error
+		if req.Condition != "" {
+			evaluation.Matched, err = evaluateCondition(req.Condition, v)
+		} else {
+			evaluation.Matched, evaluation.Value, err = evaluateExpression(req.Expression, v)
+		}
+		if err != nil {
+			evaluation.Error = err.Error()
+		}
+		resp.Evaluations = append(resp.Evaluations, evaluation)
+	}
+	return resp, nil
+}
+
 // getSpecs will return the specs for the program associated with the specified route key/app name, or all programs if no key(s) are specified.
 // if matchRK or matchApp are empty all results will be returned.
 func (s *Server) getSpecInfo(matchRK, matchApp string) []specInfo {
@@ -596,3 +753,49 @@ func agentAppStatusToProto(apps []status.AgentApplicationStatus) []*proto.Applic
 	}
 	return s
 }
+
+// evaluateExpression replaces the variables of the expression, the JSON encoded value is returned when
+// all the variables matched.
+func evaluateExpression(expression string, vars *transpiler.Vars) (bool, string, error) {
+	ast, err := transpiler.NewAST(map[string]interface{}{"value": expression})
+	if err != nil {
+		return false, "", err
+	}
+	if err := ast.Apply(vars); err != nil {
+		if errors.Is(err, transpiler.ErrNoMatch) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	m, err := ast.Map()
+	if err != nil {
+		return false, "", err
+	}
+	value, err := marshalRedacted(m["value"])
+	if err != nil {
+		return false, "", err
+	}
+	return true, value, nil
+}
+
+func evaluateCondition(condition string, vars *transpiler.Vars) (bool, error) {
+	return eql.Eval(condition, vars)
+}
+
+// redactEnv redacts the values of the environment provider, the whole environment of the agent is in it
+// including the credentials given to containers, e.g. FLEET_ENROLLMENT_TOKEN.
+func redactEnv(env map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(env))
+	for k := range env {
+		redacted[k] = secrets.RedactedValue
+	}
+	return redacted
+}
+
+func marshalRedacted(v interface{}) (string, error) {
+	b, err := json.Marshal(secrets.Redact(v))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/control/proto"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/composable/providers/secrets"
+)
+
+type stubComposable struct {
+	snapshot composable.Snapshot
+}
+
+func (c *stubComposable) Run(_ context.Context, _ composable.VarsCallback) error {
+	return nil
+}
+
+func (c *stubComposable) Snapshot() composable.Snapshot {
+	return c.snapshot
+}
+
+func newVarsServer() *Server {
+	return &Server{composable: &stubComposable{snapshot: composable.Snapshot{
+		Context: map[string]map[string]interface{}{
+			"env": {
+				"FLEET_ENROLLMENT_TOKEN": "enrollment-token",
+				"ELASTICSEARCH_PASSWORD": "changeme",
+			},
+			"host": {
+				"name": "agent-host",
+			},
+		},
+	}}}
+}
+
+func TestVarsRedactsEnv(t *testing.T) {
+	resp, err := newVarsServer().Vars(context.Background(), &proto.VarsRequest{})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Context, 2)
+	assert.Equal(t, "env", resp.Context[0].Name)
+	var env map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(resp.Context[0].Mapping), &env))
+	assert.Equal(t, map[string]interface{}{
+		"FLEET_ENROLLMENT_TOKEN": secrets.RedactedValue,
+		"ELASTICSEARCH_PASSWORD": secrets.RedactedValue,
+	}, env)
+	assert.Equal(t, `{"name":"agent-host"}`, resp.Context[1].Mapping)
+}
+
+func TestVarsRedactsEnvExpression(t *testing.T) {
+	resp, err := newVarsServer().Vars(context.Background(), &proto.VarsRequest{
+		Expression: "${env.ELASTICSEARCH_PASSWORD}",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Evaluations, 1)
+	assert.True(t, resp.Evaluations[0].Matched)
+	var value string
+	require.NoError(t, json.Unmarshal([]byte(resp.Evaluations[0].Value), &value))
+	assert.Equal(t, secrets.RedactedValue, value)
+}
+
+func TestVarsConditionOnEnv(t *testing.T) {
+	testCases := map[string]struct {
+		condition string
+		matched   bool
+	}{
+		"guessed value": {
+			condition: "${env.ELASTICSEARCH_PASSWORD} == 'changeme'",
+		},
+		"redacted value": {
+			condition: "${env.ELASTICSEARCH_PASSWORD} == '" + secrets.RedactedValue + "'",
+			matched:   true,
+		},
+		"other provider": {
+			condition: "${host.name} == 'agent-host'",
+			matched:   true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resp, err := newVarsServer().Vars(context.Background(), &proto.VarsRequest{
+				Condition: tc.condition,
+			})
+			require.NoError(t, err)
+
+			require.Len(t, resp.Evaluations, 1)
+			assert.Empty(t, resp.Evaluations[0].Error)
+			assert.Equal(t, tc.matched, resp.Evaluations[0].Matched)
+			assert.Empty(t, resp.Evaluations[0].Value)
+		})
+	}
+}
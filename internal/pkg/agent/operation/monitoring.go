@@ -205,7 +205,7 @@ func (o *Operator) generateMonitoringSteps(version, outputType string, output in
 }
 
 func loadSpecFromSupported(processName string) program.Spec {
-	if loadedSpec, found := program.SupportedMap[strings.ToLower(processName)]; found {
+	if loadedSpec, found := program.SpecByCmd(processName); found {
 		return loadedSpec
 	}
 
@@ -663,7 +663,7 @@ func normalizeHTTPCopyRules(name string) []map[string]interface{} {
 		},
 	}
 
-	spec, found := program.SupportedMap[name]
+	spec, found := program.SpecByCmd(name)
 	if !found {
 		return fromToMap
 	}
@@ -64,6 +64,9 @@ type Operator struct {
 	apps     map[string]Application
 	appsLock sync.Mutex
 
+	// journal is only set when the operator runs in dry-run mode.
+	journal *journal
+
 	downloader       download.Downloader
 	verifier         download.Verifier
 	installer        install.InstallerChecker
@@ -113,6 +116,10 @@ func NewOperator(
 		statusReporter:   statusController.RegisterComponent("operator-" + pipelineID),
 	}
 
+	if config.DryRun {
+		operator.journal = &journal{}
+	}
+
 	operator.initHandlerMap()
 
 	os.MkdirAll(config.DownloadConfig.TargetDirectory, 0755)
@@ -184,7 +191,7 @@ func (o *Operator) HandleConfig(ctx context.Context, cfg configrequest.Request)
 
 	for _, step := range steps {
 		if !strings.EqualFold(step.ProgramSpec.Cmd, monitoringName) {
-			if _, isSupported := program.SupportedMap[strings.ToLower(step.ProgramSpec.Cmd)]; !isSupported {
+			if _, isSupported := program.SpecByCmd(step.ProgramSpec.Cmd); !isSupported {
 				// mark failed, new config cannot be run
 				msg := fmt.Sprintf("program '%s' is not supported", step.ProgramSpec.Cmd)
 				o.statusReporter.Update(state.Failed, msg, nil)
@@ -298,6 +305,13 @@ func (o *Operator) runFlow(p Descriptor, operations []operation) error {
 			return err
 		}
 
+		if o.journal != nil {
+			if err := o.runDryOperation(o.bgContext, p, app, op); err != nil {
+				return err
+			}
+			continue
+		}
+
 		shouldRun, err := op.Check(o.bgContext, app)
 		if err != nil {
 			return err
@@ -350,7 +364,10 @@ func (o *Operator) getApp(p Descriptor) (Application, error) {
 		appName += "_monitoring"
 	}
 
-	if p.ServicePort() == 0 {
+	if o.journal != nil {
+		// Dry-run mode never spawns a process, application only lives in memory.
+		a = newDryRunApplication(p.ID(), appName, desc, o.reporter, o.statusController)
+	} else if p.ServicePort() == 0 {
 		// Applications without service ports defined are ran as through the process application type.
 		a, err = process.NewApplication(
 			o.bgContext,
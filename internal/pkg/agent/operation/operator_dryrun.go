@@ -0,0 +1,245 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package operation
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/elastic/elastic-agent-client/v7/pkg/proto"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
+	"github.com/elastic/elastic-agent/internal/pkg/core/app"
+	"github.com/elastic/elastic-agent/internal/pkg/core/monitoring"
+	"github.com/elastic/elastic-agent/internal/pkg/core/monitoring/noop"
+	"github.com/elastic/elastic-agent/internal/pkg/core/plugin/process"
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/pkg/core/server"
+)
+
+// JournalEntry is an operation recorded by an operator running in dry-run mode.
+type JournalEntry struct {
+	Time       time.Time
+	PipelineID string
+	App        string
+	Version    string
+	Operation  string
+	// Skipped is true when the check of the operation determined it does not need to run.
+	Skipped bool
+	// Config is the configuration passed along start and config operations.
+	Config map[string]interface{}
+}
+
+// maxJournalEntries is the number of operations kept by the journal, the oldest operations are dropped
+// past it.
+const maxJournalEntries = 1000
+
+type journal struct {
+	lock    sync.Mutex
+	entries []JournalEntry
+	// size overrides maxJournalEntries when set.
+	size int
+}
+
+func (j *journal) record(e JournalEntry) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	size := j.size
+	if size <= 0 {
+		size = maxJournalEntries
+	}
+	if len(j.entries) >= size {
+		n := copy(j.entries, j.entries[len(j.entries)-size+1:])
+		j.entries = j.entries[:n]
+	}
+	j.entries = append(j.entries, e)
+}
+
+func (j *journal) Entries() []JournalEntry {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entries := make([]JournalEntry, len(j.entries))
+	copy(entries, j.entries)
+	return entries
+}
+
+// Journal returns the last operations recorded by the operator when running in dry-run mode,
+// nil is returned otherwise.
+func (o *Operator) Journal() []JournalEntry {
+	if o.journal == nil {
+		return nil
+	}
+	return o.journal.Entries()
+}
+
+// runDryOperation records the operation into the journal instead of executing it.
+//
+// Operations acting on artifacts are never executed, operations acting on the application are
+// executed against the in-memory application so following operations observe a consistent state.
+func (o *Operator) runDryOperation(ctx context.Context, p Descriptor, application Application, op operation) error {
+	entry := JournalEntry{
+		Time:       time.Now(),
+		PipelineID: o.pipelineID,
+		App:        application.Name(),
+		Version:    p.Version(),
+		Operation:  op.Name(),
+	}
+
+	switch op := op.(type) {
+	case *retryableOperations:
+		for _, child := range op.operations {
+			if err := o.runDryOperation(ctx, p, application, child); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *operationFetch, *operationVerify, *operationInstall, *operationUninstall:
+		o.journal.record(entry)
+		return nil
+	case *operationStart:
+		entry.Config = op.cfg
+	case *operationConfig:
+		entry.Config = op.cfg
+	}
+
+	shouldRun, err := op.Check(ctx, application)
+	if err != nil {
+		return err
+	}
+
+	entry.Skipped = !shouldRun
+	o.journal.record(entry)
+	if !shouldRun {
+		return nil
+	}
+
+	return op.Run(ctx, application)
+}
+
+// dryRunApplication is an in-memory application which never spawns a process.
+type dryRunApplication struct {
+	id             string
+	name           string
+	spec           program.Spec
+	reporter       state.Reporter
+	statusReporter status.Reporter
+
+	appLock sync.Mutex
+	state   state.State
+	config  map[string]interface{}
+}
+
+func newDryRunApplication(
+	id, appName string,
+	desc *app.Descriptor,
+	reporter state.Reporter,
+	statusController status.Controller) *dryRunApplication {
+	return &dryRunApplication{
+		id:       id,
+		name:     appName,
+		spec:     desc.Spec(),
+		reporter: reporter,
+		state: state.State{
+			Status: state.Stopped,
+		},
+		statusReporter: statusController.RegisterApp(id, appName),
+	}
+}
+
+// Name returns application name.
+func (a *dryRunApplication) Name() string {
+	return a.name
+}
+
+// Started returns true if the application is started.
+func (a *dryRunApplication) Started() bool {
+	a.appLock.Lock()
+	defer a.appLock.Unlock()
+	return a.state.Status != state.Stopped && a.state.Status != state.Crashed && a.state.Status != state.Failed
+}
+
+// Start marks the application as running with the provided configuration.
+func (a *dryRunApplication) Start(_ context.Context, _ app.Taggable, cfg map[string]interface{}) error {
+	a.appLock.Lock()
+	defer a.appLock.Unlock()
+
+	a.config = cfg
+	a.setState(state.Healthy, "Running (dry run)", nil)
+	return nil
+}
+
+// Stop marks the application as stopped.
+func (a *dryRunApplication) Stop() {
+	a.appLock.Lock()
+	defer a.appLock.Unlock()
+
+	a.config = nil
+	a.setState(state.Stopped, "Stopped", nil)
+}
+
+// Shutdown stops the application.
+func (a *dryRunApplication) Shutdown() {
+	a.Stop()
+	a.statusReporter.Unregister()
+}
+
+// Configure replaces the configuration of a running application.
+func (a *dryRunApplication) Configure(_ context.Context, config map[string]interface{}) error {
+	a.appLock.Lock()
+	defer a.appLock.Unlock()
+
+	if a.state.Status == state.Stopped {
+		return process.ErrAppNotRunning
+	}
+	a.config = config
+	return nil
+}
+
+// Monitor returns a noop monitor, nothing is running to be monitored.
+func (a *dryRunApplication) Monitor() monitoring.Monitor {
+	return noop.NewMonitor()
+}
+
+// State returns the application state.
+func (a *dryRunApplication) State() state.State {
+	a.appLock.Lock()
+	defer a.appLock.Unlock()
+	return a.state
+}
+
+// Spec returns the program spec of this app.
+func (a *dryRunApplication) Spec() program.Spec {
+	return a.spec
+}
+
+// SetState sets the status of the application.
+func (a *dryRunApplication) SetState(s state.Status, msg string, payload map[string]interface{}) {
+	a.appLock.Lock()
+	defer a.appLock.Unlock()
+	a.setState(s, msg, payload)
+}
+
+// OnStatusChange is never called, the application is never registered into the server.
+func (a *dryRunApplication) OnStatusChange(_ *server.ApplicationState, _ proto.StateObserved_Status, _ string, _ map[string]interface{}) {
+}
+
+func (a *dryRunApplication) setState(s state.Status, msg string, payload map[string]interface{}) {
+	if a.state.Status == s && a.state.Message == msg && reflect.DeepEqual(a.state.Payload, payload) {
+		return
+	}
+
+	a.state.Status = s
+	a.state.Message = msg
+	a.state.Payload = payload
+	if a.reporter != nil {
+		go a.reporter.OnStateChange(a.id, a.name, a.state)
+	}
+	a.statusReporter.Update(s, msg, payload)
+}
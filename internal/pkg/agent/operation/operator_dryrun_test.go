@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package operation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+)
+
+func TestDryRunOperator(t *testing.T) {
+	p := getProgram("configurable", "1.0")
+
+	operator := getTestOperator(t, downloadPath, installPath, p)
+	operator.journal = &journal{}
+
+	cfg := map[string]interface{}{"TestFile": "dry-run"}
+	require.NoError(t, operator.start(p, cfg))
+
+	items := operator.State()
+	item, ok := items[p.ID()]
+	require.True(t, ok)
+	assert.Equal(t, state.Healthy, item.Status)
+	assert.Nil(t, item.ProcessInfo, "no process should be spawned in dry-run mode")
+
+	// starting again only pushes the configuration
+	require.NoError(t, operator.start(p, cfg))
+	require.NoError(t, operator.pushConfig(p, map[string]interface{}{"TestFile": "updated"}))
+	require.NoError(t, operator.stop(p))
+
+	_, ok = operator.State()[p.ID()]
+	assert.False(t, ok, "stopped application should be removed")
+
+	type recorded struct {
+		op      string
+		skipped bool
+	}
+	expected := []recorded{
+		{"operation-fetch", false},
+		{"operation-verify", false},
+		{"operation-install", false},
+		{"operation-start", false},
+		{"operation-config", false},
+		{"operation-fetch", false},
+		{"operation-verify", false},
+		{"operation-install", false},
+		{"operation-start", true},
+		{"operation-config", false},
+		{"operation-config", false},
+		{"operation-stop", false},
+		{"operation-uninstall", false},
+	}
+
+	entries := operator.Journal()
+	actual := make([]recorded, 0, len(entries))
+	for _, e := range entries {
+		assert.Equal(t, "p1", e.PipelineID)
+		assert.Equal(t, "configurable", e.App)
+		actual = append(actual, recorded{e.Operation, e.Skipped})
+	}
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, map[string]interface{}{"TestFile": "updated"}, entries[10].Config)
+}
+
+func TestJournalSize(t *testing.T) {
+	j := &journal{size: 3}
+	for _, op := range []string{"a", "b", "c", "d", "e"} {
+		j.record(JournalEntry{Operation: op})
+	}
+
+	entries := j.Entries()
+	ops := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ops = append(ops, e.Operation)
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, ops)
+}
@@ -19,6 +19,9 @@ import (
 	"github.com/elastic/elastic-agent-client/v7/pkg/proto"
 
 	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
+	"github.com/elastic/elastic-agent/internal/pkg/artifact"
+	"github.com/elastic/elastic-agent/internal/pkg/core/app"
+	"github.com/elastic/elastic-agent/internal/pkg/core/process"
 	"github.com/elastic/elastic-agent/internal/pkg/core/state"
 )
 
@@ -348,6 +351,76 @@ func TestConfigurableCrash(t *testing.T) {
 	}
 }
 
+func TestConfigurableExitRestartPolicy(t *testing.T) {
+	for _, policy := range []process.RestartPolicy{process.RestartNever, process.RestartOnFailure} {
+		t.Run(string(policy), func(t *testing.T) {
+			spec := program.SupportedMap["configurable"]
+			spec.RestartPolicy = policy
+			p := app.NewDescriptor(spec, "1.0", &artifact.Config{
+				InstallPath:     installPath,
+				OperatingSystem: "darwin",
+				Architecture:    "64",
+			}, nil)
+
+			operator := getTestOperator(t, downloadPath, installPath, p)
+			if err := operator.start(p, nil); err != nil {
+				t.Fatal(err)
+			}
+			defer operator.stop(p) // failure catch, to ensure no sub-process stays running
+
+			var pid int
+			waitFor(t, func() error {
+				item, ok := operator.State()[p.ID()]
+				if !ok {
+					return fmt.Errorf("no state for process")
+				}
+				if item.Status != state.Healthy {
+					return fmt.Errorf("process never went to running")
+				}
+				pid = item.ProcessInfo.PID
+				return nil
+			})
+
+			// exiting with code 0 is not restarted by the policy
+			if err := operator.pushConfig(p, map[string]interface{}{"Exit": true}); err != nil {
+				t.Fatalf("failed to config: %v", err)
+			}
+			waitFor(t, func() error {
+				item, ok := operator.State()[p.ID()]
+				if !ok {
+					return fmt.Errorf("no state for process")
+				}
+				if item.Status != state.Stopped {
+					return fmt.Errorf("process never stopped")
+				}
+				return nil
+			})
+
+			// a new configuration starts it again
+			if err := operator.pushConfig(p, map[string]interface{}{}); err != nil {
+				t.Fatalf("failed to config: %v", err)
+			}
+			waitFor(t, func() error {
+				item, ok := operator.State()[p.ID()]
+				if !ok {
+					return fmt.Errorf("no state for process")
+				}
+				if item.Status != state.Healthy {
+					return fmt.Errorf("process never went back to running")
+				}
+				if item.ProcessInfo == nil || item.ProcessInfo.PID == pid {
+					return fmt.Errorf("process never started again")
+				}
+				return nil
+			})
+
+			if err := operator.stop(p); err != nil {
+				t.Fatalf("failed to stop process: %v", err)
+			}
+		})
+	}
+}
+
 func TestConfigurableStartStop(t *testing.T) {
 	p := getProgram("configurable", "1.0")
 
@@ -74,6 +74,10 @@ func (s *configServer) OnConfig(cfgString string) {
 		os.Exit(2)
 	}
 
+	if testCfg.Exit {
+		os.Exit(0)
+	}
+
 	if testCfg.Status != nil {
 		s.client.Status(*testCfg.Status, "Custom status", map[string]interface{}{
 			"status":  *testCfg.Status,
@@ -101,4 +105,5 @@ type TestConfig struct {
 	TestFile string                      `config:"TestFile" yaml:"TestFile"`
 	Status   *proto.StateObserved_Status `config:"Status" yaml:"Status"`
 	Crash    bool                        `config:"Crash" yaml:"Crash"`
+	Exit     bool                        `config:"Exit" yaml:"Exit"`
 }
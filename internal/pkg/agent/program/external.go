@@ -0,0 +1,202 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package program
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/eql"
+)
+
+// external keeps the specs loaded at runtime, a loaded spec replaces the embedded spec sharing the
+// same command.
+var external = struct {
+	sync.RWMutex
+	specs       []Spec
+	subscribers map[chan struct{}]struct{}
+}{subscribers: make(map[chan struct{}]struct{})}
+
+// Specs returns the specs of all the supported programs, embedded and loaded at runtime.
+func Specs() []Spec {
+	external.RLock()
+	defer external.RUnlock()
+
+	if len(external.specs) == 0 {
+		return Supported
+	}
+
+	specs := make([]Spec, 0, len(Supported)+len(external.specs))
+	replaced := make(map[string]bool, len(external.specs))
+	for _, spec := range Supported {
+		if s, ok := findByCmd(external.specs, spec.Cmd); ok {
+			spec = s
+			replaced[strings.ToLower(s.Cmd)] = true
+		}
+		specs = append(specs, spec)
+	}
+	for _, spec := range external.specs {
+		if !replaced[strings.ToLower(spec.Cmd)] {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// SpecByCmd returns the spec of the supported program running the command.
+func SpecByCmd(cmd string) (Spec, bool) {
+	external.RLock()
+	defer external.RUnlock()
+
+	if spec, ok := findByCmd(external.specs, cmd); ok {
+		return spec, true
+	}
+	spec, ok := SupportedMap[strings.ToLower(cmd)]
+	return spec, ok
+}
+
+// SetExternalSpecs replaces the specs loaded at runtime, subscribers are notified when the specs
+// differ from the previous ones.
+func SetExternalSpecs(specs []Spec) {
+	external.Lock()
+	defer external.Unlock()
+
+	if reflect.DeepEqual(external.specs, specs) {
+		return
+	}
+	external.specs = specs
+
+	for ch := range external.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// a notification is already pending
+		}
+	}
+}
+
+// SubscribeSpecs returns a channel receiving a notification every time the specs loaded at runtime
+// change, the subscription ends when the context is done.
+func SubscribeSpecs(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	external.Lock()
+	external.subscribers[ch] = struct{}{}
+	external.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		external.Lock()
+		delete(external.subscribers, ch)
+		external.Unlock()
+	}()
+
+	return ch
+}
+
+// LoadSpecs reads and validates all the `*.yml` specs of the directory, no specs are returned if
+// one of them is invalid or defines an unknown field.
+func LoadSpecs(dir string) ([]Spec, error) {
+	specs, err := readSpecs(filepath.Join(dir, "*.yml"), yaml.UnmarshalStrict)
+	if err != nil {
+		return nil, err
+	}
+
+	cmds := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		if err := ValidateSpec(spec); err != nil {
+			return nil, err
+		}
+
+		cmd := strings.ToLower(spec.Cmd)
+		if cmds[cmd] {
+			return nil, errors.New(fmt.Sprintf("spec for command '%s' is defined multiple times", spec.Cmd), errors.TypeConfig)
+		}
+		cmds[cmd] = true
+	}
+
+	return specs, nil
+}
+
+// ValidateSpec checks that the spec defines the fields required to run a program and that all its
+// fields are valid, so that an invalid spec is rejected when it is loaded rather than when the
+// program starts.
+func ValidateSpec(spec Spec) error {
+	invalid := func(msg string) error {
+		return errors.New(fmt.Sprintf("invalid spec '%s': %s", spec.Name, msg), errors.TypeConfig)
+	}
+
+	switch {
+	case spec.Name == "":
+		return invalid("missing name")
+	case spec.Cmd == "":
+		return invalid("missing cmd")
+	case spec.Artifact == "":
+		return invalid("missing artifact")
+	case spec.When == "":
+		return invalid(ErrMissingWhen.Error())
+	case strings.ContainsAny(spec.Cmd, `/\`) || spec.Cmd == "." || spec.Cmd == "..":
+		return invalid("cmd must be a file name")
+	case spec.ServicePort < 0 || spec.ServicePort > 65535:
+		return invalid(fmt.Sprintf("service port %d is out of range", spec.ServicePort))
+	}
+
+	for _, inputType := range spec.ActionInputTypes {
+		if inputType == "" {
+			return invalid("empty action input type")
+		}
+	}
+
+	if err := validateExpression(spec.When); err != nil {
+		return invalid(fmt.Sprintf("invalid 'when' expression: %s", err))
+	}
+	if spec.Constraints != "" {
+		if err := validateExpression(spec.Constraints); err != nil {
+			return invalid(fmt.Sprintf("invalid 'constraints' expression: %s", err))
+		}
+	}
+
+	if err := spec.RestartPolicy.Validate(); err != nil {
+		return invalid(err.Error())
+	}
+	if spec.Resources != nil {
+		if err := spec.Resources.Validate(); err != nil {
+			return invalid(fmt.Sprintf("invalid resources: %s", err))
+		}
+	}
+	if err := spec.Sandbox.Validate(); err != nil {
+		return invalid(fmt.Sprintf("invalid sandbox: %s", err))
+	}
+
+	return nil
+}
+
+// validateExpression fails on the expressions that cannot be parsed or that call an unknown function.
+func validateExpression(expression string) error {
+	analysis, err := eql.Analyze(expression)
+	if err != nil {
+		return err
+	}
+	if len(analysis.UnknownFunctions) > 0 {
+		return fmt.Errorf("call to unknown function %s", strings.Join(analysis.UnknownFunctions, ", "))
+	}
+	return nil
+}
+
+func findByCmd(specs []Spec, cmd string) (Spec, bool) {
+	for _, spec := range specs {
+		if strings.EqualFold(spec.Cmd, cmd) {
+			return spec, true
+		}
+	}
+	return Spec{}, false
+}
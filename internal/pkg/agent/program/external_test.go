@@ -0,0 +1,191 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package program
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const internalSpec = `
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+rules:
+  - filter:
+      selectors:
+        - inputs
+        - output
+when: length(${inputs}) > 0 and hasKey(${output}, 'elasticsearch')
+`
+
+const filebeatSpec = `
+name: Filebeat
+cmd: filebeat
+args: ["-custom"]
+artifact: beats/filebeat
+when: length(${filebeat.inputs}) > 0
+`
+
+func TestLoadSpecs(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "internalbeat.yml", internalSpec)
+	writeSpec(t, dir, "filebeat.yml", filebeatSpec)
+
+	specs, err := LoadSpecs(dir)
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+
+	SetExternalSpecs(specs)
+	defer SetExternalSpecs(nil)
+
+	spec, ok := SpecByCmd("internalbeat")
+	require.True(t, ok)
+	assert.Equal(t, "Internalbeat", spec.Name)
+
+	spec, ok = SpecByCmd("Filebeat")
+	require.True(t, ok)
+	assert.Equal(t, []string{"-custom"}, spec.Args)
+
+	all := Specs()
+	assert.Len(t, all, len(Supported)+1)
+	var filebeats int
+	for _, s := range all {
+		if s.Cmd == "filebeat" {
+			filebeats++
+			assert.Equal(t, []string{"-custom"}, s.Args)
+		}
+	}
+	assert.Equal(t, 1, filebeats)
+
+	SetExternalSpecs(nil)
+	_, ok = SpecByCmd("internalbeat")
+	assert.False(t, ok)
+	assert.Equal(t, Supported, Specs())
+}
+
+func TestLoadSpecsInvalid(t *testing.T) {
+	testcases := map[string][]string{
+		"missing when": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+`},
+		"missing name": {`
+cmd: internalbeat
+artifact: beats/internalbeat
+when: length(${inputs}) > 0
+`},
+		"invalid when": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+when: length(${inputs}) >
+`},
+		"unknown function": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+when: hasInputs(${inputs})
+`},
+		"missing artifact": {`
+name: Internalbeat
+cmd: internalbeat
+when: length(${inputs}) > 0
+`},
+		"cmd with path": {`
+name: Internalbeat
+cmd: ../internalbeat
+artifact: beats/internalbeat
+when: length(${inputs}) > 0
+`},
+		"invalid service port": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+service: 70000
+when: length(${inputs}) > 0
+`},
+		"unknown field": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+restart: never
+when: length(${inputs}) > 0
+`},
+		"invalid restart policy": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+restart_policy: sometimes
+when: length(${inputs}) > 0
+`},
+		"invalid resources": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+resources:
+  pids: -1
+when: length(${inputs}) > 0
+`},
+		"invalid sandbox": {`
+name: Internalbeat
+cmd: internalbeat
+artifact: beats/internalbeat
+sandbox:
+  namespaces: [net]
+when: length(${inputs}) > 0
+`},
+		"duplicated cmd": {internalSpec, internalSpec},
+	}
+
+	for name, files := range testcases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for i, content := range files {
+				writeSpec(t, dir, fmt.Sprintf("spec-%d.yml", i), content)
+			}
+			_, err := LoadSpecs(dir)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSubscribeSpecs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer SetExternalSpecs(nil)
+
+	ch := SubscribeSpecs(ctx)
+
+	spec, err := NewSpecFromBytes([]byte(internalSpec))
+	require.NoError(t, err)
+
+	SetExternalSpecs([]Spec{spec})
+	select {
+	case <-ch:
+	default:
+		t.Fatal("expected a notification")
+	}
+
+	// same specs, no notification
+	SetExternalSpecs([]Spec{spec})
+	select {
+	case <-ch:
+		t.Fatal("unexpected notification")
+	default:
+	}
+}
+
+func writeSpec(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
@@ -68,7 +68,7 @@ func Programs(agentInfo transpiler.AgentInfo, singleConfig *transpiler.AST) (map
 // DetectPrograms returns the list of programs detected from the provided configuration.
 func DetectPrograms(agentInfo transpiler.AgentInfo, singleConfig *transpiler.AST) ([]Program, error) {
 	programs := make([]Program, 0)
-	for _, spec := range Supported {
+	for _, spec := range Specs() {
 		specificAST := singleConfig.Clone()
 		ok, err := DetectProgram(spec, agentInfo, specificAST)
 		if err != nil {
@@ -91,6 +91,11 @@ func DetectPrograms(agentInfo transpiler.AgentInfo, singleConfig *transpiler.AST
 // Note `ast` is modified to match what the program expects. Should clone the AST before passing to
 // this function if you want to still have the original.
 func DetectProgram(spec Spec, info transpiler.AgentInfo, ast *transpiler.AST) (bool, error) {
+	return DetectProgramWithTrace(spec, info, ast, nil)
+}
+
+// DetectProgramWithTrace behaves like DetectProgram and records every rule applied to the AST in the trace.
+func DetectProgramWithTrace(spec Spec, info transpiler.AgentInfo, ast *transpiler.AST, trace *transpiler.RuleTrace) (bool, error) {
 	if len(spec.Constraints) > 0 {
 		constraints, err := eql.New(spec.Constraints)
 		if err != nil {
@@ -105,7 +110,7 @@ func DetectProgram(spec Spec, info transpiler.AgentInfo, ast *transpiler.AST) (b
 		}
 	}
 
-	err := spec.Rules.Apply(info, ast)
+	err := spec.Rules.ApplyWithTrace(info, ast, trace)
 	if err != nil {
 		return false, err
 	}
@@ -122,10 +127,31 @@ func DetectProgram(spec Spec, info transpiler.AgentInfo, ast *transpiler.AST) (b
 	return expression.Eval(ast)
 }
 
+// TraceProgram applies the rules of the spec over the configuration generated for the output and
+// returns the trace of every rule applied.
+func TraceProgram(agentInfo transpiler.AgentInfo, singleConfig *transpiler.AST, output string, spec Spec) (*transpiler.RuleTrace, error) {
+	grouped, err := groupByOutputs(singleConfig)
+	if err != nil {
+		return nil, errors.New(err, errors.TypeConfig, "fail to extract program configuration")
+	}
+
+	config, ok := grouped[output]
+	if !ok {
+		return nil, fmt.Errorf("output '%s' is not recognized", output)
+	}
+
+	trace := &transpiler.RuleTrace{}
+	if _, err := DetectProgramWithTrace(spec, agentInfo, config.Clone(), trace); err != nil {
+		return trace, err
+	}
+	return trace, nil
+}
+
 // KnownProgramNames returns a list of runnable programs by the elastic-agent.
 func KnownProgramNames() []string {
-	names := make([]string, len(Supported))
-	for idx, program := range Supported {
+	specs := Specs()
+	names := make([]string, len(specs))
+	for idx, program := range specs {
 		names[idx] = program.Name
 	}
 	return names
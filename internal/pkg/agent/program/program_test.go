@@ -593,6 +593,41 @@ func TestUseCases(t *testing.T) {
 	}
 }
 
+func TestTraceProgram(t *testing.T) {
+	ast := transpiler.MustNewAST(map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"default": map[string]interface{}{
+				"type":  "elasticsearch",
+				"hosts": "xxx",
+			},
+		},
+		"inputs": []map[string]interface{}{
+			{
+				"type":    "log",
+				"streams": []map[string]interface{}{{"paths": "/var/log/hello.log"}},
+			},
+		},
+	})
+
+	spec, ok := FindSpecByName("Filebeat")
+	require.True(t, ok)
+
+	trace, err := TraceProgram(&fakeAgentInfo{}, ast, "default", spec)
+	require.NoError(t, err)
+	require.Equal(t, len(spec.Rules.Rules), len(trace.Steps))
+
+	var fixStreamChanged bool
+	for _, step := range trace.Steps {
+		if step.Rule == "fix_stream" && len(step.Changes) > 0 {
+			fixStreamChanged = true
+		}
+	}
+	assert.True(t, fixStreamChanged, "fix_stream should have changed the configuration")
+
+	_, err = TraceProgram(&fakeAgentInfo{}, ast, "unknown", spec)
+	assert.Error(t, err)
+}
+
 type fakeAgentInfo struct{}
 
 func (*fakeAgentInfo) AgentID() string {
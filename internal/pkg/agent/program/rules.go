@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package program
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+)
+
+// rulesFile is a file declaring named rules, each named rule is a list of rules using the same
+// syntax as the rules of a spec.
+//
+//	rules:
+//	  - name: internal_pipeline
+//	    rules:
+//	      - set:
+//	          key: output.pipeline
+//	          value: internal
+//	          when: ${output.type} == 'elasticsearch'
+type rulesFile struct {
+	Rules []ruleDefinition `yaml:"rules"`
+}
+
+type ruleDefinition struct {
+	Name string `yaml:"name"`
+	// Rules are kept raw until the previous definitions are registered, so a rule can use the
+	// rules defined before it.
+	Rules interface{} `yaml:"rules"`
+}
+
+// LoadRules registers the rules defined in the YAML files of the directory, files are loaded in
+// lexical order and a rule can only use the rules defined before it. No rule of the directory is
+// registered when one of the files is invalid.
+func LoadRules(dir string) (err error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+	if err != nil {
+		return errors.New(err, "could not list rules", errors.TypeConfig)
+	}
+
+	// rules are registered as they are parsed so the next ones can use them, they are removed
+	// when a later file fails
+	var registered []string
+	defer func() {
+		if err != nil {
+			for _, name := range registered {
+				transpiler.UnregisterRule(name)
+			}
+		}
+	}()
+
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return errors.New(err, fmt.Sprintf("could not read rules %s", f), errors.TypeConfig)
+		}
+
+		names, err := registerRules(b)
+		registered = append(registered, names...)
+		if err != nil {
+			return errors.New(err, fmt.Sprintf("could not load rules from file %s", f), errors.TypeConfig)
+		}
+	}
+
+	return nil
+}
+
+// registerRules registers the rules of a file and returns the names registered, including when a
+// rule of the file fails.
+func registerRules(b []byte) ([]string, error) {
+	var file rulesFile
+	if err := yaml.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(file.Rules))
+	for _, def := range file.Rules {
+		raw, err := yaml.Marshal(def.Rules)
+		if err != nil {
+			return names, err
+		}
+
+		rules := &transpiler.RuleList{}
+		if err := yaml.Unmarshal(raw, rules); err != nil {
+			return names, fmt.Errorf("invalid rule '%s': %w", def.Name, err)
+		}
+
+		if err := transpiler.RegisterRule(def.Name, rules); err != nil {
+			return names, err
+		}
+		names = append(names, def.Name)
+	}
+
+	return names, nil
+}
@@ -0,0 +1,147 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package program
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+)
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"01-base.yml": `
+rules:
+  - name: internal_pipeline
+    rules:
+      - set:
+          key: output.pipeline
+          value: internal
+          when: ${output.type} == 'elasticsearch'
+`,
+		"02-compose.yml": `
+rules:
+  - name: internal_output
+    rules:
+      - internal_pipeline: {}
+      - set:
+          key: output.settings
+          value:
+            workers: 2
+      - delete_if:
+          key: output.password
+          when: ${output.username} == ''
+`,
+		"ignored.txt": `rules: [`,
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	defer transpiler.UnregisterRule("internal_pipeline")
+	defer transpiler.UnregisterRule("internal_output")
+
+	require.NoError(t, LoadRules(dir))
+
+	spec, err := NewSpecFromBytes([]byte(`
+name: internal
+cmd: internal
+rules:
+  - internal_output: {}
+`))
+	require.NoError(t, err)
+
+	ast := transpiler.MustNewAST(map[string]interface{}{
+		"output": map[string]interface{}{
+			"type":     "elasticsearch",
+			"username": "",
+			"password": "changeme",
+		},
+	})
+	require.NoError(t, spec.Rules.Apply(nil, ast))
+
+	m, err := ast.Map()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"output": map[string]interface{}{
+			"type":     "elasticsearch",
+			"username": "",
+			"pipeline": "internal",
+			"settings": map[string]interface{}{
+				"workers": 2,
+			},
+		},
+	}, m)
+}
+
+func TestLoadRulesErrors(t *testing.T) {
+	testcases := map[string]string{
+		"unknown rule": `
+rules:
+  - name: unknown_usage
+    rules:
+      - not_defined: {}
+`,
+		"built-in name": `
+rules:
+  - name: copy
+    rules: []
+`,
+		"invalid yaml": `rules: [`,
+	}
+
+	for name, content := range testcases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "rules.yml"), []byte(content), 0600))
+			require.Error(t, LoadRules(dir))
+		})
+	}
+}
+
+func TestLoadRulesInvalidFileRegistersNothing(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"01-base.yml": `
+rules:
+  - name: partial_pipeline
+    rules:
+      - set:
+          key: output.pipeline
+          value: internal
+`,
+		"02-invalid.yml": `
+rules:
+  - name: partial_output
+    rules:
+      - partial_pipeline: {}
+  - name: partial_unknown
+    rules:
+      - not_defined: {}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	defer transpiler.UnregisterRule("partial_pipeline")
+	defer transpiler.UnregisterRule("partial_output")
+
+	require.Error(t, LoadRules(dir))
+
+	for _, name := range []string{"partial_pipeline", "partial_output"} {
+		_, err := NewSpecFromBytes([]byte(`
+name: partial
+cmd: partial
+rules:
+  - ` + name + `: {}
+`))
+		assert.Error(t, err, "rule %s should not be registered", name)
+	}
+}
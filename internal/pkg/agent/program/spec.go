@@ -13,6 +13,7 @@ import (
 
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+	"github.com/elastic/elastic-agent/internal/pkg/core/process"
 )
 
 // ErrMissingWhen is returned when no boolean expression is defined for a program.
@@ -41,10 +42,23 @@ type Spec struct {
 	Constraints           string               `yaml:"constraints"`
 	RestartOnOutputChange bool                 `yaml:"restart_on_output_change,omitempty"`
 	ExprtedMetrics        []string             `yaml:"exported_metrics,omitempty"`
+
+	// RestartPolicy defines when the program is restarted after it exits, defaults to always.
+	RestartPolicy process.RestartPolicy `yaml:"restart_policy,omitempty"`
+
+	// Resources limits the resources of the program when cgroups are enabled.
+	Resources *process.ResourceLimits `yaml:"resources,omitempty"`
+
+	// Sandbox restricts the privileges of the program beyond its user and group.
+	Sandbox *process.Sandbox `yaml:"sandbox,omitempty"`
 }
 
 // ReadSpecs reads all the specs that match the provided globbing path.
 func ReadSpecs(path string) ([]Spec, error) {
+	return readSpecs(path, yaml.Unmarshal)
+}
+
+func readSpecs(path string, unmarshal func([]byte, interface{}) error) ([]Spec, error) {
 	var specs []Spec
 	files, err := filepath.Glob(path)
 	if err != nil {
@@ -58,7 +72,7 @@ func ReadSpecs(path string) ([]Spec, error) {
 		}
 
 		spec := Spec{}
-		if err := yaml.Unmarshal(b, &spec); err != nil {
+		if err := unmarshal(b, &spec); err != nil {
 			return []Spec{}, errors.New(err, fmt.Sprintf("could not unmarshal YAML for file %s", f), errors.TypeConfig)
 		}
 		specs = append(specs, spec)
@@ -87,7 +101,7 @@ func MustReadSpecs(path string) []Spec {
 
 // FindSpecByName find a spec by name and return it or false if we cannot find it.
 func FindSpecByName(name string) (Spec, bool) {
-	for _, candidate := range Supported {
+	for _, candidate := range Specs() {
 		if name == candidate.Name {
 			return candidate, true
 		}
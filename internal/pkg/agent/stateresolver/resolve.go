@@ -5,6 +5,7 @@
 package stateresolver
 
 import (
+	"reflect"
 	"sort"
 	"strings"
 	"time"
@@ -137,6 +138,29 @@ func converge(s state, cfg cfgReq) (state, []configrequest.Step) {
 			continue
 		}
 
+		// Spec of the program changed, the process is recreated to run with the new spec.
+		if !reflect.DeepEqual(a.Program.Spec, p.Spec) {
+			newState.Active[p.Identifier()] = active{
+				LastChange:   updateState,
+				LastModified: cfg.CreatedAt(),
+				Identifier:   p.Identifier(),
+				Program:      p,
+			}
+			steps = append(steps, configrequest.Step{
+				ID:          configrequest.StepRemove,
+				ProgramSpec: a.Program.Spec,
+				Version:     release.Version(),
+			}, configrequest.Step{
+				ID:          configrequest.StepRun,
+				ProgramSpec: p.Spec,
+				Version:     release.Version(),
+				Meta: map[string]interface{}{
+					configrequest.MetaConfigKey: p.Configuration(),
+				},
+			})
+			continue
+		}
+
 		// Checksum doesn't match and we force an update of the process.
 		if a.Program.Checksum() != p.Checksum() {
 			newState.Active[p.Identifier()] = active{
@@ -24,6 +24,8 @@ func TestResolver(t *testing.T) {
 	fb1 := fb("1")
 	fb2 := fb("2")
 	mb1 := mb("2")
+	fb1Spec := fb("1")
+	fb1Spec.Spec.Args = append([]string{"-changed"}, fb1Spec.Spec.Args...)
 	tn := time.Now()
 	tn2 := time.Now().Add(time.Minute * 5)
 
@@ -263,6 +265,52 @@ func TestResolver(t *testing.T) {
 				},
 			},
 		},
+		"recreate a running program when its spec changed": {
+			submit: &cfg{
+				id:        "config-2",
+				createdAt: tn2,
+				programs: []program.Program{
+					fb1Spec,
+				},
+			},
+			cur: state{
+				ID:           "config-1",
+				LastModified: tn,
+				Active: map[string]active{
+					"filebeat": active{
+						LastChange:   startState,
+						LastModified: tn,
+						Identifier:   "filebeat",
+						Program:      fb1,
+					},
+				},
+			},
+			should: state{
+				ID:           "config-2",
+				LastModified: tn2,
+				Active: map[string]active{
+					"filebeat": active{
+						LastChange:   updateState,
+						LastModified: tn2,
+						Identifier:   "filebeat",
+						Program:      fb1Spec,
+					},
+				},
+			},
+			steps: []configrequest.Step{
+				configrequest.Step{
+					ID:          configrequest.StepRemove,
+					ProgramSpec: fb1.Spec,
+					Version:     release.Version(),
+				},
+				configrequest.Step{
+					ID:          configrequest.StepRun,
+					ProgramSpec: fb1Spec.Spec,
+					Version:     release.Version(),
+					Meta:        withMeta(fb1Spec),
+				},
+			},
+		},
 		"no changes detected": {
 			submit: &cfg{
 				id:        "config-1",
@@ -0,0 +1,92 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transpiler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/elastic/elastic-agent/internal/pkg/eql"
+)
+
+// ConditionWarning is a problem found in a condition of the configuration before it is evaluated.
+type ConditionWarning struct {
+	// Path is the selector of the condition key, e.g. `inputs.0.condition`.
+	Path      string
+	Condition string
+	Message   string
+}
+
+func (w ConditionWarning) String() string {
+	return fmt.Sprintf(`condition "%s" at %s: %s`, w.Condition, w.Path, w.Message)
+}
+
+// AnalyzeConditions statically analyzes all the conditions of the tree and reports the invalid
+// expressions, the unknown functions and the type mismatches. When vars are given, the variables
+// that none of them provide are also reported as they evaluate to null.
+func AnalyzeConditions(ast *AST, varsArray []*Vars) []ConditionWarning {
+	var warnings []ConditionWarning
+	walkConditions(ast.root, "", func(path, condition string) {
+		warn := func(msg string) {
+			warnings = append(warnings, ConditionWarning{Path: path, Condition: condition, Message: msg})
+		}
+
+		analysis, err := eql.Analyze(condition)
+		if err != nil {
+			warn(err.Error())
+			return
+		}
+		for _, msg := range analysis.Warnings() {
+			warn(msg)
+		}
+
+		if len(varsArray) == 0 {
+			return
+		}
+		for _, name := range analysis.Variables {
+			if !provided(varsArray, name) {
+				warn(fmt.Sprintf("variable %s is not provided", name))
+			}
+		}
+	})
+	return warnings
+}
+
+func walkConditions(node Node, path string, fn func(path, condition string)) {
+	join := func(name string) string {
+		if path == "" {
+			return name
+		}
+		return path + selectorSep + name
+	}
+
+	switch n := node.(type) {
+	case *Dict:
+		for _, child := range n.value {
+			walkConditions(child, path, fn)
+		}
+	case *List:
+		for i, child := range n.value {
+			walkConditions(child, join(strconv.Itoa(i)), fn)
+		}
+	case *Key:
+		if n.name == conditionKey {
+			if s, ok := n.value.(*StrVal); ok {
+				fn(join(n.name), s.value)
+			}
+			return
+		}
+		walkConditions(n.value, join(n.name), fn)
+	}
+}
+
+func provided(varsArray []*Vars, name string) bool {
+	for _, vars := range varsArray {
+		if _, ok := vars.Lookup(name); ok {
+			return true
+		}
+	}
+	return false
+}
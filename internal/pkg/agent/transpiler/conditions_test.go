@@ -0,0 +1,67 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transpiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeConditions(t *testing.T) {
+	ast, err := NewAST(map[string]interface{}{
+		"inputs": []interface{}{
+			map[string]interface{}{
+				"type":      "logfile",
+				"condition": "${host.name} == 'mac'",
+			},
+			map[string]interface{}{
+				"type":      "kubernetes/metrics",
+				"condition": "${kubernetes.namespace} == 'default' and unknown()",
+				"streams": []interface{}{
+					map[string]interface{}{
+						"condition": "${host.cpus} > '4'",
+					},
+				},
+			},
+			map[string]interface{}{
+				"type":      "system/metrics",
+				"condition": "${host.name} ==",
+			},
+			map[string]interface{}{
+				"type":      "endpoint",
+				"condition": true,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("without vars", func(t *testing.T) {
+		warnings := AnalyzeConditions(ast, nil)
+		require.Len(t, warnings, 3)
+		assert.Equal(t, ConditionWarning{
+			Path:      "inputs.1.condition",
+			Condition: "${kubernetes.namespace} == 'default' and unknown()",
+			Message:   "call to unknown function unknown",
+		}, warnings[0])
+		assert.Equal(t, "inputs.1.streams.0.condition", warnings[1].Path)
+		assert.Equal(t, "operator > expects a number, received a string", warnings[1].Message)
+		assert.Equal(t, "inputs.2.condition", warnings[2].Path)
+		assert.Contains(t, warnings[2].Message, "syntax error")
+	})
+
+	t.Run("with vars", func(t *testing.T) {
+		vars := mustMakeVars(map[string]interface{}{
+			"host": map[string]interface{}{
+				"name": "mac",
+				"cpus": 8,
+			},
+		})
+		warnings := AnalyzeConditions(ast, []*Vars{vars})
+		require.Len(t, warnings, 4)
+		assert.Equal(t, `condition "${kubernetes.namespace} == 'default' and unknown()" at inputs.1.condition: variable kubernetes.namespace is not provided`, warnings[1].String())
+	})
+}
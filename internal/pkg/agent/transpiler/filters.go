@@ -0,0 +1,313 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transpiler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filterRegex matches a filter of a variable, e.g. `lower` or `split(",")`.
+var filterRegex = regexp.MustCompile(`^([a-z][a-z0-9_]*)\s*(?:\((.*)\))?$`)
+
+type argKind int
+
+const (
+	stringArg argKind = iota
+	intArg
+)
+
+func (k argKind) String() string {
+	if k == intArg {
+		return "an integer"
+	}
+	return "a string"
+}
+
+// filterSpec defines a filter, the last optional arguments can be omitted.
+type filterSpec struct {
+	args     []argKind
+	optional int
+	apply    func(node Node, args []interface{}) (Node, error)
+}
+
+// filterSpecs are the filters that can follow the fallbacks of a variable, they are applied in order to
+// the first value found, e.g. ${kubernetes.pod.name | lower}.
+//
+// A name without arguments is a filter only when it follows a fallback and the filter takes no arguments,
+// it is otherwise a variable, e.g. ${url}.
+var filterSpecs = map[string]filterSpec{
+	"lower":        {apply: stringFilter(strings.ToLower)},
+	"upper":        {apply: stringFilter(strings.ToUpper)},
+	"trim":         {apply: stringFilter(strings.TrimSpace)},
+	"base64encode": {apply: stringFilter(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) })},
+	"base64decode": {apply: base64DecodeFilter},
+	"urlencode":    {apply: stringFilter(url.QueryEscape)},
+	"split":        {args: []argKind{stringArg}, apply: splitFilter},
+	"join":         {args: []argKind{stringArg}, apply: joinFilter},
+	"replace":      {args: []argKind{stringArg, stringArg}, apply: replaceFilter},
+	"substr":       {args: []argKind{intArg, intArg}, optional: 1, apply: substrFilter},
+	"url":          {args: []argKind{stringArg, intArg}, optional: 1, apply: urlFilter},
+}
+
+// filter is a filter of a variable with its arguments.
+type filter struct {
+	name  string
+	args  []interface{}
+	apply func(node Node, args []interface{}) (Node, error)
+}
+
+// parseFilter parses a segment of a variable, nil is returned when it is not a filter. The first segment
+// of a variable is always a fallback.
+func parseFilter(segment string, first bool) (*filter, error) {
+	m := filterRegex.FindStringSubmatch(segment)
+	if m == nil {
+		return nil, nil
+	}
+	name := m[1]
+	withArgs := strings.HasSuffix(segment, ")")
+	spec, ok := filterSpecs[name]
+	if !withArgs {
+		if !ok || first || len(spec.args) > spec.optional {
+			// a variable without any '.', e.g. ${host}
+			return nil, nil
+		}
+		return &filter{name: name, apply: spec.apply}, nil
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown filter %s", name)
+	}
+
+	args, err := parseFilterArgs(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments of filter %s: %s", name, err)
+	}
+	if len(args) > len(spec.args) || len(args) < len(spec.args)-spec.optional {
+		return nil, fmt.Errorf("filter %s expects %s, got %d", name, spec.arity(), len(args))
+	}
+	for i, arg := range args {
+		kind := stringArg
+		if _, ok := arg.(int); ok {
+			kind = intArg
+		}
+		if kind != spec.args[i] {
+			return nil, fmt.Errorf("argument %d of filter %s must be %s", i+1, name, spec.args[i])
+		}
+	}
+	return &filter{name: name, args: args, apply: spec.apply}, nil
+}
+
+func (s filterSpec) arity() string {
+	required := len(s.args) - s.optional
+	switch {
+	case len(s.args) == 0:
+		return "no arguments"
+	case s.optional == 0 && required == 1:
+		return "1 argument"
+	case s.optional == 0:
+		return fmt.Sprintf("%d arguments", required)
+	default:
+		return fmt.Sprintf("%d to %d arguments", required, len(s.args))
+	}
+}
+
+// parseFilterArgs parses the comma separated arguments of a filter, arguments are either quoted
+// strings or integers.
+func parseFilterArgs(s string) ([]interface{}, error) {
+	args := make([]interface{}, 0)
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return args, nil
+	}
+	for {
+		var arg interface{}
+		if rest[0] == '"' || rest[0] == '\'' {
+			quote := rest[0]
+			var b strings.Builder
+			escape := false
+			end := -1
+			for i := 1; i < len(rest); i++ {
+				c := rest[i]
+				if escape {
+					b.WriteByte(c)
+					escape = false
+					continue
+				}
+				if c == '\\' {
+					escape = true
+					continue
+				}
+				if c == quote {
+					end = i
+					break
+				}
+				b.WriteByte(c)
+			}
+			if end < 0 {
+				return nil, fmt.Errorf("starting %c is missing ending %c", quote, quote)
+			}
+			arg = b.String()
+			rest = strings.TrimSpace(rest[end+1:])
+		} else {
+			idx := strings.IndexByte(rest, ',')
+			if idx < 0 {
+				idx = len(rest)
+			}
+			value := strings.TrimSpace(rest[:idx])
+			i, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("%q is neither a quoted string nor an integer", value)
+			}
+			arg = i
+			rest = rest[idx:]
+		}
+		args = append(args, arg)
+
+		if rest == "" {
+			return args, nil
+		}
+		if rest[0] != ',' {
+			return nil, fmt.Errorf("expected ',' before %q", rest)
+		}
+		rest = strings.TrimSpace(rest[1:])
+		if rest == "" {
+			return nil, fmt.Errorf("missing argument after ','")
+		}
+	}
+}
+
+// applyFilters applies the filters in order to the value of a variable.
+func applyFilters(node Node, filters []*filter) (Node, error) {
+	for _, f := range filters {
+		var err error
+		node, err = f.apply(node, f.args)
+		if err != nil {
+			return nil, fmt.Errorf("filter %s: %s", f.name, err)
+		}
+	}
+	return node, nil
+}
+
+// scalarString returns the string of a value, lists and dictionaries are not supported.
+func scalarString(node Node) (string, error) {
+	switch node.(type) {
+	case *List:
+		return "", fmt.Errorf("expects a single value, got a list")
+	case *Dict:
+		return "", fmt.Errorf("expects a single value, got a dictionary")
+	}
+	return node.String(), nil
+}
+
+func stringFilter(fn func(string) string) func(Node, []interface{}) (Node, error) {
+	return func(node Node, _ []interface{}) (Node, error) {
+		s, err := scalarString(node)
+		if err != nil {
+			return nil, err
+		}
+		return NewStrVal(fn(s)), nil
+	}
+}
+
+func base64DecodeFilter(node Node, _ []interface{}) (Node, error) {
+	s, err := scalarString(node)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("value is not base64 encoded: %s", err)
+	}
+	return NewStrVal(string(decoded)), nil
+}
+
+func splitFilter(node Node, args []interface{}) (Node, error) {
+	s, err := scalarString(node)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(s, args[0].(string))
+	nodes := make([]Node, 0, len(parts))
+	for _, p := range parts {
+		nodes = append(nodes, NewStrVal(p))
+	}
+	return NewList(nodes), nil
+}
+
+func joinFilter(node Node, args []interface{}) (Node, error) {
+	l, ok := node.(*List)
+	if !ok {
+		return nil, fmt.Errorf("expects a list")
+	}
+	parts := make([]string, 0, len(l.value))
+	for _, n := range l.value {
+		s, err := scalarString(n)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, s)
+	}
+	return NewStrVal(strings.Join(parts, args[0].(string))), nil
+}
+
+func replaceFilter(node Node, args []interface{}) (Node, error) {
+	s, err := scalarString(node)
+	if err != nil {
+		return nil, err
+	}
+	return NewStrVal(strings.ReplaceAll(s, args[0].(string), args[1].(string))), nil
+}
+
+// substrFilter returns the characters from start, up to length characters when given.
+func substrFilter(node Node, args []interface{}) (Node, error) {
+	s, err := scalarString(node)
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(s)
+	start := args[0].(int)
+	if start < 0 {
+		return nil, fmt.Errorf("start cannot be negative")
+	}
+	if start > len(runes) {
+		start = len(runes)
+	}
+	end := len(runes)
+	if len(args) > 1 {
+		length := args[1].(int)
+		if length < 0 {
+			return nil, fmt.Errorf("length cannot be negative")
+		}
+		if start+length < end {
+			end = start + length
+		}
+	}
+	return NewStrVal(string(runes[start:end])), nil
+}
+
+// urlFilter turns a host into a URL with the scheme and the port when given, e.g.
+// ${host.ip | url("https", 9200)}.
+func urlFilter(node Node, args []interface{}) (Node, error) {
+	host, err := scalarString(node)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		return nil, fmt.Errorf("host is empty")
+	}
+	if len(args) > 1 {
+		host = net.JoinHostPort(host, strconv.Itoa(args[1].(int)))
+	} else if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+		// IPv6 address
+		host = "[" + host + "]"
+	}
+	u := url.URL{Scheme: args[0].(string), Host: host}
+	return NewStrVal(u.String()), nil
+}
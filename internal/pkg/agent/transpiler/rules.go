@@ -35,9 +35,19 @@ type Rule interface {
 // Apply applies a list of rules over the same tree and use the result of the previous execution
 // as the input of the next rule, will return early if any error is raise during the execution.
 func (r *RuleList) Apply(agentInfo AgentInfo, ast *AST) error {
+	return r.ApplyWithTrace(agentInfo, ast, nil)
+}
+
+// ApplyWithTrace applies the list of rules like Apply and records in the trace, when not nil,
+// the changes each rule made to the tree.
+func (r *RuleList) ApplyWithTrace(agentInfo AgentInfo, ast *AST, trace *RuleTrace) error {
 	var err error
 	for _, rule := range r.Rules {
-		err = rule.Apply(agentInfo, ast)
+		if trace == nil {
+			err = rule.Apply(agentInfo, ast)
+		} else {
+			err = trace.apply(agentInfo, ast, rule)
+		}
 		if err != nil {
 			return err
 		}
@@ -51,52 +61,9 @@ func (r *RuleList) MarshalYAML() (interface{}, error) {
 	doc := make([]map[string]Rule, 0, len(r.Rules))
 
 	for _, rule := range r.Rules {
-		var name string
-		switch rule.(type) {
-		case *SelectIntoRule:
-			name = "select_into"
-		case *CopyRule:
-			name = "copy"
-		case *CopyToListRule:
-			name = "copy_to_list"
-		case *CopyAllToListRule:
-			name = "copy_all_to_list"
-		case *RenameRule:
-			name = "rename"
-		case *TranslateRule:
-			name = "translate"
-		case *TranslateWithRegexpRule:
-			name = "translate_with_regexp"
-		case *MapRule:
-			name = "map"
-		case *FilterRule:
-			name = "filter"
-		case *FilterValuesRule:
-			name = "filter_values"
-		case *FilterValuesWithRegexpRule:
-			name = "filter_values_with_regexp"
-		case *ExtractListItemRule:
-			name = "extract_list_items"
-		case *InjectIndexRule:
-			name = "inject_index"
-		case *InjectStreamProcessorRule:
-			name = "inject_stream_processor"
-		case *InjectAgentInfoRule:
-			name = "inject_agent_info"
-		case *MakeArrayRule:
-			name = "make_array"
-		case *RemoveKeyRule:
-			name = "remove_key"
-		case *FixStreamRule:
-			name = "fix_stream"
-		case *InsertDefaultsRule:
-			name = "insert_defaults"
-		case *InjectHeadersRule:
-			name = "inject_headers"
-		case *InjectQueueRule:
-			name = "inject_queue"
-		default:
-			return nil, fmt.Errorf("unknown rule of type %T", rule)
+		name, err := ruleName(rule)
+		if err != nil {
+			return nil, err
 		}
 
 		subdoc := map[string]Rule{
@@ -108,6 +75,66 @@ func (r *RuleList) MarshalYAML() (interface{}, error) {
 	return doc, nil
 }
 
+// ruleName returns the name used to identify the rule in a spec.
+func ruleName(rule Rule) (string, error) {
+	var name string
+	switch r := rule.(type) {
+	case *SelectIntoRule:
+		name = "select_into"
+	case *CopyRule:
+		name = "copy"
+	case *CopyToListRule:
+		name = "copy_to_list"
+	case *CopyAllToListRule:
+		name = "copy_all_to_list"
+	case *RenameRule:
+		name = "rename"
+	case *TranslateRule:
+		name = "translate"
+	case *TranslateWithRegexpRule:
+		name = "translate_with_regexp"
+	case *MapRule:
+		name = "map"
+	case *FilterRule:
+		name = "filter"
+	case *FilterValuesRule:
+		name = "filter_values"
+	case *FilterValuesWithRegexpRule:
+		name = "filter_values_with_regexp"
+	case *ExtractListItemRule:
+		name = "extract_list_items"
+	case *InjectIndexRule:
+		name = "inject_index"
+	case *InjectStreamProcessorRule:
+		name = "inject_stream_processor"
+	case *InjectAgentInfoRule:
+		name = "inject_agent_info"
+	case *MakeArrayRule:
+		name = "make_array"
+	case *RemoveKeyRule:
+		name = "remove_key"
+	case *FixStreamRule:
+		name = "fix_stream"
+	case *InsertDefaultsRule:
+		name = "insert_defaults"
+	case *InjectHeadersRule:
+		name = "inject_headers"
+	case *InjectQueueRule:
+		name = "inject_queue"
+	case *SetRule:
+		name = "set"
+	case *DeleteIfRule:
+		name = "delete_if"
+	case *TemplateRule:
+		name = "template"
+	case *UserRule:
+		name = r.Name
+	default:
+		return "", fmt.Errorf("unknown rule of type %T", rule)
+	}
+	return name, nil
+}
+
 // UnmarshalYAML unmarshal a YAML document into a RuleList.
 func (r *RuleList) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var unpackTo []map[string]interface{}
@@ -140,52 +167,14 @@ func (r *RuleList) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		name := ks[0]
 		fields := m[name]
 
-		var r Rule
-		switch name {
-		case "select_into":
-			r = &SelectIntoRule{}
-		case "copy":
-			r = &CopyRule{}
-		case "copy_to_list":
-			r = &CopyToListRule{}
-		case "copy_all_to_list":
-			r = &CopyAllToListRule{}
-		case "rename":
-			r = &RenameRule{}
-		case "translate":
-			r = &TranslateRule{}
-		case "translate_with_regexp":
-			r = &TranslateWithRegexpRule{}
-		case "map":
-			r = &MapRule{}
-		case "filter":
-			r = &FilterRule{}
-		case "filter_values":
-			r = &FilterValuesRule{}
-		case "filter_values_with_regexp":
-			r = &FilterValuesWithRegexpRule{}
-		case "extract_list_items":
-			r = &ExtractListItemRule{}
-		case "inject_index":
-			r = &InjectIndexRule{}
-		case "inject_stream_processor":
-			r = &InjectStreamProcessorRule{}
-		case "inject_agent_info":
-			r = &InjectAgentInfoRule{}
-		case "make_array":
-			r = &MakeArrayRule{}
-		case "remove_key":
-			r = &RemoveKeyRule{}
-		case "fix_stream":
-			r = &FixStreamRule{}
-		case "insert_defaults":
-			r = &InsertDefaultsRule{}
-		case "inject_headers":
-			r = &InjectHeadersRule{}
-		case "inject_queue":
-			r = &InjectQueueRule{}
-		default:
-			return fmt.Errorf("unknown rule of type %s", name)
+		r, ok := newBuiltinRule(name)
+		if !ok {
+			userRule, found := lookupUserRule(name)
+			if !found {
+				return fmt.Errorf("unknown rule of type %s", name)
+			}
+			rules = append(rules, userRule)
+			continue
 		}
 
 		if err := unpack(fields, r); err != nil {
@@ -198,6 +187,62 @@ func (r *RuleList) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// newBuiltinRule returns an empty rule of the built-in type identified by name.
+func newBuiltinRule(name string) (Rule, bool) {
+	switch name {
+	case "select_into":
+		return &SelectIntoRule{}, true
+	case "copy":
+		return &CopyRule{}, true
+	case "copy_to_list":
+		return &CopyToListRule{}, true
+	case "copy_all_to_list":
+		return &CopyAllToListRule{}, true
+	case "rename":
+		return &RenameRule{}, true
+	case "translate":
+		return &TranslateRule{}, true
+	case "translate_with_regexp":
+		return &TranslateWithRegexpRule{}, true
+	case "map":
+		return &MapRule{}, true
+	case "filter":
+		return &FilterRule{}, true
+	case "filter_values":
+		return &FilterValuesRule{}, true
+	case "filter_values_with_regexp":
+		return &FilterValuesWithRegexpRule{}, true
+	case "extract_list_items":
+		return &ExtractListItemRule{}, true
+	case "inject_index":
+		return &InjectIndexRule{}, true
+	case "inject_stream_processor":
+		return &InjectStreamProcessorRule{}, true
+	case "inject_agent_info":
+		return &InjectAgentInfoRule{}, true
+	case "make_array":
+		return &MakeArrayRule{}, true
+	case "remove_key":
+		return &RemoveKeyRule{}, true
+	case "fix_stream":
+		return &FixStreamRule{}, true
+	case "insert_defaults":
+		return &InsertDefaultsRule{}, true
+	case "inject_headers":
+		return &InjectHeadersRule{}, true
+	case "inject_queue":
+		return &InjectQueueRule{}, true
+	case "set":
+		return &SetRule{}, true
+	case "delete_if":
+		return &DeleteIfRule{}, true
+	case "template":
+		return &TemplateRule{}, true
+	default:
+		return nil, false
+	}
+}
+
 // SelectIntoRule inserts selected paths into a new Dict node.
 type SelectIntoRule struct {
 	Selectors []Selector
@@ -622,6 +622,84 @@ key1: val1
 			},
 		},
 
+		"set value when condition matches": {
+			givenYAML: `
+output:
+  type: elasticsearch
+`,
+			expectedYAML: `
+output:
+  type: elasticsearch
+  pipeline: internal
+  settings:
+    workers: 2
+`,
+			rule: &RuleList{
+				Rules: []Rule{
+					Set("output.pipeline", "internal", "${output.type} == 'elasticsearch'"),
+					Set("output.settings", map[string]interface{}{"workers": 2}, ""),
+					Set("output.ignored", "value", "${output.type} == 'logstash'"),
+				},
+			},
+		},
+
+		"set value at top level": {
+			givenYAML: `
+key1: val1
+`,
+			expectedYAML: `
+key1: replaced
+key2: val2
+`,
+			rule: &RuleList{
+				Rules: []Rule{
+					Set("key1", "replaced", ""),
+					Set("key2", "val2", ""),
+				},
+			},
+		},
+
+		"delete if condition matches": {
+			givenYAML: `
+output:
+  type: logstash
+  pipeline: internal
+  hosts: [localhost]
+`,
+			expectedYAML: `
+output:
+  type: logstash
+  hosts: [localhost]
+`,
+			rule: &RuleList{
+				Rules: []Rule{
+					DeleteIf("output.pipeline", "${output.type} == 'logstash'"),
+					DeleteIf("output.hosts", "${output.type} == 'elasticsearch'"),
+					DeleteIf("output.missing.key", ""),
+				},
+			},
+		},
+
+		"template from tree values": {
+			givenYAML: `
+name: agent
+output:
+  type: elasticsearch
+`,
+			expectedYAML: `
+name: agent
+output:
+  type: elasticsearch
+  index: agent-elasticsearch
+`,
+			rule: &RuleList{
+				Rules: []Rule{
+					Template("output.index", "${name}-${output.type}", ""),
+					Template("output.skipped", "${missing}", ""),
+				},
+			},
+		},
+
 		"copy item to list": {
 			givenYAML: `
 namespace: testing
@@ -902,6 +980,9 @@ func TestSerialization(t *testing.T) {
 		SelectInto("target", "s1", "s2"),
 		InsertDefaults("target", "s1", "s2"),
 		InjectHeaders(),
+		Set("target", "value", "${key} == 'v'"),
+		DeleteIf("target", "${key} == 'v'"),
+		Template("target", "${key}-suffix", ""),
 	)
 
 	y := `- rename:
@@ -973,6 +1054,17 @@ func TestSerialization(t *testing.T) {
     - s2
     path: target
 - inject_headers: {}
+- set:
+    key: target
+    value: value
+    when: ${key} == 'v'
+- delete_if:
+    key: target
+    when: ${key} == 'v'
+- template:
+    key: target
+    template: ${key}-suffix
+    when: ""
 `
 
 	t.Run("serialize_rules", func(t *testing.T) {
@@ -989,6 +1081,46 @@ func TestSerialization(t *testing.T) {
 	})
 }
 
+func TestUserRule(t *testing.T) {
+	require.NoError(t, RegisterRule("set_pipeline", NewRuleList(
+		Set("output.pipeline", "internal", ""),
+	)))
+	defer UnregisterRule("set_pipeline")
+
+	require.Error(t, RegisterRule("copy", NewRuleList()))
+	require.Error(t, RegisterRule("", NewRuleList()))
+
+	y := `- set_pipeline: {}
+- remove_key:
+    key: name
+`
+	rules := &RuleList{}
+	require.NoError(t, yaml.Unmarshal([]byte(y), rules))
+
+	b, err := yaml.Marshal(rules)
+	require.NoError(t, err)
+	assert.Equal(t, y, string(b))
+
+	ast, err := makeASTFromYAML(`
+name: agent
+output:
+  type: elasticsearch
+`)
+	require.NoError(t, err)
+	require.NoError(t, rules.Apply(FakeAgentInfo(), ast))
+
+	expected, err := makeASTFromYAML(`
+output:
+  type: elasticsearch
+  pipeline: internal
+`)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(ast))
+
+	UnregisterRule("set_pipeline")
+	require.Error(t, yaml.Unmarshal([]byte(y), &RuleList{}))
+}
+
 type fakeAgentInfo struct{}
 
 func (*fakeAgentInfo) AgentID() string {
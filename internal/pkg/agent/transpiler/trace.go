@@ -0,0 +1,192 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transpiler
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TraceOpAdd is used when a rule adds a value to the tree.
+	TraceOpAdd = "add"
+	// TraceOpRemove is used when a rule removes a value from the tree.
+	TraceOpRemove = "remove"
+	// TraceOpReplace is used when a rule replaces a value of the tree.
+	TraceOpReplace = "replace"
+)
+
+// RuleTrace records the changes made by every rule applied through RuleList.ApplyWithTrace.
+type RuleTrace struct {
+	Steps []RuleTraceStep `json:"steps" yaml:"steps"`
+}
+
+// RuleTraceStep describes the application of a single rule.
+type RuleTraceStep struct {
+	// Rule is the name of the rule as used in the spec.
+	Rule string `json:"rule" yaml:"rule"`
+	// Selector is the part of the tree the rule is configured to act on.
+	Selector string `json:"selector,omitempty" yaml:"selector,omitempty"`
+	// Changes is the difference between the tree before and after the rule is applied.
+	Changes []RuleTraceChange `json:"changes" yaml:"changes"`
+	// Error is set when the rule failed to apply.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// RuleTraceChange is a single change made by a rule, path uses the selector notation.
+type RuleTraceChange struct {
+	Op    string      `json:"op" yaml:"op"`
+	Path  string      `json:"path" yaml:"path"`
+	From  interface{} `json:"from,omitempty" yaml:"from,omitempty"`
+	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+func (t *RuleTrace) apply(agentInfo AgentInfo, ast *AST, rule Rule) error {
+	name, err := ruleName(rule)
+	if err != nil {
+		return err
+	}
+
+	before, err := traceMap(ast)
+	if err != nil {
+		return err
+	}
+
+	step := RuleTraceStep{
+		Rule:     name,
+		Selector: ruleSelector(rule),
+	}
+
+	applyErr := rule.Apply(agentInfo, ast)
+	if applyErr != nil {
+		step.Error = applyErr.Error()
+	}
+
+	after, err := traceMap(ast)
+	if err != nil {
+		return err
+	}
+
+	step.Changes = diffValues("", before, after, nil)
+	t.Steps = append(t.Steps, step)
+
+	return applyErr
+}
+
+func traceMap(ast *AST) (map[string]interface{}, error) {
+	if ast.root == nil {
+		return nil, nil
+	}
+	return ast.Map()
+}
+
+// ruleSelector returns the selector the rule is configured with or the part of the tree
+// it acts on for rules without any configuration.
+func ruleSelector(rule Rule) string {
+	switch r := rule.(type) {
+	case *SelectIntoRule:
+		return r.Path
+	case *CopyRule:
+		return r.To
+	case *CopyToListRule:
+		return r.To
+	case *CopyAllToListRule:
+		return r.To
+	case *RenameRule:
+		return r.From
+	case *TranslateRule:
+		return r.Path
+	case *TranslateWithRegexpRule:
+		return r.Path
+	case *MapRule:
+		return r.Path
+	case *FilterRule:
+		return strings.Join(r.Selectors, ",")
+	case *FilterValuesRule:
+		return r.Selector
+	case *FilterValuesWithRegexpRule:
+		return r.Selector
+	case *ExtractListItemRule:
+		return r.To
+	case *MakeArrayRule:
+		return r.To
+	case *RemoveKeyRule:
+		return r.Key
+	case *InsertDefaultsRule:
+		return r.Path
+	case *InjectIndexRule, *InjectStreamProcessorRule, *InjectAgentInfoRule, *FixStreamRule:
+		return "inputs"
+	case *InjectHeadersRule, *InjectQueueRule:
+		return "output"
+	}
+	return ""
+}
+
+func diffValues(path string, before, after interface{}, changes []RuleTraceChange) []RuleTraceChange {
+	if before == nil && after == nil {
+		return changes
+	}
+	if before == nil {
+		return append(changes, RuleTraceChange{Op: TraceOpAdd, Path: path, Value: after})
+	}
+	if after == nil {
+		return append(changes, RuleTraceChange{Op: TraceOpRemove, Path: path, From: before})
+	}
+
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(a)+len(b))
+		for k := range b {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, found := b[k]; !found {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			changes = diffValues(joinTracePath(path, k), b[k], a[k], changes)
+		}
+		return changes
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < len(a) || i < len(b); i++ {
+			var bv, av interface{}
+			if i < len(b) {
+				bv = b[i]
+			}
+			if i < len(a) {
+				av = a[i]
+			}
+			changes = diffValues(joinTracePath(path, strconv.Itoa(i)), bv, av, changes)
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		changes = append(changes, RuleTraceChange{Op: TraceOpReplace, Path: path, From: before, Value: after})
+	}
+	return changes
+}
+
+func joinTracePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,81 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transpiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleListApplyWithTrace(t *testing.T) {
+	ast, err := NewAST(map[string]interface{}{
+		"inputs": []interface{}{
+			map[string]interface{}{
+				"type": "log",
+				"streams": []interface{}{
+					map[string]interface{}{"paths": "/var/log/syslog"},
+				},
+			},
+		},
+		"output": map[string]interface{}{
+			"elasticsearch": map[string]interface{}{"hosts": "localhost:9200"},
+		},
+	})
+	require.NoError(t, err)
+
+	rules := NewRuleList(
+		FixStream(),
+		RemoveKey("output"),
+		Rename("inputs", "filebeat"),
+	)
+
+	trace := &RuleTrace{}
+	require.NoError(t, rules.ApplyWithTrace(&fakeAgentInfo{}, ast, trace))
+	require.Len(t, trace.Steps, 3)
+
+	fixStream := trace.Steps[0]
+	assert.Equal(t, "fix_stream", fixStream.Rule)
+	assert.Equal(t, "inputs", fixStream.Selector)
+	assert.Equal(t, []RuleTraceChange{
+		{Op: TraceOpAdd, Path: "inputs.0.data_stream.namespace", Value: "default"},
+		{Op: TraceOpAdd, Path: "inputs.0.streams.0.data_stream.dataset", Value: "generic"},
+	}, fixStream.Changes)
+
+	removeKey := trace.Steps[1]
+	assert.Equal(t, "remove_key", removeKey.Rule)
+	assert.Equal(t, "output", removeKey.Selector)
+	assert.Equal(t, []RuleTraceChange{{
+		Op:   TraceOpRemove,
+		Path: "output",
+		From: map[string]interface{}{
+			"elasticsearch": map[string]interface{}{"hosts": "localhost:9200"},
+		},
+	}}, removeKey.Changes)
+
+	rename := trace.Steps[2]
+	assert.Equal(t, "rename", rename.Rule)
+	require.Len(t, rename.Changes, 2)
+	assert.Equal(t, TraceOpAdd, rename.Changes[0].Op)
+	assert.Equal(t, "filebeat", rename.Changes[0].Path)
+	assert.Equal(t, TraceOpRemove, rename.Changes[1].Op)
+	assert.Equal(t, "inputs", rename.Changes[1].Path)
+
+	// same result as applying without a trace
+	expected, err := NewAST(map[string]interface{}{
+		"inputs": []interface{}{
+			map[string]interface{}{
+				"type": "log",
+				"streams": []interface{}{
+					map[string]interface{}{"paths": "/var/log/syslog"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, rules.Apply(&fakeAgentInfo{}, expected))
+	assert.Equal(t, expected.Hash(), ast.Hash())
+}
@@ -0,0 +1,251 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package transpiler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/eql"
+)
+
+var userRules = struct {
+	sync.RWMutex
+	rules map[string]*UserRule
+}{rules: make(map[string]*UserRule)}
+
+// UserRule is a named list of rules registered at runtime, specs reference it by its name like any
+// built-in rule.
+type UserRule struct {
+	Name  string
+	Rules *RuleList
+}
+
+// Apply applies all the rules of the user rule.
+func (r *UserRule) Apply(agentInfo AgentInfo, ast *AST) error {
+	if err := r.Rules.Apply(agentInfo, ast); err != nil {
+		return errors.New(err, fmt.Sprintf("failed to apply rule '%s'", r.Name))
+	}
+	return nil
+}
+
+// MarshalYAML only keeps the reference to the user rule, the rules are defined by the registration.
+func (r *UserRule) MarshalYAML() (interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+// RegisterRule registers a list of rules under a name that specs can then use as a rule identifier.
+// Registering an already registered name replaces the rules for the specs parsed afterward, names
+// of the built-in rules cannot be used.
+func RegisterRule(name string, rules *RuleList) error {
+	if name == "" {
+		return fmt.Errorf("rule name cannot be empty")
+	}
+	if _, ok := newBuiltinRule(name); ok {
+		return fmt.Errorf("rule '%s' conflicts with a built-in rule", name)
+	}
+	if rules == nil {
+		rules = &RuleList{}
+	}
+
+	userRules.Lock()
+	defer userRules.Unlock()
+	userRules.rules[name] = &UserRule{Name: name, Rules: rules}
+	return nil
+}
+
+// UnregisterRule removes a rule registered with RegisterRule.
+func UnregisterRule(name string) {
+	userRules.Lock()
+	defer userRules.Unlock()
+	delete(userRules.rules, name)
+}
+
+func lookupUserRule(name string) (*UserRule, bool) {
+	userRules.RLock()
+	defer userRules.RUnlock()
+	r, ok := userRules.rules[name]
+	return r, ok
+}
+
+// SetRule sets a value at the key when the condition matches the tree.
+type SetRule struct {
+	Key   string
+	Value interface{}
+	When  string
+}
+
+// Apply applies set rule.
+func (r *SetRule) Apply(_ AgentInfo, ast *AST) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.New(err, "failed to set value into configuration")
+		}
+	}()
+
+	if ok, err := matchCondition(r.When, ast); !ok || err != nil {
+		return err
+	}
+
+	node, err := loadForNew(r.Value)
+	if err != nil {
+		return err
+	}
+
+	return setKey(ast, r.Key, node)
+}
+
+// Set creates a SetRule.
+func Set(key string, value interface{}, when string) *SetRule {
+	return &SetRule{
+		Key:   key,
+		Value: value,
+		When:  when,
+	}
+}
+
+// DeleteIfRule removes the key when the condition matches the tree.
+type DeleteIfRule struct {
+	Key  string
+	When string
+}
+
+// Apply applies delete if rule.
+func (r *DeleteIfRule) Apply(_ AgentInfo, ast *AST) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.New(err, "failed to delete key from configuration")
+		}
+	}()
+
+	if ok, err := matchCondition(r.When, ast); !ok || err != nil {
+		return err
+	}
+
+	removeKey(ast, r.Key)
+	return nil
+}
+
+// DeleteIf creates a DeleteIfRule.
+func DeleteIf(key, when string) *DeleteIfRule {
+	return &DeleteIfRule{
+		Key:  key,
+		When: when,
+	}
+}
+
+// TemplateRule renders a string with variables resolved from the tree and sets the result at the
+// key when the condition matches. Nothing is set when a variable of the template is missing.
+type TemplateRule struct {
+	Key      string
+	Template string
+	When     string
+}
+
+// Apply applies template rule.
+func (r *TemplateRule) Apply(_ AgentInfo, ast *AST) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.New(err, "failed to render template into configuration")
+		}
+	}()
+
+	if ok, err := matchCondition(r.When, ast); !ok || err != nil {
+		return err
+	}
+
+	vars := &Vars{tree: ast}
+	node, err := vars.Replace(r.Template)
+	if err == ErrNoMatch {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return setKey(ast, r.Key, node)
+}
+
+// Template creates a TemplateRule.
+func Template(key, template, when string) *TemplateRule {
+	return &TemplateRule{
+		Key:      key,
+		Template: template,
+		When:     when,
+	}
+}
+
+// matchCondition evaluates the EQL condition against the tree, an empty condition always matches.
+func matchCondition(condition string, ast *AST) (bool, error) {
+	if condition == "" {
+		return true, nil
+	}
+
+	ok, err := eql.Eval(condition, ast)
+	if err != nil {
+		return false, fmt.Errorf(`condition "%s" evaluation failed: %s`, condition, err)
+	}
+	return ok, nil
+}
+
+// setKey sets the node at the selector replacing any existing value.
+func setKey(ast *AST, selector Selector, node Node) error {
+	parent, name := splitSelector(selector)
+	if parent == "" {
+		root, ok := ast.root.(*Dict)
+		if !ok {
+			return fmt.Errorf("expecting Dict and received %T for '%s'", ast.root, selector)
+		}
+		root.value = withoutKey(root.value, name)
+		root.value = append(root.value, &Key{name: name, value: node})
+		root.sort()
+		return nil
+	}
+
+	return Insert(ast, &Key{name: name, value: node}, parent)
+}
+
+// removeKey removes the key at the selector, nothing is done when the key doesn't exist.
+func removeKey(ast *AST, selector Selector) {
+	parent, name := splitSelector(selector)
+
+	var container Node = ast.root
+	if parent != "" {
+		n, ok := Lookup(ast, parent)
+		if !ok {
+			return
+		}
+		container = n
+	}
+
+	if k, ok := container.(*Key); ok {
+		container = k.value
+	}
+
+	d, ok := container.(*Dict)
+	if !ok {
+		return
+	}
+	d.value = withoutKey(d.value, name)
+}
+
+func splitSelector(selector Selector) (string, string) {
+	idx := strings.LastIndex(selector, selectorSep)
+	if idx == -1 {
+		return "", selector
+	}
+	return selector[:idx], selector[idx+1:]
+}
+
+func withoutKey(nodes []Node, name string) []Node {
+	for i, n := range nodes {
+		if k, ok := n.(*Key); ok && k.name == name {
+			return append(nodes[:i:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
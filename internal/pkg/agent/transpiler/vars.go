@@ -15,7 +15,10 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common"
 )
 
-var varsRegex = regexp.MustCompile(`\${([\p{L}\d\s\\\-_|.'"]*)}`)
+// varsRegex matches a variable, the arguments of a filter are only accepted inside its parentheses so
+// ${ES_HOST:localhost:9200} is left for the programs to expand. Quoted arguments can contain anything but
+// their unescaped quote, invalid arguments are reported by the filter.
+var varsRegex = regexp.MustCompile(`\${([\p{L}\d\s\\\-_|.'"]*(?:\|\s*[a-z][a-z0-9_]*\s*\((?:"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|[^"'(){}|])*\)[\p{L}\d\s\\\-_|.'"]*)*)}`)
 
 // ErrNoMatch is return when the replace didn't fail, just that no vars match to perform the replace.
 var ErrNoMatch = fmt.Errorf("no matching vars")
@@ -53,39 +56,41 @@ func (v *Vars) Replace(value string) (Node, error) {
 	lastIndex := 0
 	for _, r := range matchIdxs {
 		for i := 0; i < len(r); i += 4 {
-			vars, err := extractVars(value[r[i+2]:r[i+3]])
+			vars, filters, err := extractVars(value[r[i+2]:r[i+3]])
 			if err != nil {
 				return nil, fmt.Errorf(`error parsing variable "%s": %s`, value[r[i]:r[i+1]], err)
 			}
-			set := false
+			var node Node
 			for _, val := range vars {
 				switch val.(type) {
 				case *constString:
-					result += value[lastIndex:r[0]] + val.Value()
-					set = true
+					node = NewStrVal(val.Value())
 				case *varString:
-					node, ok := v.lookupNode(val.Value())
+					n, ok := v.lookupNode(val.Value())
 					if ok {
-						node := nodeToValue(node)
+						node = nodeToValue(n)
 						if v.processorsKey != "" && varPrefixMatched(val.Value(), v.processorsKey) {
 							processors = v.processors
 						}
-						if r[i] == 0 && r[i+1] == len(value) {
-							// possible for complete replacement of object, because the variable
-							// is not inside of a string
-							return attachProcessors(node, processors), nil
-						}
-						result += value[lastIndex:r[0]] + node.String()
-						set = true
 					}
 				}
-				if set {
+				if node != nil {
 					break
 				}
 			}
-			if !set {
+			if node == nil {
 				return NewStrVal(""), ErrNoMatch
 			}
+			node, err = applyFilters(node, filters)
+			if err != nil {
+				return nil, fmt.Errorf(`error applying filters of variable "%s": %s`, value[r[i]:r[i+1]], err)
+			}
+			if r[i] == 0 && r[i+1] == len(value) {
+				// possible for complete replacement of object, because the variable
+				// is not inside of a string
+				return attachProcessors(node, processors), nil
+			}
+			result += value[lastIndex:r[0]] + node.String()
 			lastIndex = r[1]
 		}
 	}
@@ -164,35 +169,62 @@ func (v *constString) Value() string {
 	return v.value
 }
 
-func extractVars(i string) ([]varI, error) {
+// extractVars parses the content of a variable, the fallbacks are returned in order followed by the
+// filters applied to the first of them found.
+func extractVars(i string) ([]varI, []*filter, error) {
 	const out = rune(0)
 
 	quote := out
 	constant := false
 	escape := false
 	is := make([]rune, 0, len(i))
+	// raw is the segment as written, filter arguments are parsed from it
+	raw := make([]rune, 0, len(i))
 	res := make([]varI, 0)
+	var filters []*filter
+	endSegment := func() error {
+		defer func() {
+			is = is[:0] // slice to zero length; to keep allocated memory
+			raw = raw[:0]
+			constant = false
+		}()
+		f, err := parseFilter(strings.TrimSpace(string(raw)), len(res) == 0 && len(filters) == 0)
+		if err != nil {
+			return err
+		}
+		if f != nil {
+			filters = append(filters, f)
+			return nil
+		}
+		if len(filters) > 0 && (constant || len(is) > 0) {
+			return fmt.Errorf("fallback %q cannot follow a filter", strings.TrimSpace(string(raw)))
+		}
+		if constant {
+			res = append(res, &constString{string(is)})
+		} else if len(is) > 0 {
+			if is[len(is)-1] == '.' {
+				return fmt.Errorf("variable cannot end with '.'")
+			}
+			res = append(res, &varString{string(is)})
+		}
+		return nil
+	}
 	for _, r := range i {
 		if r == '|' {
 			if escape {
-				return nil, fmt.Errorf(`variable pipe cannot be escaped; remove \ before |`)
+				return nil, nil, fmt.Errorf(`variable pipe cannot be escaped; remove \ before |`)
 			}
 			if quote == out {
-				if constant {
-					res = append(res, &constString{string(is)})
-				} else if len(is) > 0 {
-					if is[len(is)-1] == '.' {
-						return nil, fmt.Errorf("variable cannot end with '.'")
-					}
-					res = append(res, &varString{string(is)})
+				if err := endSegment(); err != nil {
+					return nil, nil, err
 				}
-				is = is[:0] // slice to zero length; to keep allocated memory
-				constant = false
 			} else {
 				is = append(is, r)
+				raw = append(raw, r)
 			}
 			continue
 		}
+		raw = append(raw, r)
 		if !escape && (r == '"' || r == '\'') {
 			if quote == out {
 				// start of unescaped quote
@@ -217,17 +249,15 @@ func extractVars(i string) ([]varI, error) {
 		}
 	}
 	if quote != out {
-		return nil, fmt.Errorf(`starting %s is missing ending %s`, string(quote), string(quote))
+		return nil, nil, fmt.Errorf(`starting %s is missing ending %s`, string(quote), string(quote))
 	}
-	if constant {
-		res = append(res, &constString{string(is)})
-	} else if len(is) > 0 {
-		if is[len(is)-1] == '.' {
-			return nil, fmt.Errorf("variable cannot end with '.'")
-		}
-		res = append(res, &varString{string(is)})
+	if err := endSegment(); err != nil {
+		return nil, nil, err
+	}
+	if len(filters) > 0 && len(res) == 0 {
+		return nil, nil, fmt.Errorf("filter %s has no variable to apply to", filters[0].name)
 	}
-	return res, nil
+	return res, filters, nil
 }
 
 func varPrefixMatched(val string, key string) bool {
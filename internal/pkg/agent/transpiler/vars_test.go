@@ -192,6 +192,232 @@ func TestVars_Replace(t *testing.T) {
 	}
 }
 
+func TestVars_ReplaceWithFilters(t *testing.T) {
+	vars := mustMakeVars(map[string]interface{}{
+		"kubernetes": map[string]interface{}{
+			"pod": map[string]interface{}{
+				"name": "Nginx-7C9F",
+				"ip":   "10.0.0.12",
+			},
+			"secret": "c2VjcmV0LXZhbHVl",
+			"list": []string{
+				"array1",
+				"array2",
+			},
+		},
+		"env": map[string]interface{}{
+			"HOSTS": "host1:9200, host2:9200",
+			"IPV6":  "fe80::1",
+		},
+		"dict": map[string]interface{}{
+			"key1": "value1",
+		},
+	})
+	tests := []struct {
+		Input  string
+		Result Node
+		Error  string
+	}{
+		{
+			Input:  "${kubernetes.pod.name | lower}",
+			Result: NewStrVal("nginx-7c9f"),
+		},
+		{
+			Input:  "${kubernetes.pod.name|upper}",
+			Result: NewStrVal("NGINX-7C9F"),
+		},
+		{
+			Input:  "${kubernetes.missing | kubernetes.pod.name | lower}",
+			Result: NewStrVal("nginx-7c9f"),
+		},
+		{
+			Input:  "${kubernetes.missing | 'Default' | lower}",
+			Result: NewStrVal("default"),
+		},
+		{
+			Input: `${env.HOSTS | split(",")}`,
+			Result: NewList([]Node{
+				NewStrVal("host1:9200"),
+				NewStrVal(" host2:9200"),
+			}),
+		},
+		{
+			Input:  `${env.HOSTS | replace(" ", "") | split(",") | join("|")}`,
+			Result: NewStrVal("host1:9200|host2:9200"),
+		},
+		{
+			Input:  `${kubernetes.list | join(', ')}`,
+			Result: NewStrVal("array1, array2"),
+		},
+		{
+			Input:  `${kubernetes.list | join(" | ")}`,
+			Result: NewStrVal("array1 | array2"),
+		},
+		{
+			Input:  `${env.HOSTS | split(";")}`,
+			Result: NewList([]Node{NewStrVal("host1:9200, host2:9200")}),
+		},
+		{
+			Input:  `${env.HOSTS | replace(", ", "=") | replace(":", "}{")}`,
+			Result: NewStrVal("host1}{9200=host2}{9200"),
+		},
+		{
+			Input:  "${kubernetes.secret | base64decode}",
+			Result: NewStrVal("secret-value"),
+		},
+		{
+			Input:  "${kubernetes.pod.name | base64encode | base64decode}",
+			Result: NewStrVal("Nginx-7C9F"),
+		},
+		{
+			Input:  "${kubernetes.pod.name | substr(0, 5)}",
+			Result: NewStrVal("Nginx"),
+		},
+		{
+			Input:  "${kubernetes.pod.name | substr(6)}",
+			Result: NewStrVal("7C9F"),
+		},
+		{
+			Input:  "${kubernetes.pod.name | substr(20, 2)}",
+			Result: NewStrVal(""),
+		},
+		{
+			Input:  `${kubernetes.pod.ip | url("https", 9200)}`,
+			Result: NewStrVal("https://10.0.0.12:9200"),
+		},
+		{
+			Input:  `${env.IPV6 | url("http")}`,
+			Result: NewStrVal("http://[fe80::1]"),
+		},
+		{
+			Input:  `${'a b' | urlencode}`,
+			Result: NewStrVal("a+b"),
+		},
+		{
+			Input:  "${'  padded  ' | trim}",
+			Result: NewStrVal("padded"),
+		},
+		{
+			Input:  "pod ${kubernetes.pod.name | lower} on ${kubernetes.pod.ip}",
+			Result: NewStrVal("pod nginx-7c9f on 10.0.0.12"),
+		},
+		{
+			Input: "${kubernetes.pod.name | reverse()}",
+			Error: `error parsing variable "${kubernetes.pod.name | reverse()}": unknown filter reverse`,
+		},
+		{
+			Input: "${kubernetes.pod.name | split()}",
+			Error: `error parsing variable "${kubernetes.pod.name | split()}": filter split expects 1 argument, got 0`,
+		},
+		{
+			Input: "${kubernetes.pod.name | substr('a')}",
+			Error: `error parsing variable "${kubernetes.pod.name | substr('a')}": argument 1 of filter substr must be an integer`,
+		},
+		{
+			Input: "${kubernetes.pod.name | substr(1,)}",
+			Error: `error parsing variable "${kubernetes.pod.name | substr(1,)}": invalid arguments of filter substr: missing argument after ','`,
+		},
+		{
+			Input: "${kubernetes.pod.name | split(;)}",
+			Error: `error parsing variable "${kubernetes.pod.name | split(;)}": invalid arguments of filter split: ";" is neither a quoted string nor an integer`,
+		},
+		{
+			Input: "${kubernetes.pod.name | split(','}",
+			Error: "starting ${ is missing ending }",
+		},
+		{
+			Input: "${kubernetes.pod.name | lower | kubernetes.pod.ip}",
+			Error: `error parsing variable "${kubernetes.pod.name | lower | kubernetes.pod.ip}": fallback "kubernetes.pod.ip" cannot follow a filter`,
+		},
+		{
+			Input: "${ | lower()}",
+			Error: `error parsing variable "${ | lower()}": filter lower has no variable to apply to`,
+		},
+		{
+			Input: "${kubernetes.list | lower}",
+			Error: `error applying filters of variable "${kubernetes.list | lower}": filter lower: expects a single value, got a list`,
+		},
+		{
+			Input: "${dict | join(',')}",
+			Error: `error applying filters of variable "${dict | join(',')}": filter join: expects a list`,
+		},
+		{
+			Input: "${kubernetes.pod.name | base64decode}",
+			Error: `error applying filters of variable "${kubernetes.pod.name | base64decode}": filter base64decode: value is not base64 encoded: illegal base64 data at input byte 5`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			res, err := vars.Replace(test.Input)
+			if test.Error != "" {
+				assert.EqualError(t, err, test.Error)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.Result, res)
+			}
+		})
+	}
+
+	_, err := vars.Replace("${kubernetes.missing | lower}")
+	assert.Equal(t, ErrNoMatch, err)
+}
+
+func TestVars_ReplaceFilterNames(t *testing.T) {
+	vars := mustMakeVars(map[string]interface{}{
+		"url":   "http://localhost:9200",
+		"lower": "Lower",
+		"host": map[string]interface{}{
+			"name": "Host",
+		},
+	})
+	tests := []struct {
+		Input  string
+		Result Node
+	}{
+		{
+			Input:  "${url}",
+			Result: NewStrVal("http://localhost:9200"),
+		},
+		{
+			Input:  "${lower}",
+			Result: NewStrVal("Lower"),
+		},
+		{
+			Input:  "${host.missing | url}",
+			Result: NewStrVal("http://localhost:9200"),
+		},
+		{
+			Input:  "${host.name | lower}",
+			Result: NewStrVal("host"),
+		},
+		{
+			Input:  "${host.name | split}",
+			Result: NewStrVal("Host"),
+		},
+		{
+			Input:  "${host.name} ${ES_HOST:localhost:9200}",
+			Result: NewStrVal("Host ${ES_HOST:localhost:9200}"),
+		},
+		{
+			Input:  "${host.name} ${path.home/data}",
+			Result: NewStrVal("Host ${path.home/data}"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			res, err := vars.Replace(test.Input)
+			require.NoError(t, err)
+			assert.Equal(t, test.Result, res)
+		})
+	}
+
+	// a filter following a missing variable is never looked up as a variable, the value would be unfiltered
+	for _, input := range []string{"${host.missing | lower}", "${host.missing | lower | upper}", "${host.missing | lower()}"} {
+		_, err := vars.Replace(input)
+		assert.Equal(t, ErrNoMatch, err, input)
+	}
+}
+
 func TestVars_ReplaceWithProcessors(t *testing.T) {
 	processers := Processors{
 		{
@@ -319,6 +545,10 @@ func TestVars_ReplaceWithFetchContextProvider(t *testing.T) {
 	res, err = vars.Replace("${kubernetes_secrets.test_namespace.testing_secret.secret_value}")
 	require.NoError(t, err)
 	assert.Equal(t, NewStrVal("mockedFetchContent"), res)
+
+	res, err = vars.Replace("${kubernetes_secrets.test_namespace.testing_secret.secret_value | upper}")
+	require.NoError(t, err)
+	assert.Equal(t, NewStrVal("MOCKEDFETCHCONTENT"), res)
 }
 
 type contextProviderMock struct {
@@ -5,8 +5,11 @@
 package capabilities
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"sync"
 
 	"github.com/elastic/elastic-agent/internal/pkg/core/state"
 
@@ -29,47 +32,87 @@ var (
 	ErrBlocked = errors.New("capability blocked")
 )
 
+// Reloadable is a capability loaded from a file that can be loaded again when the file changes.
+type Reloadable interface {
+	Capability
+
+	// Reload loads the capabilities from the file again, the current capabilities are kept when the
+	// file is invalid.
+	Reload() error
+
+	// Subscribe returns a channel notified every time the capabilities are reloaded, the
+	// subscription ends when the context is done.
+	Subscribe(ctx context.Context) <-chan struct{}
+}
+
 type capabilitiesManager struct {
-	caps     []Capability
-	reporter status.Reporter
+	lock        sync.RWMutex
+	caps        []Capability
+	reporter    status.Reporter
+	log         *logger.Logger
+	capsFile    string
+	loadErr     error
+	subscribers map[chan struct{}]struct{}
 }
 
 type capabilityFactory func(*logger.Logger, *ruleDefinitions, status.Reporter) (Capability, error)
 
 // Load loads capabilities files and prepares manager.
 func Load(capsFile string, log *logger.Logger, sc status.Controller) (Capability, error) {
+	cm := &capabilitiesManager{
+		caps:        make([]Capability, 0),
+		reporter:    sc.RegisterComponentWithPersistance("capabilities", true),
+		log:         log,
+		capsFile:    capsFile,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+
+	caps, err := load(capsFile, log, cm.reporter, hostInfo)
+	if err != nil {
+		return cm, err
+	}
+
+	cm.caps = caps
+	return cm, nil
+}
+
+func load(capsFile string, log *logger.Logger, reporter status.Reporter, info hostInfoFunc) ([]Capability, error) {
 	handlers := []capabilityFactory{
 		newInputsCapability,
 		newOutputsCapability,
 		newUpgradesCapability,
+		newUpgradeWindowsCapability,
+		// applied after the inputs capabilities so blocked inputs are not counted
+		newMaxInputsCapability,
 	}
 
-	cm := &capabilitiesManager{
-		caps:     make([]Capability, 0),
-		reporter: sc.RegisterComponentWithPersistance("capabilities", true),
-	}
+	caps := make([]Capability, 0)
 
 	// load capabilities from file
 	fd, err := os.Open(capsFile)
 	if err != nil && !os.IsNotExist(err) {
-		return cm, err
+		return nil, err
 	}
 
 	if os.IsNotExist(err) {
 		log.Infof("capabilities file not found in %s", capsFile)
-		return cm, nil
+		return caps, nil
 	}
 	defer fd.Close()
 
 	definitions := &ruleDefinitions{Capabilities: make([]ruler, 0)}
 	dec := yaml.NewDecoder(fd)
 	if err := dec.Decode(&definitions); err != nil {
-		return cm, err
+		return nil, err
+	}
+
+	if err := filterByHost(log, definitions, info); err != nil {
+		return nil, err
 	}
 
 	// make list of handlers out of capabilities definition
 	for _, h := range handlers {
-		cap, err := h(log, definitions, cm.reporter)
+		cap, err := h(log, definitions, reporter)
 		if err != nil {
 			return nil, err
 		}
@@ -78,16 +121,72 @@ func Load(capsFile string, log *logger.Logger, sc status.Controller) (Capability
 			continue
 		}
 
-		cm.caps = append(cm.caps, cap)
+		caps = append(caps, cap)
 	}
 
-	return cm, nil
+	return caps, nil
+}
+
+// Reload loads the capabilities file again and notifies the subscribers, a failure is reported as
+// degraded until the file is successfully loaded.
+func (mgr *capabilitiesManager) Reload() error {
+	caps, err := load(mgr.capsFile, mgr.log, mgr.reporter, hostInfo)
+
+	mgr.lock.Lock()
+	defer mgr.lock.Unlock()
+
+	if err != nil {
+		mgr.loadErr = fmt.Errorf("failed to load capabilities from %s: %w", mgr.capsFile, err)
+		mgr.reporter.Update(state.Degraded, mgr.loadErr.Error(), nil)
+		return mgr.loadErr
+	}
+
+	mgr.caps = caps
+	mgr.loadErr = nil
+	mgr.reporter.Update(state.Healthy, "", nil)
+
+	for ch := range mgr.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// a notification is already pending
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel notified every time the capabilities are reloaded.
+func (mgr *capabilitiesManager) Subscribe(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	mgr.lock.Lock()
+	if mgr.subscribers == nil {
+		mgr.subscribers = make(map[chan struct{}]struct{})
+	}
+	mgr.subscribers[ch] = struct{}{}
+	mgr.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		mgr.lock.Lock()
+		delete(mgr.subscribers, ch)
+		mgr.lock.Unlock()
+	}()
+
+	return ch
 }
 
 func (mgr *capabilitiesManager) Apply(in interface{}) (interface{}, error) {
+	mgr.lock.RLock()
+	defer mgr.lock.RUnlock()
+
 	var err error
 	// reset health on start, child caps will update to fail if needed
-	mgr.reporter.Update(state.Healthy, "", nil)
+	if mgr.loadErr != nil {
+		mgr.reporter.Update(state.Degraded, mgr.loadErr.Error(), nil)
+	} else {
+		mgr.reporter.Update(state.Healthy, "", nil)
+	}
 	for _, cap := range mgr.caps {
 		in, err = cap.Apply(in)
 		if err != nil {
@@ -27,6 +27,7 @@ func TestLoadCapabilities(t *testing.T) {
 		"allow_metrics",
 		"deny_logs",
 		"no_caps",
+		"max_inputs",
 	}
 
 	l, _ := logger.New("test", false)
@@ -0,0 +1,102 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// Kinds of the objects a decision is taken for.
+const (
+	InputKind   = "input"
+	OutputKind  = "output"
+	UpgradeKind = "upgrade"
+)
+
+// Dependencies of a decision besides the configuration or the upgrade action.
+const (
+	// DependsOnHost marks the decisions of rules restricted by a host condition.
+	DependsOnHost = "host"
+	// DependsOnTime marks the decisions of upgrade windows.
+	DependsOnTime = "time"
+)
+
+// Decision is the result of a rule applied to an input, an output or an upgrade.
+type Decision struct {
+	// Kind is one of input, output or upgrade.
+	Kind string `json:"kind" yaml:"kind"`
+	// Target is the type of the input, the name of the output or the version of the upgrade.
+	Target string `json:"target" yaml:"target"`
+	// Rule is the name of the rule taking the decision.
+	Rule    string `json:"rule" yaml:"rule"`
+	Allowed bool   `json:"allowed" yaml:"allowed"`
+	// Depends lists what the decision depends on besides its target, DependsOnHost or DependsOnTime.
+	Depends []string `json:"depends,omitempty" yaml:"depends,omitempty"`
+}
+
+// CheckEnv is the host and the time the capabilities are checked for, the host running the check
+// and the current time are used when they are not set.
+type CheckEnv struct {
+	// Host replaces the values of the host provider the host conditions are evaluated against.
+	Host map[string]interface{}
+	// Time is the time upgrade windows are evaluated at.
+	Time time.Time
+}
+
+// decisionRecorder is implemented by the reporters keeping the decisions taken by the rules.
+type decisionRecorder interface {
+	recordDecision(Decision)
+}
+
+func recordDecision(reporter status.Reporter, d Decision) {
+	if r, ok := reporter.(decisionRecorder); ok {
+		r.recordDecision(d)
+	}
+}
+
+// decisionsReporter records the decisions instead of updating the status of the agent.
+type decisionsReporter struct {
+	decisions []Decision
+}
+
+func (r *decisionsReporter) Update(state.Status, string, map[string]interface{}) {}
+
+func (r *decisionsReporter) Unregister() {}
+
+func (r *decisionsReporter) recordDecision(d Decision) {
+	r.decisions = append(r.decisions, d)
+}
+
+// Check loads the capabilities file and applies it to a configuration or an upgrade action, the
+// decisions taken by the rules are returned with the result. ErrBlocked is returned when the
+// upgrade is blocked.
+func Check(capsFile string, log *logger.Logger, in interface{}, env CheckEnv) (interface{}, []Decision, error) {
+	info := hostInfoFunc(hostInfo)
+	if env.Host != nil {
+		info = func() (map[string]interface{}, error) { return env.Host, nil }
+	}
+
+	reporter := &decisionsReporter{decisions: []Decision{}}
+	caps, err := load(capsFile, log, reporter, info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !env.Time.IsZero() {
+		for _, c := range caps {
+			if windows, ok := c.(*multiUpgradeWindowCapability); ok {
+				windows.setTime(env.Time)
+			}
+		}
+	}
+
+	mgr := &capabilitiesManager{caps: caps, reporter: reporter}
+	out, err := mgr.Apply(in)
+	return out, reporter.decisions, err
+}
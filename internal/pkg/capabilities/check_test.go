@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/fleetapi"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestCheck(t *testing.T) {
+	l, _ := logger.New("test", false)
+
+	t.Run("policy", func(t *testing.T) {
+		cfg, closer := getConfigWithCloser(t, filepath.Join("testdata", "deny_logs-config.yml"))
+		defer closer.Close()
+
+		mm, err := cfg.ToMapStr()
+		require.NoError(t, err)
+
+		_, decisions, err := Check(filepath.Join("testdata", "deny_logs-capabilities.yml"), l, mm, CheckEnv{})
+		require.NoError(t, err)
+		assert.Equal(t, []Decision{
+			{Kind: InputKind, Target: "system/logs", Rule: "I deny(system/logs)", Allowed: false},
+		}, decisions)
+	})
+
+	t.Run("upgrade", func(t *testing.T) {
+		capsFile := filepath.Join(t.TempDir(), "capabilities.yml")
+		caps := `capabilities:
+- rule: allow
+  upgrade: "${version} == '8.0.0'"
+- rule: deny
+  upgrade: "${version} == '8.1.0'"
+`
+		require.NoError(t, ioutil.WriteFile(capsFile, []byte(caps), 0600))
+
+		_, decisions, err := Check(capsFile, l, &fleetapi.ActionUpgrade{Version: "8.0.0"}, CheckEnv{})
+		require.NoError(t, err)
+		assert.Equal(t, []Decision{
+			{Kind: UpgradeKind, Target: "8.0.0", Rule: "UA(${version} == '8.0.0')", Allowed: true},
+			{Kind: UpgradeKind, Target: "8.0.0", Rule: "UD(${version} == '8.1.0')", Allowed: true},
+		}, decisions)
+
+		// the first rule blocking the upgrade stops the evaluation
+		_, decisions, err = Check(capsFile, l, &fleetapi.ActionUpgrade{Version: "8.1.0"}, CheckEnv{})
+		assert.ErrorIs(t, err, ErrBlocked)
+		assert.Equal(t, []Decision{
+			{Kind: UpgradeKind, Target: "8.1.0", Rule: "UA(${version} == '8.0.0')", Allowed: false},
+		}, decisions)
+	})
+
+	t.Run("host and time", func(t *testing.T) {
+		defer func(f func() (map[string]interface{}, error)) { hostInfo = f }(hostInfo)
+		hostInfo = func() (map[string]interface{}, error) {
+			return nil, errors.New("host of the machine running the check")
+		}
+
+		capsFile := filepath.Join(t.TempDir(), "capabilities.yml")
+		caps := `capabilities:
+- rule: deny
+  upgrade: "${version} == '8.1.0'"
+  host: "${host.platform} == 'windows'"
+- rule: allow
+  upgrade_window:
+    start: "02:00"
+    end: "05:00"
+`
+		require.NoError(t, ioutil.WriteFile(capsFile, []byte(caps), 0600))
+
+		// the window has no timezone, it is evaluated in the offset of the time
+		at, err := time.Parse(time.RFC3339, "2022-03-04T03:00:00+09:00")
+		require.NoError(t, err)
+		env := CheckEnv{Host: map[string]interface{}{"platform": "linux"}, Time: at}
+
+		_, decisions, err := Check(capsFile, l, &fleetapi.ActionUpgrade{Version: "8.1.0"}, env)
+		require.NoError(t, err)
+		assert.Equal(t, []Decision{
+			{Kind: UpgradeKind, Target: "8.1.0", Rule: "UW allow(02:00-05:00)", Allowed: true, Depends: []string{DependsOnTime}},
+		}, decisions)
+
+		env.Host["platform"] = "windows"
+		env.Time = at.Add(3 * time.Hour)
+		_, decisions, err = Check(capsFile, l, &fleetapi.ActionUpgrade{Version: "8.1.0"}, env)
+		assert.ErrorIs(t, err, ErrBlocked)
+		assert.Equal(t, []Decision{
+			{Kind: UpgradeKind, Target: "8.1.0", Rule: "UD(${version} == '8.1.0')", Allowed: false, Depends: []string{DependsOnHost}},
+		}, decisions)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		capsFile := filepath.Join(t.TempDir(), "capabilities.yml")
+		require.NoError(t, ioutil.WriteFile(capsFile, []byte("capabilities: [{rule: allow, upgrade: 1.0"), 0600))
+
+		_, _, err := Check(capsFile, l, &fleetapi.ActionUpgrade{Version: "8.0.0"}, CheckEnv{})
+		assert.Error(t, err)
+	})
+}
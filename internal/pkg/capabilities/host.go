@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"fmt"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+	"github.com/elastic/elastic-agent/internal/pkg/composable/providers/host"
+	"github.com/elastic/elastic-agent/internal/pkg/eql"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const hostKey = "host"
+
+// hostInfo returns the values of the host provider, used by testing.
+var hostInfo = host.Info
+
+// hostInfoFunc returns the values the host conditions are evaluated against.
+type hostInfoFunc func() (map[string]interface{}, error)
+
+// hostCondition restricts a capability to the hosts matching an expression, the expression uses the
+// values of the `host` provider, e.g. `${host.platform} == 'windows'`.
+type hostCondition struct {
+	HostEqlDefinition string `json:"host,omitempty" yaml:"host,omitempty"`
+}
+
+func (c *hostCondition) hostExpression() string {
+	return c.HostEqlDefinition
+}
+
+// dependsOn returns the host dependency of the decisions taken by a capability with a host condition.
+func (c *hostCondition) dependsOn() []string {
+	if c.HostEqlDefinition == "" {
+		return nil
+	}
+	return []string{DependsOnHost}
+}
+
+type hostGated interface {
+	hostExpression() string
+}
+
+// filterByHost removes the capabilities whose host condition doesn't match the host, the host
+// information is only fetched when a capability defines a condition.
+func filterByHost(log *logger.Logger, rd *ruleDefinitions, info hostInfoFunc) error {
+	var vars eql.VarStore
+	filtered := make(capabilitiesList, 0, len(rd.Capabilities))
+
+	for _, r := range rd.Capabilities {
+		gated, ok := r.(hostGated)
+		if !ok || gated.hostExpression() == "" {
+			filtered = append(filtered, r)
+			continue
+		}
+
+		if vars == nil {
+			values, err := info()
+			if err != nil {
+				return fmt.Errorf("failed to fetch host information: %w", err)
+			}
+			ast, err := transpiler.NewAST(map[string]interface{}{hostKey: values})
+			if err != nil {
+				return fmt.Errorf("failed to fetch host information: %w", err)
+			}
+			vars = ast
+		}
+
+		matches, err := eql.Eval(gated.hostExpression(), vars)
+		if err != nil {
+			return fmt.Errorf("invalid host condition '%s': %w", gated.hostExpression(), err)
+		}
+		if !matches {
+			log.Debugf("capability skipped, host does not match condition '%s'", gated.hostExpression())
+			continue
+		}
+
+		filtered = append(filtered, r)
+	}
+
+	rd.Capabilities = filtered
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestFilterByHost(t *testing.T) {
+	l, _ := logger.New("test", false)
+	defer func(f func() (map[string]interface{}, error)) { hostInfo = f }(hostInfo)
+
+	var fetched int
+	hostInfo = func() (map[string]interface{}, error) {
+		fetched++
+		return map[string]interface{}{
+			"name":         "db-01",
+			"platform":     "linux",
+			"architecture": "x86_64",
+		}, nil
+	}
+
+	t.Run("keeps matching capabilities", func(t *testing.T) {
+		fetched = 0
+		linux := &inputCapability{Type: denyKey, Input: "*", hostCondition: hostCondition{HostEqlDefinition: "${host.platform} == 'linux'"}}
+		windows := &outputCapability{Type: denyKey, Output: "*", hostCondition: hostCondition{HostEqlDefinition: "${host.platform} == 'windows'"}}
+		ungated := &upgradeCapability{Type: allowKey}
+		database := &maxInputsCapability{Max: 2, hostCondition: hostCondition{HostEqlDefinition: "startsWith(${host.name}, 'db-')"}}
+
+		rd := &ruleDefinitions{Capabilities: capabilitiesList{linux, windows, ungated, database}}
+		require.NoError(t, filterByHost(l, rd, hostInfo))
+		assert.Equal(t, capabilitiesList{linux, ungated, database}, rd.Capabilities)
+		assert.Equal(t, 1, fetched)
+	})
+
+	t.Run("no host information fetched without conditions", func(t *testing.T) {
+		fetched = 0
+		rd := &ruleDefinitions{Capabilities: capabilitiesList{&inputCapability{Type: denyKey, Input: "*"}}}
+		require.NoError(t, filterByHost(l, rd, hostInfo))
+		assert.Len(t, rd.Capabilities, 1)
+		assert.Equal(t, 0, fetched)
+	})
+
+	t.Run("invalid condition", func(t *testing.T) {
+		rd := &ruleDefinitions{Capabilities: capabilitiesList{
+			&inputCapability{Type: denyKey, Input: "*", hostCondition: hostCondition{HostEqlDefinition: "${host.name} + 1"}},
+		}}
+		assert.Error(t, filterByHost(l, rd, hostInfo))
+	})
+
+	t.Run("host information unavailable", func(t *testing.T) {
+		hostInfo = func() (map[string]interface{}, error) {
+			return nil, errors.New("no host")
+		}
+		rd := &ruleDefinitions{Capabilities: capabilitiesList{
+			&inputCapability{Type: denyKey, Input: "*", hostCondition: hostCondition{HostEqlDefinition: "${host.platform} == 'linux'"}},
+		}}
+		assert.Error(t, filterByHost(l, rd, hostInfo))
+	})
+}
+
+func TestHostConditionUnmarshal(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		rd := &ruleDefinitions{}
+		require.NoError(t, yaml.Unmarshal([]byte(`
+capabilities:
+- rule: deny
+  input: system/*
+  host: ${host.platform} == 'windows'
+`), rd))
+		require.Len(t, rd.Capabilities, 1)
+		assert.Equal(t, "${host.platform} == 'windows'", rd.Capabilities[0].(hostGated).hostExpression())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		rd := &ruleDefinitions{}
+		require.NoError(t, json.Unmarshal([]byte(`{"capabilities": [{"rule": "deny", "output": "*", "host": "${host.platform} == 'windows'"}]}`), rd))
+		require.Len(t, rd.Capabilities, 1)
+		assert.Equal(t, "${host.platform} == 'windows'", rd.Capabilities[0].(hostGated).hostExpression())
+	})
+}
@@ -57,6 +57,8 @@ type inputCapability struct {
 	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
 	Type     string `json:"rule" yaml:"rule"`
 	Input    string `json:"input" yaml:"input"`
+
+	hostCondition `yaml:",inline"`
 }
 
 func (c *inputCapability) Apply(cfgMap map[string]interface{}) (map[string]interface{}, error) {
@@ -161,6 +163,7 @@ func (c *inputCapability) renderInputs(inputs []map[string]interface{}) ([]map[s
 		}
 
 		isSupported := c.Type == allowKey
+		recordDecision(c.reporter, Decision{Kind: InputKind, Target: inputType, Rule: c.name(), Allowed: isSupported, Depends: c.dependsOn()})
 
 		input[conditionKey] = isSupported
 		if !isSupported {
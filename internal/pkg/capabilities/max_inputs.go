@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"fmt"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const (
+	maxInputsKey = "max_inputs"
+	enabledKey   = "enabled"
+)
+
+// newMaxInputsCapability creates capability filter limiting the number of inputs run, inputs over
+// the limit are removed in the order of the configuration. Disabled inputs are not counted.
+func newMaxInputsCapability(log *logger.Logger, rd *ruleDefinitions, reporter status.Reporter) (Capability, error) {
+	if rd == nil {
+		return &multiMaxInputsCapability{log: log, caps: []*maxInputsCapability{}}, nil
+	}
+
+	caps := make([]*maxInputsCapability, 0, len(rd.Capabilities))
+
+	for _, r := range rd.Capabilities {
+		cap, ok := r.(*maxInputsCapability)
+		if !ok {
+			continue
+		}
+
+		if cap.Max < 0 {
+			return nil, fmt.Errorf("'%d' is not a valid number of inputs", cap.Max)
+		}
+
+		cap.log = log
+		cap.reporter = reporter
+		caps = append(caps, cap)
+	}
+
+	return &multiMaxInputsCapability{log: log, caps: caps}, nil
+}
+
+type maxInputsCapability struct {
+	log      *logger.Logger
+	reporter status.Reporter
+	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
+	Max      int    `json:"max_inputs" yaml:"max_inputs"`
+
+	hostCondition `yaml:",inline"`
+}
+
+func (c *maxInputsCapability) Rule() string {
+	return denyKey
+}
+
+func (c *maxInputsCapability) name() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	// e.g max_inputs(10)
+	c.Name = fmt.Sprintf("%s(%d)", maxInputsKey, c.Max)
+	return c.Name
+}
+
+// Apply removes the enabled inputs over the limit.
+func (c *maxInputsCapability) Apply(cfgMap map[string]interface{}) (map[string]interface{}, error) {
+	inputsIface, ok := cfgMap[inputsKey]
+	if !ok {
+		return cfgMap, nil
+	}
+
+	inputs := inputsMap(inputsIface, c.log)
+	if inputs == nil {
+		return cfgMap, nil
+	}
+
+	newInputs := make([]map[string]interface{}, 0, len(inputs))
+	var running, blocked int
+	for _, input := range inputs {
+		if enabled, ok := input[enabledKey].(bool); ok && !enabled {
+			newInputs = append(newInputs, input)
+			continue
+		}
+
+		if running >= c.Max {
+			inputType, _ := input[typeKey].(string)
+			recordDecision(c.reporter, Decision{Kind: InputKind, Target: inputType, Rule: c.name(), Allowed: false, Depends: c.dependsOn()})
+			blocked++
+			continue
+		}
+
+		running++
+		newInputs = append(newInputs, input)
+	}
+
+	if blocked > 0 {
+		msg := fmt.Sprintf("%d inputs are not run due to capability restriction '%s'", blocked, c.name())
+		c.log.Infof(msg)
+		c.reporter.Update(state.Degraded, msg, nil)
+	}
+
+	cfgMap[inputsKey] = newInputs
+	return cfgMap, nil
+}
+
+type multiMaxInputsCapability struct {
+	caps []*maxInputsCapability
+	log  *logger.Logger
+}
+
+func (c *multiMaxInputsCapability) Apply(in interface{}) (interface{}, error) {
+	cfgMap, transform, err := configObject(in)
+	if err != nil {
+		c.log.Errorf("constructing config object failed for 'max-inputs' capability: %v", err)
+		return in, nil
+	}
+	if cfgMap == nil || len(c.caps) == 0 {
+		return in, nil
+	}
+
+	for _, cap := range c.caps {
+		// max inputs capability is not blocking
+		cfgMap, err = cap.Apply(cfgMap)
+		if err != nil {
+			return in, err
+		}
+	}
+
+	return transform(cfgMap), nil
+}
@@ -0,0 +1,90 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestMaxInputs(t *testing.T) {
+	l, _ := logger.New("test", false)
+
+	t.Run("under the limit", func(t *testing.T) {
+		tr := &recordingReporter{}
+		cap, err := newMaxInputsCapability(l, &ruleDefinitions{Capabilities: capabilitiesList{&maxInputsCapability{Max: 3}}}, tr)
+		require.NoError(t, err)
+
+		out, err := cap.Apply(getInputsMap("system/metrics", "system/logs"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"system/metrics", "system/logs"}, inputTypes(t, out))
+		assert.Empty(t, tr.updates)
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		tr := &recordingReporter{}
+		cap, err := newMaxInputsCapability(l, &ruleDefinitions{Capabilities: capabilitiesList{&maxInputsCapability{Max: 1}}}, tr)
+		require.NoError(t, err)
+
+		out, err := cap.Apply(getInputsMap("system/metrics", "system/logs", "log"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"system/metrics"}, inputTypes(t, out))
+		require.Len(t, tr.updates, 1)
+		assert.Equal(t, state.Degraded, tr.updates[0].status)
+		assert.Equal(t, "2 inputs are not run due to capability restriction 'max_inputs(1)'", tr.updates[0].message)
+	})
+
+	t.Run("disabled inputs are not counted", func(t *testing.T) {
+		in := getInputsMap("system/metrics", "system/logs", "log")
+		in[inputsKey].([]map[string]interface{})[0][enabledKey] = false
+
+		cap, err := newMaxInputsCapability(l, &ruleDefinitions{Capabilities: capabilitiesList{&maxInputsCapability{Max: 1}}}, &testReporter{})
+		require.NoError(t, err)
+
+		out, err := cap.Apply(in)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"system/metrics", "system/logs"}, inputTypes(t, out))
+	})
+
+	t.Run("lowest limit wins", func(t *testing.T) {
+		rd := &ruleDefinitions{Capabilities: capabilitiesList{&maxInputsCapability{Max: 2}, &maxInputsCapability{Max: 1}}}
+		cap, err := newMaxInputsCapability(l, rd, &testReporter{})
+		require.NoError(t, err)
+
+		out, err := cap.Apply(getInputs("system/metrics", "system/logs", "log"))
+		require.NoError(t, err)
+		ast, ok := out.(*transpiler.AST)
+		require.True(t, ok, "AST is expected")
+		m, err := ast.Map()
+		require.NoError(t, err)
+		assert.Len(t, m[inputsKey], 1)
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		_, err := newMaxInputsCapability(l, &ruleDefinitions{Capabilities: capabilitiesList{&maxInputsCapability{Max: -1}}}, &testReporter{})
+		assert.Error(t, err)
+	})
+}
+
+func inputTypes(t *testing.T, out interface{}) []string {
+	t.Helper()
+	cfg, ok := out.(map[string]interface{})
+	require.True(t, ok, "map is expected")
+
+	inputs, ok := cfg[inputsKey].([]map[string]interface{})
+	require.True(t, ok, "inputs list is expected")
+
+	types := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		types = append(types, input[typeKey].(string))
+	}
+	return types
+}
@@ -57,6 +57,8 @@ type outputCapability struct {
 	Name     string `json:"name,omitempty" yaml:"name,omitempty"`
 	Type     string `json:"rule" yaml:"rule"`
 	Output   string `json:"output" yaml:"output"`
+
+	hostCondition `yaml:",inline"`
 }
 
 func (c *outputCapability) Apply(cfgMap map[string]interface{}) (map[string]interface{}, error) {
@@ -127,6 +129,7 @@ func (c *outputCapability) renderOutputs(outputs map[string]interface{}) (map[st
 		}
 
 		isSupported := c.Type == allowKey
+		recordDecision(c.reporter, Decision{Kind: OutputKind, Target: outputName, Rule: c.name(), Allowed: isSupported, Depends: c.dependsOn()})
 		output[conditionKey] = isSupported
 		outputs[outputName] = output
 
@@ -82,6 +82,8 @@ type upgradeCapability struct {
 	// UpgradeEql is eql expression defining upgrade
 	UpgradeEqlDefinition string `json:"upgrade" yaml:"upgrade"`
 
+	hostCondition `yaml:",inline"`
+
 	upgradeEql *eql.Expression
 }
 
@@ -132,6 +134,9 @@ func (c *upgradeCapability) Apply(upgradeMap map[string]interface{}) (map[string
 		c.reporter.Update(state.Degraded, msg, nil)
 	}
 
+	version, _ := upgradeMap[versionKey].(string)
+	recordDecision(c.reporter, Decision{Kind: UpgradeKind, Target: version, Rule: c.name(), Allowed: isSupported, Depends: c.dependsOn()})
+
 	if !isSupported {
 		return upgradeMap, ErrBlocked
 	}
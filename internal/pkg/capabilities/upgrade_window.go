@@ -0,0 +1,187 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const (
+	upgradeWindowKey = "upgrade_window"
+	clockLayout      = "15:04"
+)
+
+// timeNow returns the current time, used by testing.
+var timeNow = time.Now
+
+// newUpgradeWindowsCapability creates capability filter restricting upgrades to a time of the day.
+// An allow rule only accepts upgrades inside the window, a deny rule blocks upgrades inside the window.
+func newUpgradeWindowsCapability(log *logger.Logger, rd *ruleDefinitions, reporter status.Reporter) (Capability, error) {
+	if rd == nil {
+		return &multiUpgradeWindowCapability{caps: []*upgradeWindowCapability{}}, nil
+	}
+
+	caps := make([]*upgradeWindowCapability, 0, len(rd.Capabilities))
+
+	for _, r := range rd.Capabilities {
+		c, err := newUpgradeWindowCapability(log, r, reporter)
+		if err != nil {
+			return nil, err
+		}
+
+		if c != nil {
+			caps = append(caps, c)
+		}
+	}
+
+	return &multiUpgradeWindowCapability{caps: caps}, nil
+}
+
+func newUpgradeWindowCapability(log *logger.Logger, r ruler, reporter status.Reporter) (*upgradeWindowCapability, error) {
+	cap, ok := r.(*upgradeWindowCapability)
+	if !ok {
+		return nil, nil
+	}
+
+	cap.Type = strings.ToLower(cap.Type)
+	if cap.Type != allowKey && cap.Type != denyKey {
+		return nil, fmt.Errorf("'%s' is not a valid type 'allow' and 'deny' are supported", cap.Type)
+	}
+
+	start, err := time.Parse(clockLayout, cap.Window.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upgrade window start '%s', expected HH:MM", cap.Window.Start)
+	}
+	end, err := time.Parse(clockLayout, cap.Window.End)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upgrade window end '%s', expected HH:MM", cap.Window.End)
+	}
+
+	cap.location = time.Local
+	if cap.Window.Timezone != "" {
+		cap.location, err = time.LoadLocation(cap.Window.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upgrade window timezone '%s': %w", cap.Window.Timezone, err)
+		}
+	}
+
+	cap.start = start.Hour()*60 + start.Minute()
+	cap.end = end.Hour()*60 + end.Minute()
+	cap.log = log
+	cap.reporter = reporter
+	return cap, nil
+}
+
+type upgradeWindow struct {
+	// Start and End are times of the day formatted as HH:MM, the window spans over midnight when
+	// End is before Start.
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+	// Timezone is the IANA name of the timezone of the window, the local timezone is used by default.
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+type upgradeWindowCapability struct {
+	log      *logger.Logger
+	reporter status.Reporter
+	Name     string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Type     string        `json:"rule" yaml:"rule"`
+	Window   upgradeWindow `json:"upgrade_window" yaml:"upgrade_window"`
+
+	hostCondition `yaml:",inline"`
+
+	// at replaces the current time when set, used by the offline check.
+	at time.Time
+
+	location *time.Location
+	// start and end are minutes since midnight
+	start int
+	end   int
+}
+
+func (c *upgradeWindowCapability) Rule() string {
+	return c.Type
+}
+
+func (c *upgradeWindowCapability) name() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	// e.g UW allow(02:00-05:00)
+	c.Name = fmt.Sprintf("UW %s(%s-%s)", c.Type, c.Window.Start, c.Window.End)
+	return c.Name
+}
+
+func (c *upgradeWindowCapability) inWindow(t time.Time) bool {
+	t = t.In(c.location)
+	minutes := t.Hour()*60 + t.Minute()
+	if c.start <= c.end {
+		return minutes >= c.start && minutes < c.end
+	}
+	return minutes >= c.start || minutes < c.end
+}
+
+// Apply blocks the upgrade when it happens at a time not accepted by the rule.
+func (c *upgradeWindowCapability) Apply(upgradeMap map[string]interface{}) (map[string]interface{}, error) {
+	now := c.at
+	if now.IsZero() {
+		now = timeNow()
+	}
+
+	isSupported := c.inWindow(now)
+	if c.Type == denyKey {
+		isSupported = !isSupported
+	}
+
+	version, _ := upgradeMap[versionKey].(string)
+	recordDecision(c.reporter, Decision{Kind: UpgradeKind, Target: version, Rule: c.name(), Allowed: isSupported, Depends: append(c.dependsOn(), DependsOnTime)})
+
+	if !isSupported {
+		msg := fmt.Sprintf("upgrade is blocked out due to capability restriction '%s'", c.name())
+		c.log.Errorf(msg)
+		c.reporter.Update(state.Degraded, msg, nil)
+		return upgradeMap, ErrBlocked
+	}
+
+	return upgradeMap, nil
+}
+
+type multiUpgradeWindowCapability struct {
+	caps []*upgradeWindowCapability
+}
+
+// setTime evaluates the windows at a given time instead of the current time, the windows without a
+// timezone use the location of the time instead of the local timezone.
+func (c *multiUpgradeWindowCapability) setTime(t time.Time) {
+	for _, cap := range c.caps {
+		cap.at = t
+		if cap.Window.Timezone == "" {
+			cap.location = t.Location()
+		}
+	}
+}
+
+func (c *multiUpgradeWindowCapability) Apply(in interface{}) (interface{}, error) {
+	upgradeMap := upgradeObject(in)
+	if upgradeMap == nil {
+		// not an upgrade we don't alter origin
+		return in, nil
+	}
+
+	for _, cap := range c.caps {
+		if _, err := cap.Apply(upgradeMap); err != nil {
+			return in, err
+		}
+	}
+
+	return in, nil
+}
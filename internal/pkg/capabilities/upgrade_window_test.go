@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package capabilities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestUpgradeWindow(t *testing.T) {
+	l, _ := logger.New("test", false)
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+
+	at := func(clock string) {
+		now, err := time.Parse(time.RFC3339, "2022-03-04T"+clock+":00Z")
+		require.NoError(t, err)
+		timeNow = func() time.Time { return now }
+	}
+
+	testcases := []struct {
+		name    string
+		rule    string
+		window  upgradeWindow
+		now     string
+		blocked bool
+	}{
+		{name: "allow inside", rule: allowKey, window: upgradeWindow{Start: "02:00", End: "05:00", Timezone: "UTC"}, now: "03:30"},
+		{name: "allow at start", rule: allowKey, window: upgradeWindow{Start: "02:00", End: "05:00", Timezone: "UTC"}, now: "02:00"},
+		{name: "allow at end", rule: allowKey, window: upgradeWindow{Start: "02:00", End: "05:00", Timezone: "UTC"}, now: "05:00", blocked: true},
+		{name: "allow outside", rule: allowKey, window: upgradeWindow{Start: "02:00", End: "05:00", Timezone: "UTC"}, now: "12:00", blocked: true},
+		{name: "allow over midnight", rule: allowKey, window: upgradeWindow{Start: "22:00", End: "03:00", Timezone: "UTC"}, now: "01:00"},
+		{name: "allow in timezone", rule: allowKey, window: upgradeWindow{Start: "02:00", End: "05:00", Timezone: "Asia/Tokyo"}, now: "18:00"},
+		{name: "deny inside", rule: denyKey, window: upgradeWindow{Start: "09:00", End: "17:00", Timezone: "UTC"}, now: "10:00", blocked: true},
+		{name: "deny outside", rule: denyKey, window: upgradeWindow{Start: "09:00", End: "17:00", Timezone: "UTC"}, now: "20:00"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := &recordingReporter{}
+			rd := &ruleDefinitions{Capabilities: capabilitiesList{
+				&upgradeWindowCapability{Type: tc.rule, Window: tc.window},
+			}}
+			cap, err := newUpgradeWindowsCapability(l, rd, tr)
+			require.NoError(t, err)
+
+			at(tc.now)
+			ta := &testUpgradeAction{version: "8.1.0"}
+			out, err := cap.Apply(ta)
+			assert.Equal(t, ta, out)
+			if !tc.blocked {
+				assert.NoError(t, err)
+				assert.Empty(t, tr.updates)
+				return
+			}
+			assert.Equal(t, ErrBlocked, err)
+			require.Len(t, tr.updates, 1)
+			assert.Equal(t, state.Degraded, tr.updates[0].status)
+		})
+	}
+
+	t.Run("not an upgrade", func(t *testing.T) {
+		at("12:00")
+		rd := &ruleDefinitions{Capabilities: capabilitiesList{
+			&upgradeWindowCapability{Type: allowKey, Window: upgradeWindow{Start: "02:00", End: "05:00"}},
+		}}
+		cap, err := newUpgradeWindowsCapability(l, rd, &testReporter{})
+		require.NoError(t, err)
+
+		in := getInputsMap("system/metrics")
+		out, err := cap.Apply(in)
+		assert.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	t.Run("invalid definitions", func(t *testing.T) {
+		for _, c := range []*upgradeWindowCapability{
+			{Type: "maybe", Window: upgradeWindow{Start: "02:00", End: "05:00"}},
+			{Type: allowKey, Window: upgradeWindow{Start: "2am", End: "05:00"}},
+			{Type: allowKey, Window: upgradeWindow{Start: "02:00", End: "25:00"}},
+			{Type: allowKey, Window: upgradeWindow{Start: "02:00", End: "05:00", Timezone: "Mars/Olympus"}},
+		} {
+			_, err := newUpgradeWindowsCapability(l, &ruleDefinitions{Capabilities: capabilitiesList{c}}, &testReporter{})
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		rd := &ruleDefinitions{}
+		require.NoError(t, yaml.Unmarshal([]byte(`
+capabilities:
+- rule: allow
+  upgrade_window:
+    start: "02:00"
+    end: "05:00"
+    timezone: Europe/Paris
+`), rd))
+		require.Len(t, rd.Capabilities, 1)
+		c, ok := rd.Capabilities[0].(*upgradeWindowCapability)
+		require.True(t, ok)
+		assert.Equal(t, upgradeWindow{Start: "02:00", End: "05:00", Timezone: "Europe/Paris"}, c.Window)
+	})
+}
+
+type reporterUpdate struct {
+	status  state.Status
+	message string
+}
+
+type recordingReporter struct {
+	updates []reporterUpdate
+}
+
+func (r *recordingReporter) Update(s state.Status, message string, _ map[string]interface{}) {
+	r.updates = append(r.updates, reporterUpdate{status: s, message: message})
+}
+
+func (*recordingReporter) Unregister() {}
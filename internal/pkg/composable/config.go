@@ -4,9 +4,45 @@
 
 package composable
 
-import "github.com/elastic/elastic-agent/internal/pkg/config"
+import (
+	"fmt"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+)
 
 // Config is config for multiple providers.
 type Config struct {
 	Providers map[string]*config.Config `config:"providers"`
+	Agent     agentConfig               `config:"agent"`
+}
+
+type agentConfig struct {
+	Composable ControllerConfig `config:"composable"`
+}
+
+// ControllerConfig is the configuration of how the controller notifies changes of the providers,
+// configured under `agent.composable`.
+type ControllerConfig struct {
+	// Debounce is how long the controller waits for other changes after a change, all the changes
+	// received until no change happened for Debounce result in a single callback.
+	Debounce time.Duration `config:"debounce" validate:"positive"`
+	// MaxWait is the longest a change is delayed when the providers keep changing.
+	MaxWait time.Duration `config:"max_wait" validate:"positive"`
+}
+
+// DefaultControllerConfig returns the default controller configuration.
+func DefaultControllerConfig() ControllerConfig {
+	return ControllerConfig{
+		Debounce: 100 * time.Millisecond,
+		MaxWait:  time.Second,
+	}
+}
+
+// Validate validates the controller configuration.
+func (c *ControllerConfig) Validate() error {
+	if c.MaxWait < c.Debounce {
+		return fmt.Errorf("max_wait (%s) must be greater than or equal to debounce (%s)", c.MaxWait, c.Debounce)
+	}
+	return nil
 }
@@ -19,7 +19,9 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
 	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/internal/pkg/core/backoff"
 	corecomp "github.com/elastic/elastic-agent/internal/pkg/core/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
 	"github.com/elastic/elastic-agent/pkg/core/logger"
 )
 
@@ -32,19 +34,28 @@ type Controller interface {
 	//
 	// Cancelling the context stops the controller.
 	Run(ctx context.Context, cb VarsCallback) error
+
+	// Snapshot returns the current output of the providers.
+	Snapshot() Snapshot
 }
 
 // controller manages the state of the providers current context.
 type controller struct {
+	logger           *logger.Logger
+	statusCtrl       status.Controller
+	config           ControllerConfig
+	metrics          *controllerMetrics
 	contextProviders map[string]*contextProviderState
 	dynamicProviders map[string]*dynamicProviderState
 }
 
-// New creates a new controller.
-func New(log *logger.Logger, c *config.Config) (Controller, error) {
+// New creates a new controller, the health of every provider is reported to the status controller.
+func New(log *logger.Logger, c *config.Config, statusCtrl status.Controller) (Controller, error) {
 	l := log.Named("composable")
 
-	var providersCfg Config
+	providersCfg := Config{
+		Agent: agentConfig{Composable: DefaultControllerConfig()},
+	}
 	if c != nil {
 		err := c.Unpack(&providersCfg)
 		if err != nil {
@@ -65,6 +76,7 @@ func New(log *logger.Logger, c *config.Config) (Controller, error) {
 			return nil, errors.New(err, fmt.Sprintf("failed to build provider '%s'", name), errors.TypeConfig, errors.M("provider", name))
 		}
 		contextProviders[name] = &contextProviderState{
+			name:     name,
 			provider: provider,
 		}
 	}
@@ -82,12 +94,25 @@ func New(log *logger.Logger, c *config.Config) (Controller, error) {
 			return nil, errors.New(err, fmt.Sprintf("failed to build provider '%s'", name), errors.TypeConfig, errors.M("provider", name))
 		}
 		dynamicProviders[name] = &dynamicProviderState{
+			name:     name,
 			provider: provider,
 			mappings: map[string]dynamicProviderMapping{},
 		}
 	}
 
+	names := make([]string, 0, len(contextProviders)+len(dynamicProviders))
+	for name := range contextProviders {
+		names = append(names, name)
+	}
+	for name := range dynamicProviders {
+		names = append(names, name)
+	}
+
 	return &controller{
+		logger:           l,
+		statusCtrl:       statusCtrl,
+		config:           providersCfg.Agent.Composable,
+		metrics:          newControllerMetrics(names),
 		contextProviders: contextProviders,
 		dynamicProviders: dynamicProviders,
 	}, nil
@@ -96,97 +121,141 @@ func New(log *logger.Logger, c *config.Config) (Controller, error) {
 // Run runs the controller.
 func (c *controller) Run(ctx context.Context, cb VarsCallback) error {
 	// large number not to block performing Run on the provided providers
-	notify := make(chan bool, 5000)
+	notify := make(chan string, 5000)
 	localCtx, cancel := context.WithCancel(ctx)
 
 	fetchContextProviders := common.MapStr{}
 
 	// run all the enabled context providers
 	for name, state := range c.contextProviders {
+		state := state
 		state.Context = localCtx
 		state.signal = notify
-		err := state.provider.Run(state)
-		if err != nil {
-			cancel()
-			return errors.New(err, fmt.Sprintf("failed to run provider '%s'", name), errors.TypeConfig, errors.M("provider", name))
-		}
+		state.register(c.statusCtrl, name)
 		if p, ok := state.provider.(corecomp.FetchContextProvider); ok {
 			fetchContextProviders.Put(name, p)
 		}
+		c.runProvider(localCtx, name, &state.providerHealth, func() error {
+			return state.provider.Run(state)
+		})
 	}
 
 	// run all the enabled dynamic providers
 	for name, state := range c.dynamicProviders {
+		state := state
 		state.Context = localCtx
 		state.signal = notify
-		err := state.provider.Run(state)
-		if err != nil {
-			cancel()
-			return errors.New(err, fmt.Sprintf("failed to run provider '%s'", name), errors.TypeConfig, errors.M("provider", name))
-		}
+		state.register(c.statusCtrl, name)
+		c.runProvider(localCtx, name, &state.providerHealth, func() error {
+			return state.provider.Run(state)
+		})
 	}
 
 	go func() {
 		for {
-			// performs debounce of notifies; accumulates them into 100 millisecond chunks
-			changed := false
-			t := time.NewTimer(100 * time.Millisecond)
-			for {
-				exitloop := false
-				select {
-				case <-ctx.Done():
-					cancel()
-					return
-				case <-notify:
-					changed = true
-				case <-t.C:
-					exitloop = true
-				}
-				if exitloop {
-					break
-				}
+			changed, ok := c.waitChanges(ctx, notify)
+			if !ok {
+				cancel()
+				c.unregister()
+				return
 			}
+			start := time.Now()
 
-			t.Stop()
-			if !changed {
-				continue
-			}
-
-			// build the vars list of mappings
-			vars := make([]*transpiler.Vars, 1)
-			mapping := map[string]interface{}{}
-			for name, state := range c.contextProviders {
-				mapping[name] = state.Current()
-			}
-			// this is ensured not to error, by how the mappings states are verified
-			vars[0], _ = transpiler.NewVars(mapping, fetchContextProviders)
-
-			// add to the vars list for each dynamic providers mappings
-			for name, state := range c.dynamicProviders {
-				for _, mappings := range state.Mappings() {
-					local, _ := cloneMap(mapping) // will not fail; already been successfully cloned once
-					local[name] = mappings.mapping
-					// this is ensured not to error, by how the mappings states are verified
-					v, _ := transpiler.NewVarsWithProcessors(local, name, mappings.processors, fetchContextProviders)
-					vars = append(vars, v)
-				}
-			}
+			vars, _ := c.Snapshot().vars(fetchContextProviders)
 
 			// execute the callback
 			cb(vars)
+			c.metrics.called(changed, time.Since(start))
 		}
 	}()
 
 	return nil
 }
 
+// runProvider runs a provider, a provider failing to run is reported as degraded and run again with
+// a backoff until it succeeds or the context is cancelled.
+func (c *controller) runProvider(ctx context.Context, name string, health *providerHealth, run func() error) {
+	err := run()
+	if err == nil {
+		health.running()
+		return
+	}
+	c.logger.Errorf("Failed to run provider '%s', retrying: %s", name, err)
+	health.failed(err)
+
+	go func() {
+		b := backoff.NewExpBackoff(ctx.Done(), time.Second, time.Minute)
+		for b.Wait() {
+			health.restarting()
+			err := run()
+			if err == nil {
+				c.logger.Infof("Provider '%s' is running after restart", name)
+				health.running()
+				return
+			}
+			c.logger.Errorf("Failed to run provider '%s', retrying: %s", name, err)
+			health.failed(err)
+		}
+	}()
+}
+
+// unregister removes the providers from the status controller.
+func (c *controller) unregister() {
+	for _, state := range c.contextProviders {
+		state.unregister()
+	}
+	for _, state := range c.dynamicProviders {
+		state.unregister()
+	}
+}
+
+// waitChanges waits for a change and coalesces the changes following it, until no change happened
+// for the debounce period or the changes were delayed for max wait.
+//
+// Returns the names of the changed providers, false is returned when the context is cancelled.
+func (c *controller) waitChanges(ctx context.Context, notify <-chan string) (map[string]struct{}, bool) {
+	changed := map[string]struct{}{}
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case name := <-notify:
+		c.metrics.updated(name)
+		changed[name] = struct{}{}
+	}
+
+	maxWait := time.NewTimer(c.config.MaxWait)
+	defer maxWait.Stop()
+	debounce := time.NewTimer(c.config.Debounce)
+	// debounce is replaced on every change
+	defer func() {
+		debounce.Stop()
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case name := <-notify:
+			c.metrics.updated(name)
+			changed[name] = struct{}{}
+			debounce.Stop()
+			debounce = time.NewTimer(c.config.Debounce)
+		case <-debounce.C:
+			return changed, true
+		case <-maxWait.C:
+			return changed, true
+		}
+	}
+}
+
 type contextProviderState struct {
 	context.Context
+	providerHealth
 
+	name     string
 	provider corecomp.ContextProvider
 	lock     sync.RWMutex
 	mapping  map[string]interface{}
-	signal   chan bool
+	signal   chan string
 }
 
 // Set sets the current mapping.
@@ -210,7 +279,7 @@ func (c *contextProviderState) Set(mapping map[string]interface{}) error {
 		return nil
 	}
 	c.mapping = mapping
-	c.signal <- true
+	c.signal <- c.name
 	return nil
 }
 
@@ -222,6 +291,7 @@ func (c *contextProviderState) Current() map[string]interface{} {
 }
 
 type dynamicProviderMapping struct {
+	id         string
 	priority   int
 	mapping    map[string]interface{}
 	processors transpiler.Processors
@@ -229,11 +299,13 @@ type dynamicProviderMapping struct {
 
 type dynamicProviderState struct {
 	context.Context
+	providerHealth
 
+	name     string
 	provider DynamicProvider
 	lock     sync.RWMutex
 	mappings map[string]dynamicProviderMapping
-	signal   chan bool
+	signal   chan string
 }
 
 // AddOrUpdate adds or updates the current mapping for the dynamic provider.
@@ -265,11 +337,12 @@ func (c *dynamicProviderState) AddOrUpdate(id string, priority int, mapping map[
 		return nil
 	}
 	c.mappings[id] = dynamicProviderMapping{
+		id:         id,
 		priority:   priority,
 		mapping:    mapping,
 		processors: processors,
 	}
-	c.signal <- true
+	c.signal <- c.name
 	return nil
 }
 
@@ -281,7 +354,7 @@ func (c *dynamicProviderState) Remove(id string) {
 	if exists {
 		// existed; remove and signal
 		delete(c.mappings, id)
-		c.signal <- true
+		c.signal <- c.name
 	}
 }
 
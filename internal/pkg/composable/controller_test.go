@@ -6,8 +6,13 @@ package composable_test
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
 
 	"github.com/elastic/elastic-agent/pkg/core/logger"
 
@@ -19,6 +24,8 @@ import (
 
 	"github.com/elastic/elastic-agent/internal/pkg/composable"
 	"github.com/elastic/elastic-agent/internal/pkg/config"
+	corecomp "github.com/elastic/elastic-agent/internal/pkg/core/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
 
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/env"
 	_ "github.com/elastic/elastic-agent/internal/pkg/composable/providers/host"
@@ -77,7 +84,7 @@ func TestController(t *testing.T) {
 
 	log, err := logger.New("", false)
 	require.NoError(t, err)
-	c, err := composable.New(log, cfg)
+	c, err := composable.New(log, cfg, status.NewController(log))
 	require.NoError(t, err)
 
 	var wg sync.WaitGroup
@@ -110,3 +117,283 @@ func TestController(t *testing.T) {
 	localMap = local.(map[string]interface{})
 	assert.Equal(t, "value2", localMap["key1"])
 }
+
+func TestControllerSnapshot(t *testing.T) {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"providers": map[string]interface{}{
+			"env": map[string]interface{}{
+				"enabled": "false",
+			},
+			"local": map[string]interface{}{
+				"vars": map[string]interface{}{
+					"key1": "value1",
+				},
+			},
+			"local_dynamic": map[string]interface{}{
+				"items": []map[string]interface{}{
+					{
+						"vars": map[string]interface{}{
+							"key1": "value2",
+						},
+						"processors": []map[string]interface{}{
+							{
+								"add_fields": map[string]interface{}{
+									"fields": map[string]interface{}{
+										"add": "value2",
+									},
+								},
+							},
+						},
+					},
+					{
+						"vars": map[string]interface{}{
+							"key1": "value3",
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	log, err := logger.New("", false)
+	require.NoError(t, err)
+	c, err := composable.New(log, cfg, status.NewController(log))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	called := make(chan struct{}, 1)
+	err = c.Run(ctx, func(vars []*transpiler.Vars) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+	})
+	require.NoError(t, err)
+	<-called
+
+	snapshot := c.Snapshot()
+	assert.Equal(t, map[string]interface{}{"key1": "value1"}, snapshot.Context["local"])
+	assert.NotContains(t, snapshot.Context, "env")
+	assert.Equal(t, []composable.DynamicMapping{
+		{
+			ID:       "0",
+			Priority: 0,
+			Mapping:  map[string]interface{}{"key1": "value2"},
+			Processors: []map[string]interface{}{
+				{"add_fields": map[string]interface{}{"fields": map[string]interface{}{"add": "value2"}}},
+			},
+		},
+		{
+			ID:       "1",
+			Priority: 0,
+			Mapping:  map[string]interface{}{"key1": "value3"},
+		},
+	}, snapshot.Dynamic["local_dynamic"])
+
+	vars, sources := snapshot.Vars()
+	require.Len(t, vars, 3)
+	assert.Equal(t, []composable.VarsSource{
+		{},
+		{Provider: "local_dynamic", ID: "0"},
+		{Provider: "local_dynamic", ID: "1"},
+	}, sources)
+	value, ok := vars[2].Lookup("local_dynamic.key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value3", value)
+	_, ok = vars[0].Lookup("local_dynamic.key1")
+	assert.False(t, ok)
+}
+
+func TestControllerCoalescesChanges(t *testing.T) {
+	c, comm := newChangesController(t, "coalesce", 50*time.Millisecond, 5*time.Second)
+	updates := uintMetric("composable.providers.test_changes.updates")
+	callbacks := uintMetric("composable.providers.test_changes.callbacks")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	called := make(chan []*transpiler.Vars, 10)
+	err := c.Run(ctx, func(vars []*transpiler.Vars) {
+		called <- vars
+	})
+	require.NoError(t, err)
+	<-called
+
+	// changes in the debounce period result in a single callback with the last value
+	for i := 0; i < 5; i++ {
+		require.NoError(t, comm(t).Set(map[string]interface{}{"idx": i}))
+	}
+	vars := <-called
+	value, _ := vars[0].Lookup("test_changes.idx")
+	assert.Equal(t, float64(4), value)
+	select {
+	case <-called:
+		t.Fatal("changes should have been coalesced into a single callback")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	assert.Equal(t, updates+5, uintMetric("composable.providers.test_changes.updates"))
+	assert.Equal(t, callbacks+1, uintMetric("composable.providers.test_changes.callbacks"))
+}
+
+func TestControllerMaxWait(t *testing.T) {
+	c, comm := newChangesController(t, "max_wait", 200*time.Millisecond, 250*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	called := make(chan []*transpiler.Vars, 100)
+	err := c.Run(ctx, func(vars []*transpiler.Vars) {
+		called <- vars
+	})
+	require.NoError(t, err)
+	<-called
+
+	// changes keep coming faster than the debounce period, max wait still calls the callback
+	for i := 0; i < 50; i++ {
+		require.NoError(t, comm(t).Set(map[string]interface{}{"idx": i}))
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.NotEmpty(t, called)
+}
+
+func TestControllerProviderHealth(t *testing.T) {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"providers": map[string]interface{}{
+			"test_changes": map[string]interface{}{
+				"id":       "health",
+				"failures": 1,
+			},
+		},
+	})
+	require.NoError(t, err)
+	log, err := logger.New("", false)
+	require.NoError(t, err)
+	statusCtrl := status.NewController(log)
+	c, err := composable.New(log, cfg, statusCtrl)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = c.Run(ctx, func([]*transpiler.Vars) {})
+	require.NoError(t, err)
+
+	// failing provider is degraded until it runs after a restart
+	assert.Equal(t, status.Degraded, statusCtrl.StatusCode())
+	assert.Equal(t, "provider-test_changes: failed to run: connection refused", statusCtrl.Status().Message)
+	assert.Eventually(t, func() bool {
+		return statusCtrl.StatusCode() == status.Healthy
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// provider reports its data is stale
+	comm, ok := changesComms.Load("health")
+	require.True(t, ok)
+	composable.ReportDegraded(comm.(corecomp.ContextProviderComm), "failed to refresh")
+	assert.Equal(t, status.Degraded, statusCtrl.StatusCode())
+	assert.Equal(t, "provider-test_changes: failed to refresh", statusCtrl.Status().Message)
+	composable.ReportHealthy(comm.(corecomp.ContextProviderComm))
+	assert.Equal(t, status.Healthy, statusCtrl.StatusCode())
+
+	// provider monitoring a remote API is degraded while it is unreachable
+	var reachable int32
+	go composable.MonitorHealth(comm.(corecomp.ContextProviderComm), 10*time.Millisecond, func(context.Context) error {
+		if atomic.LoadInt32(&reachable) == 0 {
+			return errors.New("API unreachable")
+		}
+		return nil
+	})
+	assert.Eventually(t, func() bool {
+		return statusCtrl.Status().Message == "provider-test_changes: API unreachable"
+	}, 5*time.Second, 10*time.Millisecond)
+	atomic.StoreInt32(&reachable, 1)
+	assert.Eventually(t, func() bool {
+		return statusCtrl.StatusCode() == status.Healthy
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// providers are unregistered when the controller stops
+	composable.ReportDegraded(comm.(corecomp.ContextProviderComm), "failed to refresh")
+	cancel()
+	assert.Eventually(t, func() bool {
+		return statusCtrl.StatusCode() == status.Healthy
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestControllerInvalidConfig(t *testing.T) {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"agent.composable": map[string]interface{}{
+			"debounce": "1s",
+			"max_wait": "100ms",
+		},
+	})
+	require.NoError(t, err)
+
+	log, err := logger.New("", false)
+	require.NoError(t, err)
+	_, err = composable.New(log, cfg, status.NewController(log))
+	assert.Error(t, err)
+}
+
+// changesComms are the comms of the test_changes providers by the id in their configuration.
+var changesComms sync.Map
+
+type changesProvider struct {
+	ID       string `config:"id"`
+	Failures int    `config:"failures"`
+}
+
+func (p *changesProvider) Run(comm corecomp.ContextProviderComm) error {
+	if p.Failures > 0 {
+		p.Failures--
+		return errors.New("connection refused")
+	}
+	if p.ID != "" {
+		changesComms.Store(p.ID, comm)
+	}
+	return nil
+}
+
+func init() {
+	_ = composable.Providers.AddContextProvider("test_changes", func(_ *logger.Logger, c *config.Config) (corecomp.ContextProvider, error) {
+		p := &changesProvider{}
+		if c != nil {
+			if err := c.Unpack(p); err != nil {
+				return nil, err
+			}
+		}
+		return p, nil
+	})
+}
+
+func newChangesController(t *testing.T, id string, debounce, maxWait time.Duration) (composable.Controller, func(*testing.T) corecomp.ContextProviderComm) {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"agent.composable": map[string]interface{}{
+			"debounce": debounce,
+			"max_wait": maxWait,
+		},
+		"providers": map[string]interface{}{
+			"test_changes": map[string]interface{}{
+				"id": id,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	log, err := logger.New("", false)
+	require.NoError(t, err)
+	c, err := composable.New(log, cfg, status.NewController(log))
+	require.NoError(t, err)
+
+	return c, func(t *testing.T) corecomp.ContextProviderComm {
+		comm, ok := changesComms.Load(id)
+		require.True(t, ok)
+		return comm.(corecomp.ContextProviderComm)
+	}
+}
+
+func uintMetric(name string) uint64 {
+	v, ok := monitoring.Default.Get(name).(*monitoring.Uint)
+	if !ok {
+		return 0
+	}
+	return v.Get()
+}
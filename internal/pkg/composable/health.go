@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package composable
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+)
+
+// healthReporter is implemented by the comms the controller passes to the providers.
+type healthReporter interface {
+	reportDegraded(reason string)
+	reportHealthy()
+}
+
+// ReportDegraded reports the provider running with comm as degraded in the agent status, e.g. when
+// its watcher disconnected or its data cannot be refreshed and is stale.
+func ReportDegraded(comm context.Context, reason string) {
+	if h, ok := comm.(healthReporter); ok {
+		h.reportDegraded(reason)
+	}
+}
+
+// ReportHealthy reports the provider running with comm as healthy again.
+func ReportHealthy(comm context.Context) {
+	if h, ok := comm.(healthReporter); ok {
+		h.reportHealthy()
+	}
+}
+
+// MonitorHealth calls check every period until comm is done, the provider is reported as degraded
+// while check fails and as healthy once it succeeds again. Providers watching a remote API use it to
+// report the API unreachable while their watchers reconnect.
+func MonitorHealth(comm context.Context, period time.Duration, check func(ctx context.Context) error) {
+	t := time.NewTicker(period)
+	defer t.Stop()
+	healthy := true
+	for {
+		select {
+		case <-comm.Done():
+			return
+		case <-t.C:
+		}
+
+		ctx, cancel := context.WithTimeout(comm, period)
+		err := check(ctx)
+		cancel()
+		if comm.Err() != nil {
+			return
+		}
+		if err != nil {
+			healthy = false
+			ReportDegraded(comm, err.Error())
+		} else if !healthy {
+			healthy = true
+			ReportHealthy(comm)
+		}
+	}
+}
+
+// providerHealth reports the health of a provider as a component of the status controller.
+type providerHealth struct {
+	mx       sync.Mutex
+	reporter status.Reporter
+	// restarts is the number of times Run was called again after failing.
+	restarts int
+	// failure is set while Run keeps failing, it has precedence over the reports of the provider.
+	failure error
+}
+
+func (h *providerHealth) register(ctrl status.Controller, name string) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	h.reporter = ctrl.RegisterComponentWithPersistance("provider-"+name, true)
+	h.reporter.Update(state.Starting, "", nil)
+}
+
+func (h *providerHealth) unregister() {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	if h.reporter != nil {
+		h.reporter.Unregister()
+		h.reporter = nil
+	}
+}
+
+// failed reports a failure of Run.
+func (h *providerHealth) failed(err error) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	h.failure = err
+	h.update(state.Degraded, fmt.Sprintf("failed to run: %s", err))
+}
+
+// restarting counts a new call to Run after it failed.
+func (h *providerHealth) restarting() {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	h.restarts++
+}
+
+// running reports Run succeeded.
+func (h *providerHealth) running() {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	h.failure = nil
+	h.update(state.Healthy, "")
+}
+
+func (h *providerHealth) reportDegraded(reason string) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	if h.failure != nil {
+		return
+	}
+	h.update(state.Degraded, reason)
+}
+
+func (h *providerHealth) reportHealthy() {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	if h.failure != nil {
+		return
+	}
+	h.update(state.Healthy, "")
+}
+
+func (h *providerHealth) update(s state.Status, message string) {
+	if h.reporter == nil {
+		return
+	}
+	var payload map[string]interface{}
+	if h.restarts > 0 {
+		payload = map[string]interface{}{"restarts": h.restarts}
+	}
+	h.reporter.Update(s, message, payload)
+}
@@ -0,0 +1,100 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package composable
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/monitoring"
+)
+
+// metricsRegistry holds the controller counters, it is part of the stats served by the monitoring
+// HTTP server under `composable`.
+var (
+	metricsMx       sync.Mutex
+	metricsRegistry = monitoring.Default.NewRegistry("composable")
+)
+
+// controllerMetrics are the counters of the controller and of every provider. Counters are shared by
+// the controllers of the process, they keep counting when a controller is replaced.
+type controllerMetrics struct {
+	callbacks  *monitoring.Uint
+	renderTime *monitoring.Float
+
+	providers map[string]*providerMetrics
+}
+
+// providerMetrics are the counters of a provider.
+type providerMetrics struct {
+	// updates is the number of changes received from the provider.
+	updates *monitoring.Uint
+	// callbacks is the number of callbacks that included changes of the provider.
+	callbacks *monitoring.Uint
+	// renderTime is the time in milliseconds spent in those callbacks.
+	renderTime *monitoring.Float
+}
+
+func newControllerMetrics(names []string) *controllerMetrics {
+	metricsMx.Lock()
+	defer metricsMx.Unlock()
+
+	m := &controllerMetrics{
+		callbacks:  getOrNewUint(metricsRegistry, "callbacks"),
+		renderTime: getOrNewFloat(metricsRegistry, "render_time_ms"),
+		providers:  make(map[string]*providerMetrics, len(names)),
+	}
+	providers := getOrNewRegistry(metricsRegistry, "providers")
+	for _, name := range names {
+		reg := getOrNewRegistry(providers, name)
+		m.providers[name] = &providerMetrics{
+			updates:    getOrNewUint(reg, "updates"),
+			callbacks:  getOrNewUint(reg, "callbacks"),
+			renderTime: getOrNewFloat(reg, "render_time_ms"),
+		}
+	}
+	return m
+}
+
+// updated counts a change received from a provider.
+func (m *controllerMetrics) updated(name string) {
+	if p, ok := m.providers[name]; ok {
+		p.updates.Inc()
+	}
+}
+
+// called counts a callback with the changes of the providers and the time it took.
+func (m *controllerMetrics) called(changed map[string]struct{}, took time.Duration) {
+	ms := float64(took) / float64(time.Millisecond)
+	m.callbacks.Inc()
+	m.renderTime.Add(ms)
+	for name := range changed {
+		if p, ok := m.providers[name]; ok {
+			p.callbacks.Inc()
+			p.renderTime.Add(ms)
+		}
+	}
+}
+
+func getOrNewRegistry(parent *monitoring.Registry, name string) *monitoring.Registry {
+	if reg := parent.GetRegistry(name); reg != nil {
+		return reg
+	}
+	return parent.NewRegistry(name)
+}
+
+func getOrNewUint(reg *monitoring.Registry, name string) *monitoring.Uint {
+	if v, ok := reg.Get(name).(*monitoring.Uint); ok {
+		return v
+	}
+	return monitoring.NewUint(reg, name)
+}
+
+func getOrNewFloat(reg *monitoring.Registry, name string) *monitoring.Float {
+	if v, ok := reg.Get(name).(*monitoring.Float); ok {
+		return v
+	}
+	return monitoring.NewFloat(reg, name)
+}
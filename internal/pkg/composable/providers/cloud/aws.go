@@ -0,0 +1,57 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	awsName = "aws"
+
+	awsTokenPath    = "/latest/api/token"
+	awsDocumentPath = "/latest/dynamic/instance-identity/document"
+)
+
+type awsDocument struct {
+	AccountID        string `json:"accountId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	InstanceID       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+	Region           string `json:"region"`
+}
+
+// fetchAWS reads the instance identity document, IMDSv2 is used when the token can be retrieved and
+// IMDSv1 otherwise.
+func fetchAWS(ctx context.Context, client *http.Client, url string) (*instance, error) {
+	headers := map[string]string{}
+	token, err := request(ctx, client, http.MethodPut, url+awsTokenPath, map[string]string{
+		"X-aws-ec2-metadata-token-ttl-seconds": "60",
+	})
+	if err == nil {
+		headers["X-aws-ec2-metadata-token"] = string(token)
+	} else if ctx.Err() != nil {
+		return nil, err
+	}
+
+	var doc awsDocument
+	if err := requestJSON(ctx, client, url+awsDocumentPath, headers, &doc); err != nil {
+		return nil, err
+	}
+	if doc.InstanceID == "" {
+		return nil, errors.New("instance identity document has no instance id")
+	}
+
+	return &instance{
+		Provider:         awsName,
+		Region:           doc.Region,
+		AvailabilityZone: doc.AvailabilityZone,
+		InstanceID:       doc.InstanceID,
+		MachineType:      doc.InstanceType,
+		AccountID:        doc.AccountID,
+	}, nil
+}
@@ -0,0 +1,48 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	azureName = "azure"
+
+	azureMetadataPath = "/metadata/instance/compute?api-version=2021-02-01"
+)
+
+type azureCompute struct {
+	VMID           string `json:"vmId"`
+	Name           string `json:"name"`
+	Location       string `json:"location"`
+	Zone           string `json:"zone"`
+	VMSize         string `json:"vmSize"`
+	SubscriptionID string `json:"subscriptionId"`
+}
+
+// fetchAzure reads the compute metadata of the virtual machine.
+func fetchAzure(ctx context.Context, client *http.Client, url string) (*instance, error) {
+	var compute azureCompute
+	err := requestJSON(ctx, client, url+azureMetadataPath, map[string]string{"Metadata": "true"}, &compute)
+	if err != nil {
+		return nil, err
+	}
+	if compute.VMID == "" {
+		return nil, errors.New("compute metadata has no vm id")
+	}
+
+	return &instance{
+		Provider:         azureName,
+		Region:           compute.Location,
+		AvailabilityZone: compute.Zone,
+		InstanceID:       compute.VMID,
+		InstanceName:     compute.Name,
+		MachineType:      compute.VMSize,
+		AccountID:        compute.SubscriptionID,
+	}, nil
+}
@@ -0,0 +1,273 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/internal/pkg/core/backoff"
+	corecomp "github.com/elastic/elastic-agent/internal/pkg/core/composable"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// startupProbes is the number of probes before giving up on detecting a cloud, the metadata endpoint
+// can still be unavailable while the instance boots.
+const startupProbes = 5
+
+var (
+	// probeBackoffInit and probeBackoffMax are the delays between the probes at startup.
+	probeBackoffInit = time.Second
+	probeBackoffMax  = 30 * time.Second
+)
+
+func init() {
+	composable.Providers.AddContextProvider("cloud", ContextProviderBuilder)
+}
+
+// instance is the metadata of the cloud instance the agent runs on.
+type instance struct {
+	Provider         string
+	Region           string
+	AvailabilityZone string
+	InstanceID       string
+	InstanceName     string
+	MachineType      string
+	AccountID        string
+}
+
+// metadataFetcher reads the instance metadata from the endpoint at url.
+type metadataFetcher func(ctx context.Context, client *http.Client, url string) (*instance, error)
+
+var fetchers = map[string]metadataFetcher{
+	awsName:   fetchAWS,
+	gcpName:   fetchGCP,
+	azureName: fetchAzure,
+}
+
+type contextProvider struct {
+	logger *logger.Logger
+	config *Config
+	client *http.Client
+}
+
+// ContextProviderBuilder builds the context provider.
+func ContextProviderBuilder(log *logger.Logger, c *config.Config) (corecomp.ContextProvider, error) {
+	var cfg Config
+	if c == nil {
+		c = config.New()
+	}
+	err := c.Unpack(&cfg)
+	if err != nil {
+		return nil, errors.New(err, "failed to unpack configuration")
+	}
+	if len(cfg.Providers) == 0 {
+		// not a default of the config, lists are merged with their defaults by index
+		cfg.Providers = []string{awsName, gcpName, azureName}
+	}
+	// metadata endpoints are link-local, requests must not go through the proxy of the environment.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	return &contextProvider{
+		logger: log,
+		config: &cfg,
+		client: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+	}, nil
+}
+
+// Run runs the cloud context provider.
+//
+// Probing takes up to the timeout when the agent does not run on a cloud, it is done in the background
+// so it does not delay the other providers. The endpoints are probed again with a backoff while they
+// do not answer at startup, they are not probed anymore when no cloud is detected, an instance does not
+// move to a cloud.
+func (c *contextProvider) Run(comm corecomp.ContextProviderComm) error {
+	go func() {
+		var current map[string]interface{}
+		var detectedAt time.Time
+		retry := backoff.NewExpBackoff(comm.Done(), probeBackoffInit, probeBackoffMax)
+		for attempt := 1; ; attempt++ {
+			updated, definitive, err := c.probe(comm)
+			if comm.Err() != nil {
+				return
+			}
+			switch {
+			case err == nil:
+				detectedAt = time.Now()
+				composable.ReportHealthy(comm)
+			case current == nil && !definitive && attempt < startupProbes:
+				c.logger.Debugf("No cloud provider detected yet, probing again: %s", err)
+				if !retry.Wait() {
+					return
+				}
+				continue
+			case current == nil:
+				c.logger.Infof("No cloud provider detected, metadata endpoints are not probed anymore: %s", err)
+				if err := comm.Set(map[string]interface{}{}); err != nil {
+					c.logger.Errorf("Failed updating mapping to latest cloud metadata: %s", err)
+				}
+				return
+			case len(current) > 0 && time.Since(detectedAt) < 2*c.config.CacheTTL:
+				// a failed refresh keeps the metadata for another period of the cache
+				c.logger.Warnf("Failed to refresh cloud metadata, keeping the previous metadata: %s", err)
+				composable.ReportDegraded(comm, fmt.Sprintf("cloud metadata is stale: %s", err))
+				updated = current
+			default:
+				composable.ReportDegraded(comm, fmt.Sprintf("cloud metadata is unavailable: %s", err))
+			}
+			if current == nil || !reflect.DeepEqual(current, updated) {
+				current = updated
+				if err := comm.Set(updated); err != nil {
+					c.logger.Errorf("Failed updating mapping to latest cloud metadata: %s", err)
+				}
+			}
+
+			// metadata is cached, the endpoints are probed again once it expires.
+			t := time.NewTimer(c.config.CacheTTL)
+			select {
+			case <-comm.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// probe queries the metadata endpoints of all the clouds at once and returns the mapping of the first
+// cloud that answered, an error is returned when none of them answered. definitive is true when every
+// endpoint is known not to exist, a cloud is not detected by probing again.
+func (c *contextProvider) probe(ctx context.Context) (mapping map[string]interface{}, definitive bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	type result struct {
+		inst *instance
+		err  error
+	}
+	results := make([]chan result, len(c.config.Providers))
+	for i, name := range c.config.Providers {
+		results[i] = make(chan result, 1)
+		go func(name string, results chan<- result) {
+			inst, err := fetchers[name](ctx, c.client, strings.TrimSuffix(c.config.url(name), "/"))
+			if err != nil {
+				// not running on this cloud
+				c.logger.Debugf("Cloud provider %s not detected: %s", name, err)
+				err = fmt.Errorf("%s: %w", name, err)
+			}
+			results <- result{inst, err}
+		}(name, results[i])
+	}
+
+	var errs []string
+	definitive = true
+	for _, result := range results {
+		r := <-result
+		if r.inst != nil {
+			c.logger.Infof("Cloud provider %s detected in region %s", r.inst.Provider, r.inst.Region)
+			return generateMapping(r.inst), false, nil
+		}
+		errs = append(errs, r.err.Error())
+		definitive = definitive && isDefinitive(r.err)
+	}
+	return map[string]interface{}{}, definitive, fmt.Errorf("no cloud provider detected: %s", strings.Join(errs, "; "))
+}
+
+// isDefinitive returns true when the error shows the metadata endpoint does not exist: the connection
+// is refused, there is no route to it, its name does not resolve or something else answered. A timeout
+// is not definitive, the endpoint can be coming up.
+func isDefinitive(err error) bool {
+	var dnsErr *net.DNSError
+	var statusErr *statusError
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return true
+	case errors.As(err, &dnsErr):
+		return dnsErr.IsNotFound
+	case errors.As(err, &statusErr):
+		return true
+	}
+	return false
+}
+
+// statusError is returned when the metadata endpoint answers with an unexpected status.
+type statusError struct {
+	method string
+	url    string
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d", e.method, e.url, e.status)
+}
+
+func generateMapping(inst *instance) map[string]interface{} {
+	return map[string]interface{}{
+		"provider":          inst.Provider,
+		"region":            inst.Region,
+		"availability_zone": inst.AvailabilityZone,
+		"instance": map[string]interface{}{
+			"id":   inst.InstanceID,
+			"name": inst.InstanceName,
+		},
+		"machine": map[string]interface{}{
+			"type": inst.MachineType,
+		},
+		"account": map[string]interface{}{
+			"id": inst.AccountID,
+		},
+	}
+}
+
+// request performs a metadata request and returns the body of the response.
+func request(ctx context.Context, client *http.Client, method, url string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// metadata documents are small
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{method: method, url: url, status: resp.StatusCode}
+	}
+	return body, nil
+}
+
+func requestJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, v interface{}) error {
+	body, err := request(ctx, client, http.MethodGet, url, headers)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid metadata returned by %s: %w", url, err)
+	}
+	return nil
+}
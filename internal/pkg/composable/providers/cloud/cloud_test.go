@@ -0,0 +1,370 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	ctesting "github.com/elastic/elastic-agent/internal/pkg/composable/testing"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const awsToken = "imds-token"
+
+const awsIdentity = `{
+  "accountId": "123456789012",
+  "availabilityZone": "eu-west-1b",
+  "imageId": "ami-0abcdef1234567890",
+  "instanceId": "i-0123456789abcdef0",
+  "instanceType": "m5.large",
+  "region": "eu-west-1"
+}`
+
+const gcpDocument = `{
+  "instance": {
+    "id": 8468426298378124734,
+    "name": "gke-node-1",
+    "zone": "projects/123456/zones/us-central1-a",
+    "machineType": "projects/123456/machineTypes/n1-standard-4"
+  },
+  "project": {"projectId": "my-project", "numericProjectId": 123456}
+}`
+
+const azureDocument = `{
+  "vmId": "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+  "name": "vm-1",
+  "location": "westeurope",
+  "zone": "2",
+  "vmSize": "Standard_D2s_v3",
+  "subscriptionId": "8d10da13-8125-4ba9-a717-bf7490507b3d"
+}`
+
+func awsHandler(imdsV2 bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == awsTokenPath && r.Method == http.MethodPut:
+			if !imdsV2 {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			fmt.Fprint(w, awsToken)
+		case r.URL.Path == awsDocumentPath:
+			if imdsV2 && r.Header.Get("X-aws-ec2-metadata-token") != awsToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, awsIdentity)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func gcpHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/computeMetadata/v1/" || r.Header.Get("Metadata-Flavor") != "Google" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	fmt.Fprint(w, gcpDocument)
+}
+
+func azureHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/metadata/instance/compute" || r.Header.Get("Metadata") != "true" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	fmt.Fprint(w, azureDocument)
+}
+
+func notFoundHandler(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func TestContextProvider(t *testing.T) {
+	awsMapping := map[string]interface{}{
+		"provider":          "aws",
+		"region":            "eu-west-1",
+		"availability_zone": "eu-west-1b",
+		"instance":          map[string]interface{}{"id": "i-0123456789abcdef0", "name": ""},
+		"machine":           map[string]interface{}{"type": "m5.large"},
+		"account":           map[string]interface{}{"id": "123456789012"},
+	}
+	gcpMapping := map[string]interface{}{
+		"provider":          "gcp",
+		"region":            "us-central1",
+		"availability_zone": "us-central1-a",
+		"instance":          map[string]interface{}{"id": "8468426298378124734", "name": "gke-node-1"},
+		"machine":           map[string]interface{}{"type": "n1-standard-4"},
+		"account":           map[string]interface{}{"id": "my-project"},
+	}
+	azureMapping := map[string]interface{}{
+		"provider":          "azure",
+		"region":            "westeurope",
+		"availability_zone": "2",
+		"instance":          map[string]interface{}{"id": "02aab8a4-74ef-476e-8182-f6d2ba4166a6", "name": "vm-1"},
+		"machine":           map[string]interface{}{"type": "Standard_D2s_v3"},
+		"account":           map[string]interface{}{"id": "8d10da13-8125-4ba9-a717-bf7490507b3d"},
+	}
+
+	testCases := map[string]struct {
+		aws, gcp, azure http.HandlerFunc
+		providers       []string
+		expected        map[string]interface{}
+	}{
+		"aws imdsv2": {
+			aws:      awsHandler(true),
+			expected: awsMapping,
+		},
+		"aws imdsv1": {
+			aws:      awsHandler(false),
+			expected: awsMapping,
+		},
+		"gcp": {
+			gcp:      gcpHandler,
+			expected: gcpMapping,
+		},
+		"azure": {
+			azure:    azureHandler,
+			expected: azureMapping,
+		},
+		"first configured provider wins": {
+			aws:       awsHandler(true),
+			azure:     azureHandler,
+			providers: []string{"azure", "aws"},
+			expected:  azureMapping,
+		},
+		"only configured providers": {
+			aws:       awsHandler(true),
+			providers: []string{"gcp", "azure"},
+			expected:  map[string]interface{}{},
+		},
+		"not on a cloud": {
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := map[string]interface{}{
+				"aws.url":   newStandIn(t, tc.aws),
+				"gcp.url":   newStandIn(t, tc.gcp),
+				"azure.url": newStandIn(t, tc.azure),
+			}
+			if tc.providers != nil {
+				cfg["providers"] = tc.providers
+			}
+			provider := newProvider(t, cfg)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			comm := ctesting.NewContextComm(ctx)
+			require.NoError(t, provider.Run(comm))
+			assert.Equal(t, tc.expected, waitMapping(t, comm))
+		})
+	}
+}
+
+func TestContextProviderTimeout(t *testing.T) {
+	hanging := func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}
+	provider := newProvider(t, map[string]interface{}{
+		"timeout":   "50ms",
+		"aws.url":   newStandIn(t, hanging),
+		"gcp.url":   newStandIn(t, hanging),
+		"azure.url": newStandIn(t, azureHandler),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewContextComm(ctx)
+	require.NoError(t, provider.Run(comm))
+	assert.Equal(t, "azure", waitMapping(t, comm)["provider"])
+}
+
+func TestContextProviderCache(t *testing.T) {
+	var mx sync.Mutex
+	handler := awsHandler(true)
+	probes := 0
+	url := newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		h := handler
+		if r.URL.Path == awsDocumentPath {
+			probes++
+		}
+		mx.Unlock()
+		h(w, r)
+	})
+	provider := newProvider(t, map[string]interface{}{
+		"cache_ttl": "100ms",
+		"providers": []string{"aws"},
+		"aws.url":   url,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewContextComm(ctx)
+	require.NoError(t, provider.Run(comm))
+	assert.Equal(t, "aws", waitMapping(t, comm)["provider"])
+
+	// metadata is kept when a refresh fails, until it expires again
+	mx.Lock()
+	handler = notFoundHandler
+	failedAt := probes
+	mx.Unlock()
+	require.Eventually(t, func() bool {
+		mx.Lock()
+		defer mx.Unlock()
+		return probes > failedAt
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "aws", comm.Current()["provider"])
+	assert.Eventually(t, func() bool {
+		return len(comm.Current()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestContextProviderNotOnCloud(t *testing.T) {
+	var mx sync.Mutex
+	probes := 0
+	url := newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		probes++
+		mx.Unlock()
+		notFoundHandler(w, r)
+	})
+	provider := newProvider(t, map[string]interface{}{
+		"cache_ttl": "20ms",
+		"providers": []string{"aws"},
+		"aws.url":   url,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewContextComm(ctx)
+	require.NoError(t, provider.Run(comm))
+	assert.Empty(t, waitMapping(t, comm))
+
+	// endpoints are not probed again
+	mx.Lock()
+	after := probes
+	mx.Unlock()
+	<-time.After(200 * time.Millisecond)
+	mx.Lock()
+	defer mx.Unlock()
+	assert.Equal(t, after, probes)
+}
+
+func TestContextProviderRetryAtStartup(t *testing.T) {
+	defer setProbeBackoff(10 * time.Millisecond)()
+
+	var mx sync.Mutex
+	probes := 0
+	aws := awsHandler(true)
+	url := newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		mx.Lock()
+		if r.URL.Path == awsTokenPath {
+			probes++
+		}
+		booting := probes < startupProbes
+		mx.Unlock()
+		if booting {
+			// the metadata endpoint does not answer while the instance boots
+			<-r.Context().Done()
+			return
+		}
+		aws(w, r)
+	})
+	provider := newProvider(t, map[string]interface{}{
+		"timeout":   "50ms",
+		"providers": []string{"aws"},
+		"aws.url":   url,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewContextComm(ctx)
+	require.NoError(t, provider.Run(comm))
+	assert.Equal(t, "aws", waitMapping(t, comm)["provider"])
+}
+
+func TestContextProviderConnectionRefused(t *testing.T) {
+	// probes are not retried, the test times out otherwise
+	defer setProbeBackoff(time.Hour)()
+
+	srv := httptest.NewServer(http.HandlerFunc(notFoundHandler))
+	url := srv.URL
+	srv.Close()
+	provider := newProvider(t, map[string]interface{}{
+		"providers": []string{"aws"},
+		"aws.url":   url,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewContextComm(ctx)
+	require.NoError(t, provider.Run(comm))
+	assert.Empty(t, waitMapping(t, comm))
+}
+
+func TestContextProviderInvalidConfig(t *testing.T) {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"providers": []string{"aws", "digitalocean"},
+	})
+	require.NoError(t, err)
+	log, err := logger.New("cloud_test", false)
+	require.NoError(t, err)
+	_, err = ContextProviderBuilder(log, cfg)
+	assert.Error(t, err)
+}
+
+// waitMapping waits for the first mapping set by the provider.
+func waitMapping(t *testing.T, comm *ctesting.ContextComm) map[string]interface{} {
+	require.Eventually(t, func() bool {
+		return comm.Current() != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return comm.Current()
+}
+
+// newStandIn starts a local metadata endpoint, every request is not found when handler is nil.
+func newStandIn(t *testing.T, handler http.HandlerFunc) string {
+	if handler == nil {
+		handler = notFoundHandler
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// setProbeBackoff sets the delay between the probes at startup and returns a func restoring it.
+func setProbeBackoff(d time.Duration) func() {
+	prevInit, prevMax := probeBackoffInit, probeBackoffMax
+	probeBackoffInit, probeBackoffMax = d, d
+	return func() {
+		probeBackoffInit, probeBackoffMax = prevInit, prevMax
+	}
+}
+
+func newProvider(t *testing.T, cfg map[string]interface{}) *contextProvider {
+	c, err := config.NewConfigFrom(cfg)
+	require.NoError(t, err)
+	log, err := logger.New("cloud_test", false)
+	require.NoError(t, err)
+
+	builder, _ := composable.Providers.GetContextProvider("cloud")
+	provider, err := builder(log, c)
+	require.NoError(t, err)
+	return provider.(*contextProvider)
+}
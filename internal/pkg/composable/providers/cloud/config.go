@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloud
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config for cloud provider
+type Config struct {
+	// Providers are the clouds probed, when several answer the first one in the list is used. All the
+	// clouds are probed when empty.
+	Providers []string `config:"providers"`
+	// Timeout is the timeout of the requests to a metadata endpoint.
+	Timeout time.Duration `config:"timeout" validate:"positive,nonzero"`
+	// CacheTTL is how long the metadata is kept before the endpoints are probed again.
+	CacheTTL time.Duration `config:"cache_ttl" validate:"positive,nonzero"`
+
+	AWS   EndpointConfig `config:"aws"`
+	GCP   EndpointConfig `config:"gcp"`
+	Azure EndpointConfig `config:"azure"`
+}
+
+// EndpointConfig is the metadata endpoint of a cloud.
+type EndpointConfig struct {
+	URL string `config:"url"`
+}
+
+// InitDefaults initializes the default values for the config.
+func (c *Config) InitDefaults() {
+	c.Timeout = 2 * time.Second
+	c.CacheTTL = time.Hour
+	c.AWS.URL = "http://169.254.169.254"
+	c.GCP.URL = "http://metadata.google.internal"
+	c.Azure.URL = "http://169.254.169.254"
+}
+
+// Validate validates the config.
+func (c *Config) Validate() error {
+	for _, name := range c.Providers {
+		if _, ok := fetchers[name]; !ok {
+			return fmt.Errorf("unknown cloud provider '%s'", name)
+		}
+	}
+	return nil
+}
+
+// url returns the metadata endpoint of a cloud.
+func (c *Config) url(name string) string {
+	switch name {
+	case awsName:
+		return c.AWS.URL
+	case gcpName:
+		return c.GCP.URL
+	case azureName:
+		return c.Azure.URL
+	}
+	return ""
+}
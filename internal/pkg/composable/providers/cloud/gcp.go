@@ -0,0 +1,62 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	gcpName = "gcp"
+
+	gcpMetadataPath = "/computeMetadata/v1/?recursive=true"
+)
+
+type gcpMetadata struct {
+	Instance struct {
+		// ID is a number larger than what a float64 holds exactly
+		ID          json.Number `json:"id"`
+		Name        string      `json:"name"`
+		Zone        string      `json:"zone"`
+		MachineType string      `json:"machineType"`
+	} `json:"instance"`
+	Project struct {
+		ProjectID string `json:"projectId"`
+	} `json:"project"`
+}
+
+// fetchGCP reads the instance and project metadata.
+func fetchGCP(ctx context.Context, client *http.Client, url string) (*instance, error) {
+	var md gcpMetadata
+	err := requestJSON(ctx, client, url+gcpMetadataPath, map[string]string{"Metadata-Flavor": "Google"}, &md)
+	if err != nil {
+		return nil, err
+	}
+	if md.Instance.ID == "" {
+		return nil, errors.New("metadata has no instance id")
+	}
+
+	// zone and machine type are paths, e.g. projects/123/zones/us-central1-a
+	zone := path.Base(md.Instance.Zone)
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+
+	return &instance{
+		Provider:         gcpName,
+		Region:           region,
+		AvailabilityZone: zone,
+		InstanceID:       md.Instance.ID.String(),
+		InstanceName:     md.Instance.Name,
+		MachineType:      path.Base(md.Instance.MachineType),
+		AccountID:        md.Project.ProjectID,
+	}, nil
+}
@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package filedynamic
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/application/paths"
+)
+
+// Config for file dynamic provider
+type Config struct {
+	// Path is the directory of the descriptors, every *.yml, *.yaml and *.json file is an item.
+	Path string `config:"path"`
+	// Period is the interval the directory is scanned for changes.
+	Period time.Duration `config:"period" validate:"positive,nonzero"`
+	// Priority of the items, a descriptor can override it.
+	Priority int `config:"priority"`
+}
+
+// InitDefaults initializes the default values for the config.
+func (c *Config) InitDefaults() {
+	c.Path = filepath.Join(paths.Config(), "dynamic.d")
+	c.Period = 10 * time.Second
+	c.Priority = ItemPriority
+}
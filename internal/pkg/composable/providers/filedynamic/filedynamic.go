@@ -0,0 +1,159 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package filedynamic
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/internal/pkg/filewatcher"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// ItemPriority is the default priority that item mappings are added to the provider.
+const ItemPriority = 0
+
+// extensions of the descriptor files, JSON is loaded as YAML.
+var extensions = []string{"*.yml", "*.yaml", "*.json"}
+
+func init() {
+	composable.Providers.AddDynamicProvider("file_dynamic", DynamicProviderBuilder)
+}
+
+// descriptor is the content of a file, the name of the file is the ID of the item.
+type descriptor struct {
+	Priority   *int                     `config:"priority"`
+	Mapping    map[string]interface{}   `config:"vars"`
+	Processors []map[string]interface{} `config:"processors"`
+}
+
+type dynamicProvider struct {
+	logger  *logger.Logger
+	config  *Config
+	watcher *filewatcher.Watch
+	// items are the IDs of the items added to the provider.
+	items map[string]struct{}
+}
+
+// DynamicProviderBuilder builds the dynamic provider.
+func DynamicProviderBuilder(logger *logger.Logger, c *config.Config) (composable.DynamicProvider, error) {
+	var cfg Config
+	if c == nil {
+		c = config.New()
+	}
+	err := c.Unpack(&cfg)
+	if err != nil {
+		return nil, errors.New(err, "failed to unpack configuration")
+	}
+
+	w, err := filewatcher.New(logger, filewatcher.DefaultComparer)
+	if err != nil {
+		return nil, errors.New(err, "failed to create file watcher")
+	}
+
+	return &dynamicProvider{logger: logger, config: &cfg, watcher: w, items: make(map[string]struct{})}, nil
+}
+
+// Run adds the items of the directory and keeps them up to date until the provider is stopped.
+func (p *dynamicProvider) Run(comm composable.DynamicProviderComm) error {
+	p.refresh(comm)
+
+	go func() {
+		for {
+			t := time.NewTimer(p.config.Period)
+			select {
+			case <-comm.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			p.refresh(comm)
+		}
+	}()
+	return nil
+}
+
+// refresh scans the directory, the provider is degraded while the directory cannot be scanned.
+func (p *dynamicProvider) refresh(comm composable.DynamicProviderComm) {
+	if err := p.scan(comm); err != nil {
+		p.logger.Errorf("%s", err)
+		composable.ReportDegraded(comm, err.Error())
+		return
+	}
+	composable.ReportHealthy(comm)
+}
+
+// scan adds or updates the items of the created or changed files and removes the items of the deleted
+// files. An invalid descriptor keeps the previous item, it is loaded again when the file changes.
+func (p *dynamicProvider) scan(comm composable.DynamicProviderComm) error {
+	p.watcher.Reset()
+	found := make(map[string]struct{})
+	for _, ext := range extensions {
+		files, err := filepath.Glob(filepath.Join(p.config.Path, ext))
+		if err != nil {
+			return errors.New(err, "could not discover descriptors", errors.TypeConfig, errors.M(errors.MetaKeyPath, p.config.Path))
+		}
+		for _, f := range files {
+			p.watcher.Watch(f)
+			found[filepath.Base(f)] = struct{}{}
+		}
+	}
+
+	for id := range p.items {
+		if _, ok := found[id]; !ok {
+			comm.Remove(id)
+			delete(p.items, id)
+		}
+	}
+
+	st, err := p.watcher.Update()
+	if err != nil {
+		// a file was removed during the scan, every file is loaded again on next scan.
+		p.watcher.Invalidate()
+		return errors.New(err, "could not update the descriptors states", errors.TypeFilesystem, errors.M(errors.MetaKeyPath, p.config.Path))
+	}
+
+	for _, f := range st.Updated {
+		d, err := loadDescriptor(f)
+		if err != nil {
+			p.logger.Errorf("%s", err)
+			continue
+		}
+
+		priority := p.config.Priority
+		if d.Priority != nil {
+			priority = *d.Priority
+		}
+		id := filepath.Base(f)
+		if err := comm.AddOrUpdate(id, priority, d.Mapping, d.Processors); err != nil {
+			p.logger.Errorf("failed to add mapping for descriptor %s: %s", f, err)
+			continue
+		}
+		p.items[id] = struct{}{}
+	}
+
+	return nil
+}
+
+func loadDescriptor(path string) (*descriptor, error) {
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		return nil, errors.New(err, fmt.Sprintf("failed to read descriptor %s", path), errors.TypeConfig, errors.M(errors.MetaKeyPath, path))
+	}
+
+	d := &descriptor{}
+	if err := cfg.Unpack(d); err != nil {
+		return nil, errors.New(err, fmt.Sprintf("failed to unpack descriptor %s", path), errors.TypeConfig, errors.M(errors.MetaKeyPath, path))
+	}
+	if d.Mapping == nil {
+		d.Mapping = map[string]interface{}{}
+	}
+	return d, nil
+}
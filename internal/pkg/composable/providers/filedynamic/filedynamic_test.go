@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package filedynamic
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	ctesting "github.com/elastic/elastic-agent/internal/pkg/composable/testing"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+)
+
+func TestDynamicProvider(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "nginx.yml", `
+priority: 5
+vars:
+  service: nginx
+  port: 8080
+processors:
+  - add_fields:
+      fields:
+        service: nginx
+      to: dynamic
+`)
+	writeFile(t, dir, "redis.json", `{"vars": {"service": "redis", "port": 6379}}`)
+	writeFile(t, dir, "ignored.txt", `vars: {service: ignored}`)
+
+	p := newProvider(t, dir)
+	comm := ctesting.NewDynamicComm(context.Background())
+	require.NoError(t, p.scan(comm))
+
+	assert.ElementsMatch(t, []string{"nginx.yml", "redis.json"}, comm.CurrentIDs())
+
+	nginx, ok := comm.Current("nginx.yml")
+	require.True(t, ok)
+	assert.Equal(t, 5, nginx.Priority)
+	assert.Equal(t, map[string]interface{}{"service": "nginx", "port": float64(8080)}, nginx.Mapping)
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"add_fields": map[string]interface{}{
+				"fields": map[string]interface{}{
+					"service": "nginx",
+				},
+				"to": "dynamic",
+			},
+		},
+	}, nginx.Processors)
+
+	redis, ok := comm.Current("redis.json")
+	require.True(t, ok)
+	assert.Equal(t, ItemPriority, redis.Priority)
+	assert.Equal(t, map[string]interface{}{"service": "redis", "port": float64(6379)}, redis.Mapping)
+
+	// changed file updates the item
+	writeFile(t, dir, "redis.json", `{"vars": {"service": "redis", "port": 6380}}`)
+	require.NoError(t, p.scan(comm))
+	redis, ok = comm.Current("redis.json")
+	require.True(t, ok)
+	assert.Equal(t, float64(6380), redis.Mapping["port"])
+
+	// invalid descriptor keeps the previous item
+	writeFile(t, dir, "redis.json", `{"vars": `)
+	require.NoError(t, p.scan(comm))
+	redis, ok = comm.Current("redis.json")
+	require.True(t, ok)
+	assert.Equal(t, float64(6380), redis.Mapping["port"])
+
+	// deleted file removes the item
+	require.NoError(t, os.Remove(filepath.Join(dir, "nginx.yml")))
+	require.NoError(t, p.scan(comm))
+	assert.True(t, comm.Deleted("nginx.yml"))
+	assert.ElementsMatch(t, []string{"redis.json"}, comm.CurrentIDs())
+}
+
+func TestDynamicProviderRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "nginx.yml", `vars: {service: nginx}`)
+
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"path":   dir,
+		"period": "10ms",
+	})
+	require.NoError(t, err)
+	builder, _ := composable.Providers.GetDynamicProvider("file_dynamic")
+	provider, err := builder(logp.NewLogger("test_file_dynamic"), cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewDynamicComm(ctx)
+	require.NoError(t, provider.Run(comm))
+
+	_, ok := comm.Current("nginx.yml")
+	assert.True(t, ok, "items are added before Run returns")
+
+	writeFile(t, dir, "redis.yml", `vars: {service: redis}`)
+	assert.Eventually(t, func() bool {
+		_, ok := comm.Current("redis.yml")
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDynamicProviderMissingDirectory(t *testing.T) {
+	p := newProvider(t, filepath.Join(t.TempDir(), "missing"))
+	comm := ctesting.NewDynamicComm(context.Background())
+	require.NoError(t, p.scan(comm))
+	assert.Empty(t, comm.CurrentIDs())
+}
+
+func newProvider(t *testing.T, dir string) *dynamicProvider {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"path": dir,
+	})
+	require.NoError(t, err)
+
+	p, err := DynamicProviderBuilder(logp.NewLogger("test_file_dynamic"), cfg)
+	require.NoError(t, err)
+	return p.(*dynamicProvider)
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
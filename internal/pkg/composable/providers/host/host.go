@@ -63,8 +63,10 @@ func (c *contextProvider) Run(comm corecomp.ContextProviderComm) error {
 			updated, err := c.fetcher()
 			if err != nil {
 				c.logger.Warnf("Failed fetching latest host information: %s", err)
+				composable.ReportDegraded(comm, fmt.Sprintf("host information is stale: %s", err))
 				continue
 			}
+			composable.ReportHealthy(comm)
 			if reflect.DeepEqual(current, updated) {
 				// nothing to do
 				continue
@@ -98,6 +100,11 @@ func ContextProviderBuilder(log *logger.Logger, c *config.Config) (corecomp.Cont
 	return p, nil
 }
 
+// Info returns the host information the provider exposes as the `host` variables.
+func Info() (map[string]interface{}, error) {
+	return getHostInfo()
+}
+
 func getHostInfo() (map[string]interface{}, error) {
 	hostname, err := os.Hostname()
 	if err != nil {
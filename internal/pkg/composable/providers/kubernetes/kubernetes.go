@@ -5,7 +5,9 @@
 package kubernetes
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	k8s "k8s.io/client-go/kubernetes"
 
@@ -50,39 +52,57 @@ func DynamicProviderBuilder(logger *logger.Logger, c *config.Config) (composable
 	return &dynamicProvider{logger, &cfg}, nil
 }
 
+// apiCheckPeriod is how often the API server is checked while the resources are watched.
+var apiCheckPeriod = 30 * time.Second
+
 // Run runs the kubernetes context provider.
+//
+// The watchers reconnect on their own when the connection to the API server is lost, the provider is
+// reported as degraded until the API server can be reached again.
 func (p *dynamicProvider) Run(comm composable.DynamicProviderComm) error {
-	if p.config.Resources.Pod.Enabled {
-		err := p.watchResource(comm, "pod")
-		if err != nil {
-			return err
+	var client k8s.Interface
+	for _, resource := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"pod", p.config.Resources.Pod.Enabled},
+		{"node", p.config.Resources.Node.Enabled},
+		{"service", p.config.Resources.Service.Enabled},
+	} {
+		if !resource.enabled {
+			continue
 		}
-	}
-	if p.config.Resources.Node.Enabled {
-		err := p.watchResource(comm, "node")
+		c, err := p.watchResource(comm, resource.name)
 		if err != nil {
 			return err
 		}
-	}
-	if p.config.Resources.Service.Enabled {
-		err := p.watchResource(comm, "service")
-		if err != nil {
-			return err
+		if c != nil {
+			client = c
 		}
 	}
+	if client != nil {
+		go composable.MonitorHealth(comm, apiCheckPeriod, func(context.Context) error {
+			if _, err := client.Discovery().ServerVersion(); err != nil {
+				p.logger.Warnf("Kubernetes API server unreachable, watchers are reconnecting: %s", err)
+				return fmt.Errorf("kubernetes API server unreachable: %w", err)
+			}
+			return nil
+		})
+	}
 	return nil
 }
 
 // watchResource initializes the proper watcher according to the given resource (pod, node, service)
-// and starts watching for such resource's events.
+// and starts watching for such resource's events. The client of the watcher is returned, it is nil when
+// the resource is skipped.
 func (p *dynamicProvider) watchResource(
 	comm composable.DynamicProviderComm,
-	resourceType string) error {
+	resourceType string) (k8s.Interface, error) {
 	client, err := kubernetes.GetKubernetesClient(p.config.KubeConfig, p.config.KubeClientOptions)
 	if err != nil {
 		// info only; return nil (do nothing)
 		p.logger.Debugf("Kubernetes provider for resource %s skipped, unable to connect: %s", resourceType, err)
-		return nil
+		return nil, nil
 	}
 
 	// Ensure that node is set correctly whenever the scope is set to "node". Make sure that node is empty
@@ -103,7 +123,7 @@ func (p *dynamicProvider) watchResource(
 		p.config.Node, err = kubernetes.DiscoverKubernetesNode(p.logger, nd)
 		if err != nil {
 			p.logger.Debugf("Kubernetes provider skipped, unable to discover node: %w", err)
-			return nil
+			return nil, nil
 		}
 
 	} else {
@@ -112,15 +132,15 @@ func (p *dynamicProvider) watchResource(
 
 	eventer, err := p.newEventer(resourceType, comm, client)
 	if err != nil {
-		return errors.New(err, "couldn't create kubernetes watcher for resource %s", resourceType)
+		return nil, errors.New(err, "couldn't create kubernetes watcher for resource %s", resourceType)
 	}
 
 	err = eventer.Start()
 	if err != nil {
-		return errors.New(err, "couldn't start kubernetes eventer for resource %s", resourceType)
+		return nil, errors.New(err, "couldn't start kubernetes eventer for resource %s", resourceType)
 	}
 
-	return nil
+	return client, nil
 }
 
 // Eventer allows defining ways in which kubernetes resource events are observed and processed
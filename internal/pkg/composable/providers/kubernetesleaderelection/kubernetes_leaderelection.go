@@ -6,6 +6,7 @@ package kubernetesleaderelection
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
@@ -22,6 +23,9 @@ import (
 	"github.com/elastic/elastic-agent/pkg/core/logger"
 )
 
+// apiCheckPeriod is how often the API server is checked while the leader election runs.
+var apiCheckPeriod = 30 * time.Second
+
 func init() {
 	composable.Providers.AddContextProvider("kubernetes_leaderelection", ContextProviderBuilder)
 }
@@ -103,19 +107,32 @@ func (p *contextProvider) Run(comm corecomp.ContextProviderComm) error {
 	ctx, cancel := context.WithCancel(context.TODO())
 	p.cancelLeaderElection = cancel
 	p.comm = comm
-	p.startLeaderElector(ctx)
+	if !p.startLeaderElector(ctx) {
+		return nil
+	}
 
+	// the lease cannot be renewed while the API server is unreachable, the leadership is then lost
+	go composable.MonitorHealth(comm, apiCheckPeriod, func(context.Context) error {
+		if _, err := client.Discovery().ServerVersion(); err != nil {
+			return fmt.Errorf("kubernetes API server unreachable, leader lease cannot be renewed: %w", err)
+		}
+		return nil
+	})
 	return nil
 }
 
-// startLeaderElector starts a Leader Elector in the background with the provided config
-func (p *contextProvider) startLeaderElector(ctx context.Context) {
+// startLeaderElector starts a Leader Elector in the background with the provided config, false is
+// returned when it cannot be created.
+func (p *contextProvider) startLeaderElector(ctx context.Context) bool {
 	le, err := leaderelection.NewLeaderElector(*p.leaderElection)
 	if err != nil {
 		p.logger.Errorf("error while creating Leader Elector: %v", err)
+		composable.ReportDegraded(p.comm, fmt.Sprintf("failed to create leader elector: %s", err))
+		return false
 	}
 	p.logger.Debugf("Starting Leader Elector")
 	go le.Run(ctx)
+	return true
 }
 
 func (p *contextProvider) startLeading(metaUID string) {
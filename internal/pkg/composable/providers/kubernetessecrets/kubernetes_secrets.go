@@ -6,8 +6,10 @@ package kubernetessecrets
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sclient "k8s.io/client-go/kubernetes"
 
@@ -31,6 +33,7 @@ type contextProviderK8sSecrets struct {
 	config *Config
 
 	client k8sclient.Interface
+	comm   corecomp.ContextProviderComm
 }
 
 // ContextProviderBuilder builds the context provider.
@@ -43,7 +46,7 @@ func ContextProviderBuilder(logger *logger.Logger, c *config.Config) (corecomp.C
 	if err != nil {
 		return nil, errors.New(err, "failed to unpack configuration")
 	}
-	return &contextProviderK8sSecrets{logger, &cfg, nil}, nil
+	return &contextProviderK8sSecrets{logger, &cfg, nil, nil}, nil
 }
 
 func (p *contextProviderK8sSecrets) Fetch(key string) (string, bool) {
@@ -72,8 +75,14 @@ func (p *contextProviderK8sSecrets) Fetch(key string) (string, bool) {
 	secret, err := secretIntefrace.Get(ctx, secretName, metav1.GetOptions{})
 	if err != nil {
 		p.logger.Errorf("Could not retrieve secret from k8s API: %v", err)
+		if !apierrors.IsNotFound(err) && p.comm != nil {
+			composable.ReportDegraded(p.comm, fmt.Sprintf("failed to retrieve secret from kubernetes API: %s", err))
+		}
 		return "", false
 	}
+	if p.comm != nil {
+		composable.ReportHealthy(p.comm)
+	}
 	if _, ok := secret.Data[secretVar]; !ok {
 		p.logger.Errorf("Could not retrieve value %v for secret %v", secretVar, secretName)
 		return "", false
@@ -90,6 +99,7 @@ func (p *contextProviderK8sSecrets) Run(comm corecomp.ContextProviderComm) error
 		return nil
 	}
 	p.client = client
+	p.comm = comm
 	return nil
 }
 
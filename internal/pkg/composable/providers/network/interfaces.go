@@ -0,0 +1,170 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package network
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// routeTable is the IPv4 routing table, it only exists on Linux.
+var routeTable = "/proc/net/route"
+
+// netInterface is a network interface with its addresses.
+type netInterface struct {
+	Name  string
+	Index int
+	MTU   int
+	MAC   string
+	Up    bool
+	Addrs []net.IP
+}
+
+// route is the default route of the host.
+type route struct {
+	Interface string
+	Gateway   string
+}
+
+func getNetworkInfo() (map[string]interface{}, error) {
+	ifaces, err := listInterfaces()
+	if err != nil {
+		return nil, err
+	}
+	def, err := readDefaultRoute(routeTable)
+	if err != nil {
+		return nil, err
+	}
+	return generateMapping(ifaces, def), nil
+}
+
+func listInterfaces() ([]netInterface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]netInterface, 0, len(ifaces))
+	for _, iface := range ifaces {
+		n := netInterface{
+			Name:  iface.Name,
+			Index: iface.Index,
+			MTU:   iface.MTU,
+			MAC:   iface.HardwareAddr.String(),
+			Up:    iface.Flags&net.FlagUp != 0,
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			// interface removed while listing
+			continue
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok {
+				n.Addrs = append(n.Addrs, ipNet.IP)
+			}
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// generateMapping returns the `network` variables.
+//
+// `ip` is the first IPv4 address of the interface, or its first IPv6 address when it has no IPv4 address.
+func generateMapping(ifaces []netInterface, def route) map[string]interface{} {
+	interfaces := make(map[string]interface{}, len(ifaces))
+	for _, iface := range ifaces {
+		ipv4 := []interface{}{}
+		ipv6 := []interface{}{}
+		for _, ip := range iface.Addrs {
+			if ip.To4() != nil {
+				ipv4 = append(ipv4, ip.String())
+			} else {
+				ipv6 = append(ipv6, ip.String())
+			}
+		}
+		primary := ""
+		if len(ipv4) > 0 {
+			primary = ipv4[0].(string)
+		} else if len(ipv6) > 0 {
+			primary = ipv6[0].(string)
+		}
+
+		interfaces[iface.Name] = map[string]interface{}{
+			"name":    iface.Name,
+			"index":   iface.Index,
+			"mtu":     iface.MTU,
+			"mac":     iface.MAC,
+			"up":      iface.Up,
+			"ip":      primary,
+			"ipv4":    ipv4,
+			"ipv6":    ipv6,
+			"default": iface.Name == def.Interface,
+		}
+	}
+
+	return map[string]interface{}{
+		"interfaces": interfaces,
+		"default_route": map[string]interface{}{
+			"interface": def.Interface,
+			"gateway":   def.Gateway,
+		},
+	}
+}
+
+// readDefaultRoute reads the IPv4 default route from the routing table, an empty route is returned
+// when the table does not exist.
+func readDefaultRoute(path string) (route, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return route{}, nil
+	}
+	if err != nil {
+		return route{}, err
+	}
+	defer f.Close()
+	return parseDefaultRoute(f)
+}
+
+// parseDefaultRoute returns the default route with the lowest metric from a /proc/net/route table, e.g.
+//
+//	Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+//	eth0	00000000	0100000A	0003	0	0	100	00000000	0	0	0
+func parseDefaultRoute(r io.Reader) (route, error) {
+	var (
+		def    route
+		metric = -1
+	)
+
+	scanner := bufio.NewScanner(r)
+	// header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 || fields[1] != "00000000" || fields[7] != "00000000" {
+			continue
+		}
+		m, err := strconv.Atoi(fields[6])
+		if err != nil || (metric >= 0 && m >= metric) {
+			continue
+		}
+		gw, err := hex.DecodeString(fields[2])
+		if err != nil || len(gw) != net.IPv4len {
+			continue
+		}
+		// addresses are in host byte order, little endian
+		def = route{
+			Interface: fields[0],
+			Gateway:   net.IPv4(gw[3], gw[2], gw[1], gw[0]).String(),
+		}
+		metric = m
+	}
+	return def, scanner.Err()
+}
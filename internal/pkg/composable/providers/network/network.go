@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package network
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	corecomp "github.com/elastic/elastic-agent/internal/pkg/core/composable"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// DefaultCheckInterval is the default interval the interfaces are read when no change is reported.
+const DefaultCheckInterval = time.Minute
+
+func init() {
+	composable.Providers.AddContextProvider("network", ContextProviderBuilder)
+}
+
+type infoFetcher func() (map[string]interface{}, error)
+
+// changesWatcher returns a channel receiving a value when the interfaces, addresses or routes change.
+// The channel is closed when watching stops before done is closed.
+type changesWatcher func(done <-chan struct{}) (<-chan struct{}, error)
+
+type contextProvider struct {
+	logger *logger.Logger
+
+	CheckInterval time.Duration `config:"check_interval"`
+
+	// used by testing
+	fetcher infoFetcher
+	watcher changesWatcher
+}
+
+// ContextProviderBuilder builds the context provider.
+func ContextProviderBuilder(log *logger.Logger, c *config.Config) (corecomp.ContextProvider, error) {
+	p := &contextProvider{
+		logger:  log,
+		fetcher: getNetworkInfo,
+		watcher: watchChanges,
+	}
+	if c != nil {
+		err := c.Unpack(p)
+		if err != nil {
+			return nil, errors.New(err, "failed to unpack configuration")
+		}
+	}
+	if p.CheckInterval <= 0 {
+		p.CheckInterval = DefaultCheckInterval
+	}
+	return p, nil
+}
+
+// Run runs the network context provider.
+func (c *contextProvider) Run(comm corecomp.ContextProviderComm) error {
+	current, err := c.fetcher()
+	if err != nil {
+		return errors.New(err, "failed to read network interfaces", errors.TypeNetwork)
+	}
+	err = comm.Set(current)
+	if err != nil {
+		return errors.New(err, "failed to set mapping", errors.TypeUnexpected)
+	}
+
+	changes, err := c.watcher(comm.Done())
+	if err != nil {
+		// interfaces are still read every check interval
+		c.logger.Infof("Network provider is not notified of changes, interfaces are read every %s: %s", c.CheckInterval, err)
+	}
+
+	go func() {
+		for {
+			t := time.NewTimer(c.CheckInterval)
+			select {
+			case <-comm.Done():
+				t.Stop()
+				return
+			case _, ok := <-changes:
+				t.Stop()
+				if !ok {
+					c.logger.Warnf("Network provider stopped receiving changes, interfaces are read every %s", c.CheckInterval)
+					changes = nil
+				}
+			case <-t.C:
+			}
+
+			updated, err := c.fetcher()
+			if err != nil {
+				c.logger.Warnf("Failed reading network interfaces: %s", err)
+				composable.ReportDegraded(comm, fmt.Sprintf("network interfaces are stale: %s", err))
+				continue
+			}
+			composable.ReportHealthy(comm)
+			if reflect.DeepEqual(current, updated) {
+				// nothing to do
+				continue
+			}
+			current = updated
+			err = comm.Set(updated)
+			if err != nil {
+				c.logger.Errorf("Failed updating mapping to latest network interfaces: %s", err)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	ctesting "github.com/elastic/elastic-agent/internal/pkg/composable/testing"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const routes = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	0000000A	00000000	0001	0	0	0	0000FFFF	0	0	0
+wlan0	00000000	0101A8C0	0003	0	0	600	00000000	0	0	0
+eth0	00000000	0100000A	0003	0	0	100	00000000	0	0	0
+`
+
+var eth0 = netInterface{
+	Name:  "eth0",
+	Index: 2,
+	MTU:   1500,
+	MAC:   "02:42:ac:11:00:02",
+	Up:    true,
+	Addrs: []net.IP{net.ParseIP("fe80::42:acff:fe11:2"), net.ParseIP("10.0.0.2")},
+}
+
+func TestContextProvider(t *testing.T) {
+	c, err := config.NewConfigFrom(map[string]interface{}{
+		"check_interval": time.Hour,
+	})
+	require.NoError(t, err)
+	builder, _ := composable.Providers.GetContextProvider("network")
+	log, err := logger.New("network_test", false)
+	require.NoError(t, err)
+	provider, err := builder(log, c)
+	require.NoError(t, err)
+
+	networkProvider := provider.(*contextProvider)
+	require.Equal(t, time.Hour, networkProvider.CheckInterval)
+
+	var mx sync.Mutex
+	mtu := 1500
+	networkProvider.fetcher = func() (map[string]interface{}, error) {
+		mx.Lock()
+		defer mx.Unlock()
+		iface := eth0
+		iface.MTU = mtu
+		return generateMapping([]netInterface{iface}, route{Interface: "eth0", Gateway: "10.0.0.1"}), nil
+	}
+	changes := make(chan struct{})
+	networkProvider.watcher = func(<-chan struct{}) (<-chan struct{}, error) {
+		return changes, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewContextComm(ctx)
+	require.NoError(t, provider.Run(comm))
+	assert.Equal(t, float64(1500), mapValue(comm.Current(), "interfaces.eth0.mtu"))
+	assert.Equal(t, "10.0.0.2", mapValue(comm.Current(), "interfaces.eth0.ip"))
+
+	// change notification reads the interfaces again
+	var wg sync.WaitGroup
+	wg.Add(1)
+	comm.CallOnSet(func() {
+		wg.Done()
+	})
+	mx.Lock()
+	mtu = 9000
+	mx.Unlock()
+	changes <- struct{}{}
+	wg.Wait()
+	comm.CallOnSet(nil)
+	assert.Equal(t, float64(9000), mapValue(comm.Current(), "interfaces.eth0.mtu"))
+}
+
+func TestContextProviderNoWatcher(t *testing.T) {
+	log, err := logger.New("network_test", false)
+	require.NoError(t, err)
+	provider, err := ContextProviderBuilder(log, nil)
+	require.NoError(t, err)
+
+	networkProvider := provider.(*contextProvider)
+	require.Equal(t, DefaultCheckInterval, networkProvider.CheckInterval)
+	networkProvider.CheckInterval = 10 * time.Millisecond
+
+	var mx sync.Mutex
+	up := true
+	networkProvider.fetcher = func() (map[string]interface{}, error) {
+		mx.Lock()
+		defer mx.Unlock()
+		iface := eth0
+		iface.Up = up
+		return generateMapping([]netInterface{iface}, route{}), nil
+	}
+	networkProvider.watcher = func(<-chan struct{}) (<-chan struct{}, error) {
+		return nil, errors.New("not supported")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	comm := ctesting.NewContextComm(ctx)
+	require.NoError(t, provider.Run(comm))
+	assert.Equal(t, true, mapValue(comm.Current(), "interfaces.eth0.up"))
+
+	// interfaces are still read every check interval
+	mx.Lock()
+	up = false
+	mx.Unlock()
+	assert.Eventually(t, func() bool {
+		return mapValue(comm.Current(), "interfaces.eth0.up") == false
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestGenerateMapping(t *testing.T) {
+	lo := netInterface{
+		Name:  "lo",
+		Index: 1,
+		MTU:   65536,
+		Up:    true,
+		Addrs: []net.IP{net.ParseIP("::1")},
+	}
+	down := netInterface{Name: "eth1", Index: 3, MTU: 1500}
+
+	mapping := generateMapping([]netInterface{lo, eth0, down}, route{Interface: "eth0", Gateway: "10.0.0.1"})
+	assert.Equal(t, map[string]interface{}{
+		"interfaces": map[string]interface{}{
+			"lo": map[string]interface{}{
+				"name":    "lo",
+				"index":   1,
+				"mtu":     65536,
+				"mac":     "",
+				"up":      true,
+				"ip":      "::1",
+				"ipv4":    []interface{}{},
+				"ipv6":    []interface{}{"::1"},
+				"default": false,
+			},
+			"eth0": map[string]interface{}{
+				"name":    "eth0",
+				"index":   2,
+				"mtu":     1500,
+				"mac":     "02:42:ac:11:00:02",
+				"up":      true,
+				"ip":      "10.0.0.2",
+				"ipv4":    []interface{}{"10.0.0.2"},
+				"ipv6":    []interface{}{"fe80::42:acff:fe11:2"},
+				"default": true,
+			},
+			"eth1": map[string]interface{}{
+				"name":    "eth1",
+				"index":   3,
+				"mtu":     1500,
+				"mac":     "",
+				"up":      false,
+				"ip":      "",
+				"ipv4":    []interface{}{},
+				"ipv6":    []interface{}{},
+				"default": false,
+			},
+		},
+		"default_route": map[string]interface{}{
+			"interface": "eth0",
+			"gateway":   "10.0.0.1",
+		},
+	}, mapping)
+}
+
+func TestParseDefaultRoute(t *testing.T) {
+	def, err := parseDefaultRoute(strings.NewReader(routes))
+	require.NoError(t, err)
+	assert.Equal(t, route{Interface: "eth0", Gateway: "10.0.0.1"}, def)
+
+	def, err = readDefaultRoute(filepath.Join(t.TempDir(), "route"))
+	require.NoError(t, err)
+	assert.Equal(t, route{}, def)
+}
+
+func mapValue(m map[string]interface{}, path string) interface{} {
+	var v interface{} = m
+	for _, key := range strings.Split(path, ".") {
+		inner, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = inner[key]
+	}
+	return v
+}
@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build linux
+// +build linux
+
+package network
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// netlink groups of the link, address and route changes
+const changeGroups = unix.RTMGRP_LINK |
+	unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV6_IFADDR |
+	unix.RTMGRP_IPV4_ROUTE | unix.RTMGRP_IPV6_ROUTE
+
+// watchChanges subscribes to the rtnetlink notifications. The messages are not parsed, any message
+// means the interfaces are read again.
+func watchChanges(done <-chan struct{}) (<-chan struct{}, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: changeGroups})
+	if err != nil {
+		unix.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+	// reads time out so done is checked while no change happens
+	tv := unix.NsecToTimeval(time.Second.Nanoseconds())
+	err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv)
+	if err != nil {
+		unix.Close(fd)
+		return nil, os.NewSyscallError("setsockopt", err)
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer unix.Close(fd)
+
+		buf := make([]byte, os.Getpagesize())
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			_, _, err := unix.Recvfrom(fd, buf, 0)
+			switch err {
+			case nil, unix.ENOBUFS:
+				// ENOBUFS is returned when notifications were dropped, something still changed
+			case unix.EAGAIN, unix.EINTR:
+				continue
+			default:
+				return
+			}
+
+			// pending notification already covers this change
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return changes, nil
+}
@@ -0,0 +1,17 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !linux
+// +build !linux
+
+package network
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func watchChanges(_ <-chan struct{}) (<-chan struct{}, error) {
+	return nil, fmt.Errorf("change notifications are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,27 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import "time"
+
+// Config for process provider
+type Config struct {
+	// ProcPath is the mount point of the proc filesystem.
+	ProcPath string `config:"proc_path"`
+	// Period is the interval /proc is scanned for started and stopped processes.
+	Period time.Duration `config:"period" validate:"positive,nonzero"`
+	// Include is a condition a process must match to be added, variables are escaped so they are not
+	// resolved when the configuration is loaded, e.g. `$${process.name} == 'java'`. No process is added
+	// without it.
+	Include string `config:"include"`
+	// Exclude is a condition removing the processes that match it.
+	Exclude string `config:"exclude"`
+}
+
+// InitDefaults initializes the default values for the config.
+func (c *Config) InitDefaults() {
+	c.ProcPath = "/proc"
+	c.Period = 10 * time.Second
+}
@@ -0,0 +1,215 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/internal/pkg/eql"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// ProcessPriority is the priority that process mappings are added to the provider.
+const ProcessPriority = 0
+
+func init() {
+	composable.Providers.AddDynamicProvider("process", DynamicProviderBuilder)
+}
+
+type dynamicProvider struct {
+	logger  *logger.Logger
+	config  *Config
+	fs      procFS
+	include *eql.Expression
+	exclude *eql.Expression
+	// pids are the processes added to the provider.
+	pids map[string]struct{}
+}
+
+// DynamicProviderBuilder builds the dynamic provider.
+func DynamicProviderBuilder(logger *logger.Logger, c *config.Config) (composable.DynamicProvider, error) {
+	var cfg Config
+	if c == nil {
+		c = config.New()
+	}
+	err := c.Unpack(&cfg)
+	if err != nil {
+		return nil, errors.New(err, "failed to unpack configuration")
+	}
+
+	include, err := newFilter(cfg.Include)
+	if err != nil {
+		return nil, errors.New(err, "invalid include condition", errors.TypeConfig)
+	}
+	exclude, err := newFilter(cfg.Exclude)
+	if err != nil {
+		return nil, errors.New(err, "invalid exclude condition", errors.TypeConfig)
+	}
+
+	return &dynamicProvider{
+		logger:  logger,
+		config:  &cfg,
+		fs:      procFS{root: cfg.ProcPath},
+		include: include,
+		exclude: exclude,
+		pids:    make(map[string]struct{}),
+	}, nil
+}
+
+func newFilter(condition string) (*eql.Expression, error) {
+	if condition == "" {
+		return nil, nil
+	}
+	// unlike eql.New, syntax errors are reported by Analyze.
+	if _, err := eql.Analyze(condition); err != nil {
+		return nil, err
+	}
+	return eql.New(condition)
+}
+
+// Run runs the process dynamic provider.
+func (p *dynamicProvider) Run(comm composable.DynamicProviderComm) error {
+	if p.include == nil {
+		// every input using process variables is rendered once per process, the processes must be selected
+		p.logger.Infof("Process provider skipped, no include condition is configured")
+		return nil
+	}
+	if _, err := os.Stat(p.config.ProcPath); err != nil {
+		// info only; return nil (do nothing)
+		p.logger.Infof("Process provider skipped, unable to read %s: %s", p.config.ProcPath, err)
+		return nil
+	}
+
+	p.refresh(comm)
+
+	go func() {
+		for {
+			t := time.NewTimer(p.config.Period)
+			select {
+			case <-comm.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			p.refresh(comm)
+		}
+	}()
+	return nil
+}
+
+// refresh updates the processes, the provider is degraded while the processes cannot be listed.
+func (p *dynamicProvider) refresh(comm composable.DynamicProviderComm) {
+	if err := p.update(comm); err != nil {
+		p.logger.Errorf("%s", err)
+		composable.ReportDegraded(comm, err.Error())
+		return
+	}
+	composable.ReportHealthy(comm)
+}
+
+// update adds or updates the running processes matching the filters and removes the others.
+func (p *dynamicProvider) update(comm composable.DynamicProviderComm) error {
+	processes, err := p.fs.processes()
+	if err != nil {
+		return errors.New(err, "failed to list processes", errors.TypeFilesystem, errors.M(errors.MetaKeyPath, p.config.ProcPath))
+	}
+
+	running := make(map[string]struct{}, len(processes))
+	for _, proc := range processes {
+		mapping := generateMapping(proc)
+		if !p.matches(mapping) {
+			continue
+		}
+
+		id := strconv.Itoa(proc.PID)
+		if err := comm.AddOrUpdate(id, ProcessPriority, mapping, generateProcessors(proc)); err != nil {
+			p.logger.Errorf("failed to add mapping for process %d: %s", proc.PID, err)
+			continue
+		}
+		running[id] = struct{}{}
+		p.pids[id] = struct{}{}
+	}
+
+	for id := range p.pids {
+		if _, ok := running[id]; !ok {
+			comm.Remove(id)
+			delete(p.pids, id)
+		}
+	}
+	return nil
+}
+
+// matches returns true when the process matches the include condition and not the exclude condition,
+// no process matches without an include condition.
+func (p *dynamicProvider) matches(mapping map[string]interface{}) bool {
+	if p.include == nil {
+		return false
+	}
+
+	store, err := transpiler.NewAST(mapping)
+	if err != nil {
+		p.logger.Debugf("failed to create the variables of a process: %s", err)
+		return false
+	}
+
+	if !p.eval(p.include, store) {
+		return false
+	}
+	if p.exclude != nil && p.eval(p.exclude, store) {
+		return false
+	}
+	return true
+}
+
+func (p *dynamicProvider) eval(expr *eql.Expression, store eql.VarStore) bool {
+	ok, err := expr.Eval(store)
+	if err != nil {
+		p.logger.Debugf("failed to evaluate condition: %s", err)
+		return false
+	}
+	return ok
+}
+
+func generateMapping(proc processInfo) map[string]interface{} {
+	ports := make([]interface{}, 0, len(proc.Ports))
+	for _, port := range proc.Ports {
+		ports = append(ports, port)
+	}
+
+	return map[string]interface{}{
+		"process": map[string]interface{}{
+			"pid":     proc.PID,
+			"name":    proc.Name,
+			"cmdline": proc.Cmdline,
+			"exe":     proc.Exe,
+			"cwd":     proc.Cwd,
+			"ports":   ports,
+		},
+	}
+}
+
+func generateProcessors(proc processInfo) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"add_fields": map[string]interface{}{
+				"fields": map[string]interface{}{
+					"pid":               proc.PID,
+					"name":              proc.Name,
+					"executable":        proc.Exe,
+					"command_line":      proc.Cmdline,
+					"working_directory": proc.Cwd,
+				},
+				"to": "process",
+			},
+		},
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	ctesting "github.com/elastic/elastic-agent/internal/pkg/composable/testing"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+)
+
+const tcpTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1001 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:20FB 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1002 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:A2C4 0100007F:1F90 01 00000000:00000000 00:00000000 00000000  1000        0 1003 1 0000000000000000 20 4 30 10 -1
+`
+
+const udpTable = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  10: 00000000:0202 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 2001 2 0000000000000000 0
+`
+
+type fakeProcess struct {
+	pid     int
+	name    string
+	cmdline string
+	exe     string
+	cwd     string
+	sockets []string
+}
+
+func newFakeProc(t *testing.T, processes ...fakeProcess) string {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "net"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "net", "tcp"), []byte(tcpTable), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "net", "udp"), []byte(udpTable), 0644))
+	// not a process
+	require.NoError(t, ioutil.WriteFile(filepath.Join(root, "uptime"), []byte("1.0 1.0"), 0644))
+
+	for _, p := range processes {
+		addFakeProcess(t, root, p)
+	}
+	return root
+}
+
+func addFakeProcess(t *testing.T, root string, p fakeProcess) {
+	dir := filepath.Join(root, strconv.Itoa(p.pid))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fd"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "comm"), []byte(p.name+"\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte(p.cmdline), 0644))
+	if p.exe != "" {
+		require.NoError(t, os.Symlink(p.exe, filepath.Join(dir, "exe")))
+	}
+	if p.cwd != "" {
+		require.NoError(t, os.Symlink(p.cwd, filepath.Join(dir, "cwd")))
+	}
+	for i, s := range p.sockets {
+		require.NoError(t, os.Symlink(s, filepath.Join(dir, "fd", strconv.Itoa(i+3))))
+	}
+}
+
+var java = fakeProcess{
+	pid:     100,
+	name:    "java",
+	cmdline: "java\x00-jar\x00app.jar\x00",
+	exe:     "/usr/bin/java",
+	cwd:     "/opt/app",
+	sockets: []string{"socket:[1001]", "socket:[1003]", "pipe:[5000]", "socket:[1001]"},
+}
+
+var postgres = fakeProcess{
+	pid:     200,
+	name:    "postgres",
+	cmdline: "/usr/lib/postgresql/bin/postgres\x00-D\x00/var/lib/postgresql\x00",
+	exe:     "/usr/lib/postgresql/bin/postgres",
+	cwd:     "/var/lib/postgresql",
+	sockets: []string{"socket:[1002]", "socket:[2001]"},
+}
+
+var kthread = fakeProcess{
+	pid:  2,
+	name: "kthreadd",
+}
+
+func TestProcFS(t *testing.T) {
+	root := newFakeProc(t, java, postgres, kthread)
+
+	processes, err := procFS{root: root}.processes()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []processInfo{
+		{
+			PID:     100,
+			Name:    "java",
+			Cmdline: "java -jar app.jar",
+			Exe:     "/usr/bin/java",
+			Cwd:     "/opt/app",
+			Ports:   []int{8080},
+		},
+		{
+			PID:     200,
+			Name:    "postgres",
+			Cmdline: "/usr/lib/postgresql/bin/postgres -D /var/lib/postgresql",
+			Exe:     "/usr/lib/postgresql/bin/postgres",
+			Cwd:     "/var/lib/postgresql",
+			Ports:   []int{514, 8443},
+		},
+	}, processes)
+}
+
+func TestDynamicProvider(t *testing.T) {
+	root := newFakeProc(t, java, postgres, kthread)
+
+	p := newProvider(t, map[string]interface{}{"proc_path": root, "include": "$${process.pid} > 0"})
+	comm := ctesting.NewDynamicComm(context.Background())
+	require.NoError(t, p.Run(comm))
+	assert.ElementsMatch(t, []string{"100", "200"}, comm.CurrentIDs())
+
+	curr, ok := comm.Current("100")
+	require.True(t, ok)
+	assert.Equal(t, ProcessPriority, curr.Priority)
+	assert.Equal(t, map[string]interface{}{
+		"process": map[string]interface{}{
+			"pid":     float64(100),
+			"name":    "java",
+			"cmdline": "java -jar app.jar",
+			"exe":     "/usr/bin/java",
+			"cwd":     "/opt/app",
+			"ports":   []interface{}{float64(8080)},
+		},
+	}, curr.Mapping)
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"add_fields": map[string]interface{}{
+				"fields": map[string]interface{}{
+					"pid":               float64(100),
+					"name":              "java",
+					"executable":        "/usr/bin/java",
+					"command_line":      "java -jar app.jar",
+					"working_directory": "/opt/app",
+				},
+				"to": "process",
+			},
+		},
+	}, curr.Processors)
+
+	// stopped process is removed, started process is added
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "200")))
+	addFakeProcess(t, root, fakeProcess{pid: 300, name: "redis", cmdline: "redis-server\x00"})
+	require.NoError(t, p.update(comm))
+	assert.True(t, comm.Deleted("200"))
+	assert.ElementsMatch(t, []string{"100", "300"}, comm.CurrentIDs())
+}
+
+func TestDynamicProviderFilters(t *testing.T) {
+	root := newFakeProc(t, java, postgres)
+
+	testCases := map[string]struct {
+		cfg      map[string]interface{}
+		expected []string
+	}{
+		"include": {
+			cfg:      map[string]interface{}{"include": "$${process.name} == 'java'"},
+			expected: []string{"100"},
+		},
+		"include ports": {
+			cfg:      map[string]interface{}{"include": "arrayContains($${process.ports}, 8443)"},
+			expected: []string{"200"},
+		},
+		"exclude": {
+			cfg: map[string]interface{}{
+				"include": "$${process.pid} > 0",
+				"exclude": "startsWith($${process.exe}, '/usr/lib/postgresql')",
+			},
+			expected: []string{"100"},
+		},
+		"exclude without include": {
+			cfg:      map[string]interface{}{"exclude": "startsWith($${process.exe}, '/usr/lib/postgresql')"},
+			expected: []string{},
+		},
+		"include and exclude": {
+			cfg: map[string]interface{}{
+				"include": "$${process.name} == 'java' or $${process.name} == 'postgres'",
+				"exclude": "$${process.cwd} == '/opt/app'",
+			},
+			expected: []string{"200"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tc.cfg["proc_path"] = root
+			p := newProvider(t, tc.cfg)
+			comm := ctesting.NewDynamicComm(context.Background())
+			require.NoError(t, p.update(comm))
+			assert.ElementsMatch(t, tc.expected, comm.CurrentIDs())
+		})
+	}
+}
+
+func TestDynamicProviderInvalidFilter(t *testing.T) {
+	cfg, err := config.NewConfigFrom(map[string]interface{}{"include": "$${process.name} == "})
+	require.NoError(t, err)
+	_, err = DynamicProviderBuilder(logp.NewLogger("test_process"), cfg)
+	assert.Error(t, err)
+}
+
+func TestDynamicProviderNoInclude(t *testing.T) {
+	root := newFakeProc(t, java, postgres)
+
+	p := newProvider(t, map[string]interface{}{"proc_path": root})
+	comm := ctesting.NewDynamicComm(context.Background())
+	require.NoError(t, p.Run(comm))
+	assert.Empty(t, comm.CurrentIDs())
+}
+
+func TestDynamicProviderNoProc(t *testing.T) {
+	p := newProvider(t, map[string]interface{}{
+		"proc_path": filepath.Join(t.TempDir(), "missing"),
+		"include":   "$${process.pid} > 0",
+	})
+	comm := ctesting.NewDynamicComm(context.Background())
+	require.NoError(t, p.Run(comm))
+	assert.Empty(t, comm.CurrentIDs())
+}
+
+func newProvider(t *testing.T, cfg map[string]interface{}) *dynamicProvider {
+	c, err := config.NewConfigFrom(cfg)
+	require.NoError(t, err)
+
+	builder, _ := composable.Providers.GetDynamicProvider("process")
+	p, err := builder(logp.NewLogger("test_process"), c)
+	require.NoError(t, err)
+	return p.(*dynamicProvider)
+}
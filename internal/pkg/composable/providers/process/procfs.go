@@ -0,0 +1,157 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// socket states in /proc/net files
+	tcpListen   = "0A"
+	udpUnconned = "07"
+)
+
+// processInfo is a process read from the proc filesystem.
+type processInfo struct {
+	PID     int
+	Name    string
+	Cmdline string
+	Exe     string
+	Cwd     string
+	Ports   []int
+}
+
+// procFS reads the processes from a proc filesystem mounted at root.
+type procFS struct {
+	root string
+}
+
+// processes returns the user processes, kernel threads have no command line and are skipped.
+// Details that cannot be read, e.g. the executable of a process owned by another user, are left empty.
+func (fs procFS) processes() ([]processInfo, error) {
+	entries, err := ioutil.ReadDir(fs.root)
+	if err != nil {
+		return nil, err
+	}
+
+	ports := fs.listeningPorts()
+
+	processes := make([]processInfo, 0, len(entries))
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+
+		p, ok := fs.process(pid, ports)
+		if !ok {
+			continue
+		}
+		processes = append(processes, p)
+	}
+	return processes, nil
+}
+
+func (fs procFS) process(pid int, ports map[string]int) (processInfo, bool) {
+	dir := filepath.Join(fs.root, strconv.Itoa(pid))
+
+	cmdline, err := ioutil.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil || len(cmdline) == 0 {
+		// process exited or kernel thread
+		return processInfo{}, false
+	}
+
+	p := processInfo{
+		PID:     pid,
+		Cmdline: strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " ")),
+	}
+	if comm, err := ioutil.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		p.Name = strings.TrimSpace(string(comm))
+	}
+	p.Exe, _ = os.Readlink(filepath.Join(dir, "exe"))
+	p.Cwd, _ = os.Readlink(filepath.Join(dir, "cwd"))
+	p.Ports = fs.processPorts(dir, ports)
+	return p, true
+}
+
+// processPorts returns the ports of the listening sockets opened by the process.
+func (fs procFS) processPorts(dir string, ports map[string]int) []int {
+	if len(ports) == 0 {
+		return []int{}
+	}
+
+	fds, err := ioutil.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return []int{}
+	}
+
+	found := make(map[int]struct{})
+	for _, fd := range fds {
+		link, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+		if err != nil || !strings.HasPrefix(link, "socket:[") {
+			continue
+		}
+		inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+		if port, ok := ports[inode]; ok {
+			found[port] = struct{}{}
+		}
+	}
+
+	result := make([]int, 0, len(found))
+	for port := range found {
+		result = append(result, port)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// listeningPorts returns the ports of the listening TCP sockets and the bound UDP sockets by inode.
+func (fs procFS) listeningPorts() map[string]int {
+	ports := make(map[string]int)
+	fs.readSockets(filepath.Join(fs.root, "net", "tcp"), tcpListen, ports)
+	fs.readSockets(filepath.Join(fs.root, "net", "tcp6"), tcpListen, ports)
+	fs.readSockets(filepath.Join(fs.root, "net", "udp"), udpUnconned, ports)
+	fs.readSockets(filepath.Join(fs.root, "net", "udp6"), udpUnconned, ports)
+	return ports
+}
+
+// readSockets parses a /proc/net socket table, e.g.
+//
+//	sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+//	 0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 12345 ...
+func (fs procFS) readSockets(path, state string, ports map[string]int) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	// header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != state {
+			continue
+		}
+
+		idx := strings.LastIndex(fields[1], ":")
+		if idx < 0 {
+			continue
+		}
+		port, err := strconv.ParseInt(fields[1][idx+1:], 16, 32)
+		if err != nil || port == 0 {
+			continue
+		}
+		ports[fields[9]] = int(port)
+	}
+}
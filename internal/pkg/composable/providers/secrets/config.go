@@ -0,0 +1,51 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/application/paths"
+)
+
+// Config for secrets provider
+type Config struct {
+	// Backend is the store secrets are fetched from, one of file, vault or http.
+	Backend string      `config:"backend"`
+	File    FileConfig  `config:"file"`
+	Vault   VaultConfig `config:"vault"`
+	HTTP    HTTPConfig  `config:"http"`
+	// CacheTTL is how long a fetched secret is used before fetching it again, 0 disables caching.
+	CacheTTL time.Duration `config:"cache_ttl" validate:"min=0"`
+}
+
+// FileConfig for the file backend, every file of the directory is a secret named after the file.
+type FileConfig struct {
+	Path string `config:"path"`
+}
+
+// VaultConfig for the vault backend, the vault is a file encrypted with the password read from
+// PasswordFile.
+type VaultConfig struct {
+	Path         string `config:"path"`
+	PasswordFile string `config:"password_file"`
+}
+
+// HTTPConfig for the http backend, a secret is fetched with a GET request on <url>/<name>.
+type HTTPConfig struct {
+	URL     string            `config:"url"`
+	Headers map[string]string `config:"headers"`
+	Timeout time.Duration     `config:"timeout" validate:"positive"`
+}
+
+// InitDefaults initializes the default values for the config.
+func (c *Config) InitDefaults() {
+	c.Backend = fileBackendName
+	c.File.Path = filepath.Join(paths.Config(), "secrets")
+	c.Vault.Path = filepath.Join(paths.Config(), "secrets.vault")
+	c.HTTP.Timeout = 5 * time.Second
+	c.CacheTTL = time.Minute
+}
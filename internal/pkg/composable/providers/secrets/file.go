@@ -0,0 +1,55 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const fileBackendName = "file"
+
+// fileBackend reads every secret from its own file in a directory, the trailing newline of the file
+// is not part of the secret.
+type fileBackend struct {
+	path string
+}
+
+func newFileBackend(_ *logger.Logger, cfg *Config) (Backend, error) {
+	if cfg.File.Path == "" {
+		return nil, fmt.Errorf("file.path is required")
+	}
+	return &fileBackend{path: cfg.File.Path}, nil
+}
+
+func (b *fileBackend) Get(name string) (string, bool, error) {
+	if err := validateName(name); err != nil {
+		return "", false, err
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(b.path, name))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+// validateName ensures the name of a secret cannot be used to read outside of the store, every backend
+// checks the names it is given.
+func validateName(name string) error {
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid secret name '%s'", name)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const httpBackendName = "http"
+
+// httpBackend fetches secrets from a local HTTP secret store, the body of a successful response to
+// GET <url>/<name> is the value of the secret and a 404 response means the secret does not exist.
+type httpBackend struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newHTTPBackend(_ *logger.Logger, cfg *Config) (Backend, error) {
+	if cfg.HTTP.URL == "" {
+		return nil, fmt.Errorf("http.url is required")
+	}
+	u, err := url.Parse(cfg.HTTP.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid http.url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid http.url scheme '%s', http and https are supported", u.Scheme)
+	}
+
+	return &httpBackend{
+		url:     strings.TrimRight(cfg.HTTP.URL, "/"),
+		headers: cfg.HTTP.Headers,
+		client:  &http.Client{Timeout: cfg.HTTP.Timeout},
+	}, nil
+}
+
+func (b *httpBackend) Get(name string) (string, bool, error) {
+	if err := validateName(name); err != nil {
+		return "", false, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, b.url+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return "", false, err
+	}
+	for k, v := range b.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("secret store returned status %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimRight(string(body), "\r\n"), true, nil
+}
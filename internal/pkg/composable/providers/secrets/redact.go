@@ -0,0 +1,93 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"sync"
+)
+
+// RedactedValue replaces the secrets in redacted values.
+const RedactedValue = "<REDACTED>"
+
+var (
+	resolvedMx sync.RWMutex
+	// resolved is the last value fetched for each secret, the set is bounded by the secrets referenced
+	// by the configuration.
+	resolved = map[string]string{}
+)
+
+// addResolved records the value fetched for a secret, the value is then redacted by Redact. The value
+// replaces the previous value of the secret, a rotated value is not redacted anymore.
+func addResolved(name, value string) {
+	resolvedMx.Lock()
+	defer resolvedMx.Unlock()
+	if value == "" {
+		delete(resolved, name)
+		return
+	}
+	resolved[name] = value
+}
+
+// Redact returns a copy of the configuration where the values equal to a secret fetched by the
+// provider in this process are replaced by RedactedValue. Only whole values are compared, a secret is
+// not searched for inside of longer values, keys are kept.
+func Redact(in interface{}) interface{} {
+	values := resolvedValues()
+	if len(values) == 0 {
+		return in
+	}
+	return redact(values, in)
+}
+
+func resolvedValues() map[string]struct{} {
+	resolvedMx.RLock()
+	defer resolvedMx.RUnlock()
+
+	values := make(map[string]struct{}, len(resolved))
+	for _, v := range resolved {
+		values[v] = struct{}{}
+	}
+	return values
+}
+
+func redactString(values map[string]struct{}, s string) string {
+	if _, ok := values[s]; ok {
+		return RedactedValue
+	}
+	return s
+}
+
+func redact(values map[string]struct{}, in interface{}) interface{} {
+	switch v := in.(type) {
+	case string:
+		return redactString(values, v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			out[k] = redact(values, val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = redact(values, val)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]map[string]interface{}, len(v))
+		for i, val := range v {
+			out[i], _ = redact(values, val).(map[string]interface{})
+		}
+		return out
+	case []string:
+		out := make([]string, len(v))
+		for i, val := range v {
+			out[i] = redactString(values, val)
+		}
+		return out
+	default:
+		return in
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	resolvedMx.Lock()
+	resolved = map[string]string{}
+	resolvedMx.Unlock()
+
+	in := map[string]interface{}{
+		"password": "changeme",
+	}
+	assert.Equal(t, in, Redact(in), "nothing to redact before secrets are resolved")
+
+	addResolved("es_password", "changeme")
+	addResolved("api_key", "changeme2")
+	addResolved("ssl_enabled", "on")
+	addResolved("empty", "")
+
+	in = map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"default": map[string]interface{}{
+				"password": "changeme",
+				"api_key":  "changeme2",
+				"hosts":    []interface{}{"https://monitoring:9200"},
+				"changeme": true,
+				"port":     9200,
+			},
+		},
+		"tags": []string{"changeme", "other", ""},
+	}
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"default": map[string]interface{}{
+				"password": RedactedValue,
+				"api_key":  RedactedValue,
+				"hosts":    []interface{}{"https://monitoring:9200"},
+				"changeme": true,
+				"port":     9200,
+			},
+		},
+		"tags": []string{RedactedValue, "other", ""},
+	}
+	assert.Equal(t, expected, Redact(in))
+	assert.Equal(t, "changeme", in["outputs"].(map[string]interface{})["default"].(map[string]interface{})["password"], "input is not modified")
+
+	// the rotated value replaces the previous one
+	addResolved("es_password", "rotated")
+	assert.Equal(t, []string{"changeme", RedactedValue}, Redact([]string{"changeme", "rotated"}))
+}
@@ -0,0 +1,146 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	corecomp "github.com/elastic/elastic-agent/internal/pkg/core/composable"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const providerName = "secrets"
+
+var _ corecomp.FetchContextProvider = (*contextProviderSecrets)(nil)
+
+// timeNow returns the current time, used by testing.
+var timeNow = time.Now
+
+func init() {
+	composable.Providers.AddContextProvider(providerName, ContextProviderBuilder)
+}
+
+// Backend is a store secrets are fetched from.
+type Backend interface {
+	// Get returns the value of the secret, found is false when the store has no secret with this name.
+	Get(name string) (value string, found bool, err error)
+}
+
+// BackendBuilder creates a backend from the provider configuration.
+type BackendBuilder func(log *logger.Logger, cfg *Config) (Backend, error)
+
+// backends are the stores supported by the provider, by name.
+var backends = map[string]BackendBuilder{
+	fileBackendName:  newFileBackend,
+	vaultBackendName: newVaultBackend,
+	httpBackendName:  newHTTPBackend,
+}
+
+type cachedSecret struct {
+	value   string
+	expires time.Time
+}
+
+type contextProviderSecrets struct {
+	logger  *logger.Logger
+	config  *Config
+	backend Backend
+
+	mx    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// ContextProviderBuilder builds the context provider.
+func ContextProviderBuilder(log *logger.Logger, c *config.Config) (corecomp.ContextProvider, error) {
+	var cfg Config
+	if c == nil {
+		c = config.New()
+	}
+	err := c.Unpack(&cfg)
+	if err != nil {
+		return nil, errors.New(err, "failed to unpack configuration")
+	}
+
+	builder, ok := backends[cfg.Backend]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unknown secrets backend '%s'", cfg.Backend), errors.TypeConfig)
+	}
+	backend, err := builder(log, &cfg)
+	if err != nil {
+		return nil, errors.New(err, fmt.Sprintf("failed to create secrets backend '%s'", cfg.Backend), errors.TypeConfig)
+	}
+
+	return &contextProviderSecrets{
+		logger:  log,
+		config:  &cfg,
+		backend: backend,
+		cache:   make(map[string]cachedSecret),
+	}, nil
+}
+
+// Run does nothing, secrets are only fetched when a variable references them.
+func (p *contextProviderSecrets) Run(_ corecomp.ContextProviderComm) error {
+	return nil
+}
+
+// Fetch returns the value of the secret.
+func (p *contextProviderSecrets) Fetch(key string) (string, bool) {
+	// key = "secrets.somesecret"
+	tokens := strings.SplitN(key, ".", 2)
+	if len(tokens) != 2 || tokens[0] != providerName || tokens[1] == "" {
+		p.logger.Debugf("not valid secret key: %v. Secrets should be of the following format %v", key, "secrets.somesecret")
+		return "", false
+	}
+	name := tokens[1]
+
+	if value, ok := p.cached(name); ok {
+		return value, true
+	}
+
+	value, found, err := p.backend.Get(name)
+	if err != nil {
+		p.logger.Errorf("Could not retrieve secret %v from %v backend: %v", name, p.config.Backend, err)
+		return "", false
+	}
+	if !found {
+		p.logger.Debugf("Secret %v not found in %v backend", name, p.config.Backend)
+		return "", false
+	}
+
+	addResolved(name, value)
+	p.store(name, value)
+	return value, true
+}
+
+func (p *contextProviderSecrets) cached(name string) (string, bool) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	s, ok := p.cache[name]
+	if !ok {
+		return "", false
+	}
+	if timeNow().After(s.expires) {
+		delete(p.cache, name)
+		return "", false
+	}
+	return s.value, true
+}
+
+func (p *contextProviderSecrets) store(name, value string) {
+	if p.config.CacheTTL <= 0 {
+		return
+	}
+
+	p.mx.Lock()
+	defer p.mx.Unlock()
+	p.cache[name] = cachedSecret{value: value, expires: timeNow().Add(p.config.CacheTTL)}
+}
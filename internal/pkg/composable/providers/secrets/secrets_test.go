@@ -0,0 +1,223 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	corecomp "github.com/elastic/elastic-agent/internal/pkg/core/composable"
+)
+
+func newFetchProvider(t *testing.T, cfg map[string]interface{}) corecomp.FetchContextProvider {
+	c, err := config.NewConfigFrom(cfg)
+	require.NoError(t, err)
+
+	p, err := ContextProviderBuilder(logp.NewLogger("test_secrets"), c)
+	require.NoError(t, err)
+	require.NoError(t, p.Run(nil))
+
+	return p.(corecomp.FetchContextProvider)
+}
+
+func TestSecretsProvider_File(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "api_key"), []byte("secret_api_key\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "db.password"), []byte("changeme"), 0600))
+
+	fp := newFetchProvider(t, map[string]interface{}{
+		"backend":   "file",
+		"file.path": dir,
+	})
+
+	val, found := fp.Fetch("secrets.api_key")
+	assert.True(t, found)
+	assert.Equal(t, "secret_api_key", val)
+
+	val, found = fp.Fetch("secrets.db.password")
+	assert.True(t, found)
+	assert.Equal(t, "changeme", val)
+
+	_, found = fp.Fetch("secrets.missing")
+	assert.False(t, found)
+
+	_, found = fp.Fetch("secrets...")
+	assert.False(t, found)
+
+	_, found = fp.Fetch("secrets.../api_key")
+	assert.False(t, found)
+
+	_, found = fp.Fetch("env.api_key")
+	assert.False(t, found)
+}
+
+func TestSecretsProvider_Vault(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	vaultFile := filepath.Join(dir, "secrets.vault")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("vault_password\n"), 0600))
+	require.NoError(t, writeVault(vaultFile, []byte("vault_password"), map[string]string{"api_key": "from_vault"}))
+
+	fp := newFetchProvider(t, map[string]interface{}{
+		"backend":             "vault",
+		"vault.path":          vaultFile,
+		"vault.password_file": passwordFile,
+		"cache_ttl":           0,
+	})
+
+	val, found := fp.Fetch("secrets.api_key")
+	assert.True(t, found)
+	assert.Equal(t, "from_vault", val)
+
+	_, found = fp.Fetch("secrets.missing")
+	assert.False(t, found)
+
+	// vault is read again when it changes
+	require.NoError(t, writeVault(vaultFile, []byte("vault_password"), map[string]string{"api_key": "rotated", "other": "value"}))
+	val, found = fp.Fetch("secrets.other")
+	assert.True(t, found)
+	assert.Equal(t, "value", val)
+
+	t.Run("wrong password", func(t *testing.T) {
+		wrongPassword := filepath.Join(dir, "wrong")
+		require.NoError(t, ioutil.WriteFile(wrongPassword, []byte("wrong"), 0600))
+		fp := newFetchProvider(t, map[string]interface{}{
+			"backend":             "vault",
+			"vault.path":          vaultFile,
+			"vault.password_file": wrongPassword,
+		})
+
+		_, found := fp.Fetch("secrets.api_key")
+		assert.False(t, found)
+	})
+}
+
+func TestSetVaultSecrets(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	vaultFile := filepath.Join(dir, "secrets.vault")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("vault_password\n"), 0600))
+
+	require.NoError(t, SetVaultSecrets(vaultFile, passwordFile, map[string]string{"api_key": "from_vault", "other": "value"}))
+	require.NoError(t, SetVaultSecrets(vaultFile, passwordFile, map[string]string{"api_key": "rotated", "other": ""}))
+
+	secrets, err := readVault(vaultFile, []byte("vault_password"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"api_key": "rotated"}, secrets)
+
+	info, err := os.Stat(vaultFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	assert.Error(t, SetVaultSecrets(vaultFile, passwordFile, map[string]string{"../api_key": "value"}))
+	assert.Error(t, SetVaultSecrets(vaultFile, filepath.Join(dir, "missing"), map[string]string{"api_key": "value"}))
+	_, err = readVault(vaultFile, []byte("wrong_password"))
+	assert.Error(t, err)
+}
+
+func TestSecretsProvider_HTTP(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/secrets/api_key":
+			w.Write([]byte("from_http"))
+		case "/secrets/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	fp := newFetchProvider(t, map[string]interface{}{
+		"backend":  "http",
+		"http.url": srv.URL + "/secrets/",
+		"http.headers": map[string]interface{}{
+			"Authorization": "Bearer token",
+		},
+	})
+
+	val, found := fp.Fetch("secrets.api_key")
+	assert.True(t, found)
+	assert.Equal(t, "from_http", val)
+
+	// cached value is used
+	val, found = fp.Fetch("secrets.api_key")
+	assert.True(t, found)
+	assert.Equal(t, "from_http", val)
+	assert.Equal(t, 1, requests)
+
+	_, found = fp.Fetch("secrets.missing")
+	assert.False(t, found)
+
+	_, found = fp.Fetch("secrets.broken")
+	assert.False(t, found)
+
+	// invalid names are rejected before reaching the store
+	requests = 0
+	_, found = fp.Fetch("secrets.../api_key")
+	assert.False(t, found)
+	assert.Equal(t, 0, requests)
+}
+
+func TestSecretsProvider_CacheExpires(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "api_key")
+	require.NoError(t, ioutil.WriteFile(secretFile, []byte("first"), 0600))
+
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	fp := newFetchProvider(t, map[string]interface{}{
+		"file.path": dir,
+		"cache_ttl": "1m",
+	})
+
+	val, _ := fp.Fetch("secrets.api_key")
+	assert.Equal(t, "first", val)
+
+	require.NoError(t, ioutil.WriteFile(secretFile, []byte("second"), 0600))
+	val, _ = fp.Fetch("secrets.api_key")
+	assert.Equal(t, "first", val)
+
+	now = now.Add(2 * time.Minute)
+	val, _ = fp.Fetch("secrets.api_key")
+	assert.Equal(t, "second", val)
+}
+
+func TestSecretsProvider_InvalidConfig(t *testing.T) {
+	testCases := map[string]map[string]interface{}{
+		"unknown backend":        {"backend": "unknown"},
+		"http without url":       {"backend": "http"},
+		"http invalid scheme":    {"backend": "http", "http.url": "ftp://localhost"},
+		"vault without password": {"backend": "vault"},
+	}
+
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c, err := config.NewConfigFrom(cfg)
+			require.NoError(t, err)
+
+			_, err = ContextProviderBuilder(logp.NewLogger("test_secrets"), c)
+			assert.Error(t, err)
+		})
+	}
+}
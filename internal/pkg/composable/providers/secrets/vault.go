@@ -0,0 +1,187 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic/elastic-agent/internal/pkg/crypto"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+const vaultBackendName = "vault"
+
+// vaultBackend reads secrets from a YAML map of names to values encrypted with internal/pkg/crypto,
+// the vault is decrypted again only when the file changes.
+type vaultBackend struct {
+	path     string
+	password []byte
+
+	mx      sync.Mutex
+	modTime time.Time
+	size    int64
+	secrets map[string]string
+}
+
+func newVaultBackend(_ *logger.Logger, cfg *Config) (Backend, error) {
+	if cfg.Vault.Path == "" {
+		return nil, fmt.Errorf("vault.path is required")
+	}
+	if cfg.Vault.PasswordFile == "" {
+		return nil, fmt.Errorf("vault.password_file is required")
+	}
+
+	password, err := readVaultPassword(cfg.Vault.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vaultBackend{path: cfg.Vault.Path, password: password}, nil
+}
+
+// SetVaultSecrets sets the secrets in the vault encrypted with the password read from passwordFile, a
+// secret with an empty value is removed. The vault is created when it does not exist.
+func SetVaultSecrets(path, passwordFile string, secrets map[string]string) error {
+	password, err := readVaultPassword(passwordFile)
+	if err != nil {
+		return err
+	}
+
+	current, err := readVault(path, password)
+	if os.IsNotExist(err) {
+		current = make(map[string]string)
+	} else if err != nil {
+		return err
+	}
+
+	for name, value := range secrets {
+		if err := validateName(name); err != nil {
+			return err
+		}
+		if value == "" {
+			delete(current, name)
+			continue
+		}
+		current[name] = value
+	}
+	return writeVault(path, password, current)
+}
+
+func readVaultPassword(path string) ([]byte, error) {
+	password, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read vault password: %w", err)
+	}
+	password = bytes.TrimRight(password, "\r\n")
+	if len(password) == 0 {
+		return nil, fmt.Errorf("vault password file %s is empty", path)
+	}
+	return password, nil
+}
+
+func (b *vaultBackend) Get(name string) (string, bool, error) {
+	if err := validateName(name); err != nil {
+		return "", false, err
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if err := b.load(); err != nil {
+		return "", false, err
+	}
+
+	value, ok := b.secrets[name]
+	return value, ok, nil
+}
+
+func (b *vaultBackend) load() error {
+	info, err := os.Stat(b.path)
+	if os.IsNotExist(err) {
+		b.secrets = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if b.secrets != nil && info.ModTime().Equal(b.modTime) && info.Size() == b.size {
+		return nil
+	}
+
+	secrets, err := readVault(b.path, b.password)
+	if err != nil {
+		return err
+	}
+
+	b.secrets = secrets
+	b.modTime = info.ModTime()
+	b.size = info.Size()
+	return nil
+}
+
+func readVault(path string, password []byte) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := crypto.NewReaderWithDefaults(f, password)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt vault %s: %w", path, err)
+	}
+
+	secrets := make(map[string]string)
+	if err := yaml.Unmarshal(content, &secrets); err != nil {
+		return nil, fmt.Errorf("could not parse vault %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// writeVault replaces the vault with the encrypted secrets, the new vault is renamed over the previous
+// one so the vault backend never reads a partially written vault.
+func writeVault(path string, password []byte, secrets map[string]string) error {
+	content, err := yaml.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := crypto.NewWriterWithDefaults(&buf, password)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("could not encrypt vault %s: %w", path, err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,20 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package systemd
+
+import "time"
+
+// Config for systemd provider
+type Config struct {
+	// Patterns of the unit names listed, e.g. `nginx.service`. No unit is listed without patterns.
+	Patterns []string `config:"patterns"`
+	// Period is the interval units are listed again to discover started and stopped units.
+	Period time.Duration `config:"period" validate:"positive,nonzero"`
+}
+
+// InitDefaults initializes the default values for the config.
+func (c *Config) InitDefaults() {
+	c.Period = 10 * time.Second
+}
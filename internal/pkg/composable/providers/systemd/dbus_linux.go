@@ -0,0 +1,73 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build linux
+// +build linux
+
+package systemd
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+)
+
+// runningStates are the active states of the units added to the provider.
+var runningStates = []string{"active", "reloading"}
+
+type dbusLister struct {
+	conn *dbus.Conn
+}
+
+func newDBusLister(ctx context.Context) (unitLister, error) {
+	conn, err := dbus.NewWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dbusLister{conn: conn}, nil
+}
+
+func (l *dbusLister) ListUnits(ctx context.Context, patterns []string) ([]unit, error) {
+	statuses, err := l.conn.ListUnitsByPatternsContext(ctx, runningStates, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	units := make([]unit, 0, len(statuses))
+	for _, s := range statuses {
+		u := unit{
+			Name:     s.Name,
+			State:    s.ActiveState,
+			SubState: s.SubState,
+		}
+
+		props, err := l.conn.GetUnitTypePropertiesContext(ctx, s.Name, unitType(s.Name))
+		if err != nil {
+			// unit stopped since it was listed
+			continue
+		}
+		u.PID, _ = props["MainPID"].(uint32)
+		u.CGroup, _ = props["ControlGroup"].(string)
+		u.ExecStart = execStart(props["ExecStart"])
+
+		units = append(units, u)
+	}
+	return units, nil
+}
+
+func (l *dbusLister) Close() {
+	l.conn.Close()
+}
+
+// unitType returns the D-Bus interface holding the properties specific to the type of the unit, e.g.
+// nginx.service has the properties of the Service interface. Unit suffixes are lower case ASCII.
+func unitType(name string) string {
+	suffix := strings.TrimPrefix(filepath.Ext(name), ".")
+	if suffix == "" {
+		return ""
+	}
+	return strings.ToUpper(suffix[:1]) + suffix[1:]
+}
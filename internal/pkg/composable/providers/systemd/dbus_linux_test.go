@@ -0,0 +1,21 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build linux
+// +build linux
+
+package systemd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitType(t *testing.T) {
+	assert.Equal(t, "Service", unitType("nginx.service"))
+	assert.Equal(t, "Socket", unitType("docker.socket"))
+	assert.Equal(t, "Service", unitType("getty@tty1.service"))
+	assert.Equal(t, "", unitType("nginx"))
+}
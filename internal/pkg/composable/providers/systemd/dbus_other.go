@@ -0,0 +1,17 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !linux
+// +build !linux
+
+package systemd
+
+import (
+	"context"
+	"errors"
+)
+
+func newDBusLister(_ context.Context) (unitLister, error) {
+	return nil, errors.New("systemd is only supported on Linux")
+}
@@ -0,0 +1,170 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package systemd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+// UnitPriority is the priority that unit mappings are added to the provider.
+const UnitPriority = 0
+
+func init() {
+	composable.Providers.AddDynamicProvider("systemd", DynamicProviderBuilder)
+}
+
+// unit is an active systemd unit.
+type unit struct {
+	Name      string
+	State     string
+	SubState  string
+	PID       uint32
+	ExecStart string
+	CGroup    string
+}
+
+// unitLister lists the active and reloading units matching the patterns, D-Bus is used on Linux.
+type unitLister interface {
+	ListUnits(ctx context.Context, patterns []string) ([]unit, error)
+	Close()
+}
+
+// newLister creates the unitLister, replaced by testing.
+var newLister = newDBusLister
+
+type dynamicProvider struct {
+	logger *logger.Logger
+	config *Config
+}
+
+// DynamicProviderBuilder builds the dynamic provider.
+func DynamicProviderBuilder(logger *logger.Logger, c *config.Config) (composable.DynamicProvider, error) {
+	var cfg Config
+	if c == nil {
+		c = config.New()
+	}
+	err := c.Unpack(&cfg)
+	if err != nil {
+		return nil, errors.New(err, "failed to unpack configuration")
+	}
+	return &dynamicProvider{logger, &cfg}, nil
+}
+
+// Run runs the systemd dynamic provider.
+func (p *dynamicProvider) Run(comm composable.DynamicProviderComm) error {
+	if len(p.config.Patterns) == 0 {
+		// every input using unit variables is rendered once per unit, the units must be selected
+		p.logger.Infof("systemd provider skipped, no unit patterns are configured")
+		return nil
+	}
+	lister, err := newLister(comm)
+	if err != nil {
+		// info only; return nil (do nothing)
+		p.logger.Infof("systemd provider skipped, unable to connect: %s", err)
+		return nil
+	}
+
+	units := make(map[string]struct{})
+	update := func() {
+		if err := p.update(comm, lister, units); err != nil {
+			// units of the last successful update are kept
+			p.logger.Errorf("%s", err)
+			composable.ReportDegraded(comm, err.Error())
+			return
+		}
+		composable.ReportHealthy(comm)
+	}
+	update()
+
+	go func() {
+		defer lister.Close()
+		for {
+			t := time.NewTimer(p.config.Period)
+			select {
+			case <-comm.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+
+			update()
+		}
+	}()
+	return nil
+}
+
+// update adds or updates the active units and removes the units that stopped since last update.
+func (p *dynamicProvider) update(comm composable.DynamicProviderComm, lister unitLister, units map[string]struct{}) error {
+	listed, err := lister.ListUnits(comm, p.config.Patterns)
+	if err != nil {
+		return errors.New(err, "failed to list systemd units", errors.TypeUnexpected)
+	}
+
+	active := make(map[string]struct{}, len(listed))
+	for _, u := range listed {
+		active[u.Name] = struct{}{}
+		if err := comm.AddOrUpdate(u.Name, UnitPriority, generateMapping(u), generateProcessors(u)); err != nil {
+			p.logger.Errorf("failed to add mapping for unit %s: %s", u.Name, err)
+			continue
+		}
+		units[u.Name] = struct{}{}
+	}
+
+	for name := range units {
+		if _, ok := active[name]; !ok {
+			comm.Remove(name)
+			delete(units, name)
+		}
+	}
+	return nil
+}
+
+func generateMapping(u unit) map[string]interface{} {
+	return map[string]interface{}{
+		"unit": map[string]interface{}{
+			"name":       u.Name,
+			"state":      u.State,
+			"sub_state":  u.SubState,
+			"pid":        u.PID,
+			"exec_start": u.ExecStart,
+			"cgroup":     u.CGroup,
+		},
+	}
+}
+
+func generateProcessors(u unit) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"add_fields": map[string]interface{}{
+				"fields": map[string]interface{}{
+					"unit": u.Name,
+				},
+				"to": "systemd",
+			},
+		},
+	}
+}
+
+// execStart returns the command line of the first ExecStart entry of a service, the D-Bus property
+// is a list of (path, argv, ignore_failure, ...) structures.
+func execStart(v interface{}) string {
+	entries, ok := v.([][]interface{})
+	if !ok || len(entries) == 0 || len(entries[0]) < 2 {
+		return ""
+	}
+	argv, ok := entries[0][1].([]string)
+	if !ok || len(argv) == 0 {
+		path, _ := entries[0][0].(string)
+		return path
+	}
+	return strings.Join(argv, " ")
+}
@@ -0,0 +1,170 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package systemd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/logp"
+	"github.com/elastic/elastic-agent/internal/pkg/composable"
+	ctesting "github.com/elastic/elastic-agent/internal/pkg/composable/testing"
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+)
+
+type fakeLister struct {
+	mx       sync.Mutex
+	units    []unit
+	err      error
+	patterns []string
+	closed   bool
+}
+
+func (f *fakeLister) ListUnits(_ context.Context, patterns []string) ([]unit, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.patterns = patterns
+	return f.units, f.err
+}
+
+func (f *fakeLister) Close() {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.closed = true
+}
+
+func (f *fakeLister) set(units []unit, err error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+	f.units = units
+	f.err = err
+}
+
+var nginx = unit{
+	Name:      "nginx.service",
+	State:     "active",
+	SubState:  "running",
+	PID:       1234,
+	ExecStart: "/usr/sbin/nginx -g daemon on;",
+	CGroup:    "/system.slice/nginx.service",
+}
+
+var redis = unit{
+	Name:      "redis.service",
+	State:     "active",
+	SubState:  "running",
+	PID:       4321,
+	ExecStart: "/usr/bin/redis-server /etc/redis/redis.conf",
+	CGroup:    "/system.slice/redis.service",
+}
+
+func TestDynamicProvider(t *testing.T) {
+	lister := &fakeLister{units: []unit{nginx, redis}}
+	newLister = func(context.Context) (unitLister, error) { return lister, nil }
+	defer func() { newLister = newDBusLister }()
+
+	cfg, err := config.NewConfigFrom(map[string]interface{}{
+		"patterns": []string{"nginx.service", "redis.*"},
+		"period":   "10ms",
+	})
+	require.NoError(t, err)
+	builder, _ := composable.Providers.GetDynamicProvider("systemd")
+	provider, err := builder(logp.NewLogger("test_systemd"), cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	comm := ctesting.NewDynamicComm(ctx)
+	require.NoError(t, provider.Run(comm))
+
+	assert.ElementsMatch(t, []string{"nginx.service", "redis.service"}, comm.CurrentIDs())
+	curr, ok := comm.Current("nginx.service")
+	require.True(t, ok)
+	assert.Equal(t, UnitPriority, curr.Priority)
+	assert.Equal(t, map[string]interface{}{
+		"unit": map[string]interface{}{
+			"name":       "nginx.service",
+			"state":      "active",
+			"sub_state":  "running",
+			"pid":        float64(1234),
+			"exec_start": "/usr/sbin/nginx -g daemon on;",
+			"cgroup":     "/system.slice/nginx.service",
+		},
+	}, curr.Mapping)
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"add_fields": map[string]interface{}{
+				"fields": map[string]interface{}{
+					"unit": "nginx.service",
+				},
+				"to": "systemd",
+			},
+		},
+	}, curr.Processors)
+
+	// listing errors keep the units
+	lister.set(nil, errors.New("connection lost"))
+	time.Sleep(50 * time.Millisecond)
+	assert.ElementsMatch(t, []string{"nginx.service", "redis.service"}, comm.CurrentIDs())
+
+	// stopped unit is removed
+	lister.set([]unit{nginx}, nil)
+	assert.Eventually(t, func() bool {
+		return comm.Deleted("redis.service")
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"nginx.service"}, comm.CurrentIDs())
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		lister.mx.Lock()
+		defer lister.mx.Unlock()
+		return lister.closed
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"nginx.service", "redis.*"}, lister.patterns)
+}
+
+func TestDynamicProviderNoPatterns(t *testing.T) {
+	lister := &fakeLister{units: []unit{nginx, redis}}
+	newLister = func(context.Context) (unitLister, error) { return lister, nil }
+	defer func() { newLister = newDBusLister }()
+
+	provider, err := DynamicProviderBuilder(logp.NewLogger("test_systemd"), nil)
+	require.NoError(t, err)
+
+	comm := ctesting.NewDynamicComm(context.Background())
+	require.NoError(t, provider.Run(comm))
+	assert.Empty(t, comm.CurrentIDs())
+	assert.Nil(t, lister.patterns)
+}
+
+func TestDynamicProviderUnavailable(t *testing.T) {
+	newLister = func(context.Context) (unitLister, error) { return nil, errors.New("no bus") }
+	defer func() { newLister = newDBusLister }()
+
+	cfg, err := config.NewConfigFrom(map[string]interface{}{"patterns": []string{"*.service"}})
+	require.NoError(t, err)
+	provider, err := DynamicProviderBuilder(logp.NewLogger("test_systemd"), cfg)
+	require.NoError(t, err)
+
+	comm := ctesting.NewDynamicComm(context.Background())
+	require.NoError(t, provider.Run(comm))
+	assert.Empty(t, comm.CurrentIDs())
+}
+
+func TestExecStart(t *testing.T) {
+	assert.Equal(t, "", execStart(nil))
+	assert.Equal(t, "", execStart([][]interface{}{}))
+	assert.Equal(t, "/usr/sbin/nginx -g daemon on;", execStart([][]interface{}{
+		{"/usr/sbin/nginx", []string{"/usr/sbin/nginx", "-g", "daemon on;"}, false},
+	}))
+	assert.Equal(t, "/usr/sbin/nginx", execStart([][]interface{}{
+		{"/usr/sbin/nginx", []string{}, false},
+	}))
+}
@@ -0,0 +1,100 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package composable
+
+import (
+	"sort"
+
+	"github.com/elastic/beats/v7/libbeat/common"
+
+	"github.com/elastic/elastic-agent/internal/pkg/agent/transpiler"
+)
+
+// Snapshot is the current output of the providers of a controller.
+type Snapshot struct {
+	// Context is the current mapping of each context provider.
+	Context map[string]map[string]interface{}
+	// Dynamic is the current mappings of each dynamic provider, sorted by (priority, id).
+	Dynamic map[string][]DynamicMapping
+}
+
+// DynamicMapping is a mapping added by a dynamic provider.
+type DynamicMapping struct {
+	ID         string
+	Priority   int
+	Mapping    map[string]interface{}
+	Processors []map[string]interface{}
+}
+
+// VarsSource identifies the provider mapping vars are built from, provider and id are empty for the
+// vars built only from the context providers.
+type VarsSource struct {
+	Provider string
+	ID       string
+}
+
+// Snapshot returns the current output of the providers.
+func (c *controller) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Context: make(map[string]map[string]interface{}, len(c.contextProviders)),
+		Dynamic: make(map[string][]DynamicMapping, len(c.dynamicProviders)),
+	}
+	for name, state := range c.contextProviders {
+		snapshot.Context[name] = state.Current()
+	}
+	for name, state := range c.dynamicProviders {
+		mappings := state.Mappings()
+		dynamic := make([]DynamicMapping, 0, len(mappings))
+		for _, m := range mappings {
+			dynamic = append(dynamic, DynamicMapping{
+				ID:         m.id,
+				Priority:   m.priority,
+				Mapping:    m.mapping,
+				Processors: m.processors,
+			})
+		}
+		snapshot.Dynamic[name] = dynamic
+	}
+	return snapshot
+}
+
+// Vars returns the vars built from the snapshot, in the same order they are given to the callback of
+// the controller, with the source of each of them.
+//
+// Values of the fetch context providers are not available, they are only fetched when the
+// configuration is rendered.
+func (s Snapshot) Vars() ([]*transpiler.Vars, []VarsSource) {
+	return s.vars(nil)
+}
+
+func (s Snapshot) vars(fetchContextProviders common.MapStr) ([]*transpiler.Vars, []VarsSource) {
+	mapping := make(map[string]interface{}, len(s.Context))
+	for name, current := range s.Context {
+		mapping[name] = current
+	}
+
+	vars := make([]*transpiler.Vars, 1)
+	sources := []VarsSource{{}}
+	// this is ensured not to error, by how the mappings states are verified
+	vars[0], _ = transpiler.NewVars(mapping, fetchContextProviders)
+
+	// add to the vars list for each dynamic providers mappings
+	names := make([]string, 0, len(s.Dynamic))
+	for name := range s.Dynamic {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, m := range s.Dynamic[name] {
+			local, _ := cloneMap(mapping) // will not fail; already been successfully cloned once
+			local[name] = m.Mapping
+			// this is ensured not to error, by how the mappings states are verified
+			v, _ := transpiler.NewVarsWithProcessors(local, name, m.Processors, fetchContextProviders)
+			vars = append(vars, v)
+			sources = append(sources, VarsSource{Provider: name, ID: m.ID})
+		}
+	}
+	return vars, sources
+}
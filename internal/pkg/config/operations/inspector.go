@@ -56,6 +56,37 @@ func LoadFullAgentConfig(cfgPath string, failOnFleetMissing bool) (*config.Confi
 	return config.NewConfigFrom(fleetConfig)
 }
 
+// LoadCandidateAgentConfig loads a policy that is not applied yet the same way LoadFullAgentConfig
+// loads the current config, so both can be compared. The policy replaces the config file of a
+// standalone agent or the policy received from fleet by a managed agent.
+func LoadCandidateAgentConfig(cfgPath, policyPath string) (*config.Config, error) {
+	rawConfig, err := loadConfig(cfgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := configuration.NewFromConfig(rawConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if configuration.IsStandalone(cfg.Fleet) {
+		return loadConfig(policyPath)
+	}
+
+	policy, err := config.LoadFile(policyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// policies from fleet are received as maps, the policy is converted to match them.
+	policyMap, err := policy.ToMapStr()
+	if err != nil {
+		return nil, err
+	}
+	return config.NewConfigFrom(policyMap)
+}
+
 func loadConfig(configPath string) (*config.Config, error) {
 	rawConfig, err := config.LoadFile(configPath)
 	if err != nil {
@@ -189,7 +189,8 @@ func parseID(id string) (programDetail, error) {
 		return detail, errorfWithStatus(http.StatusBadRequest, "provided ID is not valid")
 	}
 
-	for p, spec := range program.SupportedMap {
+	for _, spec := range program.Specs() {
+		p := strings.ToLower(spec.Cmd)
 		if !strings.HasPrefix(id, p+separator) {
 			continue
 		}
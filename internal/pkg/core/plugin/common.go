@@ -5,18 +5,35 @@
 package plugin
 
 import (
+	"fmt"
+
 	"gopkg.in/yaml.v2"
 
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
 	"github.com/elastic/elastic-agent/internal/pkg/config"
+	"github.com/elastic/elastic-agent/internal/pkg/core/process"
 	"github.com/elastic/elastic-agent/pkg/core/logger"
+	"github.com/elastic/elastic-agent/pkg/core/server"
 )
 
 type configFetcher interface {
 	Config() string
 }
 
+// StopApplication signals the application to stop through GRPC and waits for it to disconnect, an
+// application reporting that it drains its queued events is given the drain timeout to complete.
+func StopApplication(srvState *server.ApplicationState, cfg *process.Config) error {
+	if err := srvState.StopAndDrain(cfg.StopTimeout, cfg.DrainTimeout); err != nil {
+		timeout := cfg.StopTimeout
+		if errors.Is(err, server.ErrApplicationDrainTimedOut) {
+			timeout = cfg.DrainTimeout
+		}
+		return fmt.Errorf("failed to stop after %s: %w", timeout, err)
+	}
+	return nil
+}
+
 // IsRestartNeeded returns true if
 // - spec is configured to support restart on change
 // - output changes in between configs
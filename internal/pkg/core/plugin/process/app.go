@@ -18,7 +18,9 @@ import (
 	"github.com/elastic/elastic-agent/internal/pkg/agent/errors"
 	"github.com/elastic/elastic-agent/internal/pkg/agent/program"
 	"github.com/elastic/elastic-agent/internal/pkg/core/app"
+	"github.com/elastic/elastic-agent/internal/pkg/core/backoff"
 	"github.com/elastic/elastic-agent/internal/pkg/core/monitoring"
+	"github.com/elastic/elastic-agent/internal/pkg/core/plugin"
 	"github.com/elastic/elastic-agent/internal/pkg/core/process"
 	"github.com/elastic/elastic-agent/internal/pkg/core/state"
 	"github.com/elastic/elastic-agent/internal/pkg/core/status"
@@ -58,6 +60,22 @@ type Application struct {
 
 	processConfig *process.Config
 
+	// limits of the program, applied when cgroups are enabled
+	limits    process.ResourceLimits
+	cgroup    *process.Cgroup
+	cgroupErr error
+	oomKills  uint64
+
+	restartPolicy process.RestartPolicy
+	backoff       backoff.Backoff
+	backoffDone   chan struct{}
+	restarts      int
+	restartTimes  []time.Time
+	startedAt     time.Time
+	crashLooping  bool
+	// exited is true when the program exited and its restart policy does not restart it
+	exited bool
+
 	logger *logger.Logger
 
 	appLock          sync.Mutex
@@ -87,6 +105,16 @@ func NewApplication(
 		return nil, err
 	}
 
+	restartPolicy := desc.Spec().RestartPolicy
+	if err := restartPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
+	var limits process.ResourceLimits
+	if cfg.ProcessConfig != nil {
+		limits = cfg.ProcessConfig.Cgroups.LimitsFor(desc.Spec().Cmd, desc.Spec().Resources)
+	}
+
 	b, _ := tokenbucket.NewTokenBucket(ctx, 3, 3, 1*time.Second)
 	return &Application{
 		bgContext:     ctx,
@@ -97,6 +125,8 @@ func NewApplication(
 		desc:          desc,
 		srv:           srv,
 		processConfig: cfg.ProcessConfig,
+		limits:        limits,
+		restartPolicy: restartPolicy,
 		logger:        logger,
 		limiter:       b,
 		state: state.State{
@@ -143,16 +173,19 @@ func (a *Application) Stop() {
 	a.appLock.Lock()
 	status := a.state.Status
 	srvState := a.srvState
+	exited := a.exited
 	a.appLock.Unlock()
 
-	if status == state.Stopped {
+	if status == state.Stopped && !exited {
 		return
 	}
 
-	if srvState != nil {
+	if srvState != nil && exited {
+		// the program is not running anymore, there is nothing to stop gracefully
+		srvState.Destroy()
+	} else if srvState != nil {
 		// signal stop through GRPC, wait and kill is performed later in gracefulKill
-		if err := srvState.Stop(a.processConfig.StopTimeout); err != nil {
-			err := fmt.Errorf("failed to stop after %s: %w", a.processConfig.StopTimeout, err)
+		if err := plugin.StopApplication(srvState, a.processConfig); err != nil {
 			a.setState(state.Failed, err.Error(), nil)
 
 			a.logger.Error(err)
@@ -174,6 +207,9 @@ func (a *Application) Stop() {
 		// cleanup drops
 		a.cleanUp()
 	}
+	a.cancelRestart()
+	a.removeCgroup()
+	a.exited = false
 	a.setState(state.Stopped, "Stopped", nil)
 }
 
@@ -227,11 +263,24 @@ func (a *Application) watch(ctx context.Context, p app.Taggable, proc *process.I
 			return
 		}
 
-		msg := fmt.Sprintf("exited with code: %d", procState.ExitCode())
-		a.setState(state.Restarting, msg, nil)
+		exitCode := procState.ExitCode()
+		msg := fmt.Sprintf("exited with code: %d", exitCode)
+		if a.oomKilled(proc) {
+			msg = fmt.Sprintf("%s, killed by the OOM killer with a memory limit of %s", msg, a.limits.Memory)
+		}
+
+		if !a.restartPolicy.RestartOnExit(exitCode) {
+			a.exited = true
+			if exitCode == 0 {
+				a.setState(state.Stopped, msg, nil)
+			} else {
+				a.setState(state.Crashed, msg, nil)
+			}
+			return
+		}
 
 		// it was a crash
-		a.start(ctx, p, cfg, true)
+		a.requestRestart(p, cfg, msg)
 	}()
 }
 
@@ -261,6 +310,7 @@ func (a *Application) waitProc(proc *os.Process) <-chan *os.ProcessState {
 }
 
 func (a *Application) setState(s state.Status, msg string, payload map[string]interface{}) {
+	payload = a.statePayload(payload)
 	if a.state.Status != s || a.state.Message != msg || !reflect.DeepEqual(a.state.Payload, payload) {
 		if state.IsStateFiltered(msg, payload) {
 			return
@@ -276,6 +326,26 @@ func (a *Application) setState(s state.Status, msg string, payload map[string]in
 	}
 }
 
+// statePayload adds the restarts and the resources of the application to the payload it reports.
+func (a *Application) statePayload(payload map[string]interface{}) map[string]interface{} {
+	resources := a.resourcesPayload()
+	if resources == nil && a.restarts == 0 {
+		return payload
+	}
+
+	extended := make(map[string]interface{}, len(payload)+2)
+	for k, v := range payload {
+		extended[k] = v
+	}
+	if resources != nil {
+		extended["resources"] = resources
+	}
+	if a.restarts > 0 {
+		extended["restarts"] = a.restarts
+	}
+	return extended
+}
+
 func (a *Application) cleanUp() {
 	a.monitor.Cleanup(a.desc.Spec(), a.pipelineID)
 }
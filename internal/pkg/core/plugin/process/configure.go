@@ -27,7 +27,7 @@ func (a *Application) Configure(ctx context.Context, config map[string]interface
 	a.appLock.Lock()
 	defer a.appLock.Unlock()
 
-	if a.state.Status == state.Stopped {
+	if a.state.Status == state.Stopped && !a.exited {
 		return errors.New(ErrAppNotRunning)
 	}
 	if a.srvState == nil {
@@ -46,7 +46,15 @@ func (a *Application) Configure(ctx context.Context, config map[string]interface
 		return errors.New(err, errors.TypeApplication)
 	}
 
-	if isRestartNeeded {
+	// the program exited and was not restarted by its restart policy, the new configuration is
+	// applied by starting it again
+	if a.exited {
+		a.logger.Infof("starting '%s' exited earlier due to config change", a.Name())
+		return a.start(ctx, a.desc, config, false)
+	}
+
+	// a new configuration can fix an application that stopped restarting
+	if isRestartNeeded || a.crashLooping {
 		a.logger.Infof("initiating restart of '%s' due to config change", a.Name())
 		a.appLock.Unlock()
 		a.Stop()
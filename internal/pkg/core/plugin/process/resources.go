@@ -0,0 +1,94 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"github.com/elastic/elastic-agent/internal/pkg/core/process"
+)
+
+// setupCgroup creates the cgroup of the application when cgroups are enabled. The application
+// is started without limits when the cgroup cannot be created, the error is reported in the
+// payload of its state.
+func (a *Application) setupCgroup() *process.Cgroup {
+	if a.processConfig == nil || a.processConfig.Cgroups == nil || !a.processConfig.Cgroups.Enabled {
+		return nil
+	}
+	if a.cgroup == nil {
+		a.cgroup, a.cgroupErr = process.NewCgroup(a.id, a.limits)
+		if a.cgroupErr != nil {
+			a.logger.Errorf("%q failed to create cgroup, starting without resource limits: %v", a.Name(), a.cgroupErr)
+			return nil
+		}
+	}
+
+	// counter of the cgroup survives restarts, only new kills are reported
+	if kills, err := a.cgroup.OOMKills(); err == nil {
+		a.oomKills = kills
+	}
+	return a.cgroup
+}
+
+// oomKilled returns true when a process of the cgroup was killed by the OOM killer since the
+// application was started.
+func (a *Application) oomKilled(proc *process.Info) bool {
+	if proc.Cgroup == nil {
+		return false
+	}
+	kills, err := proc.Cgroup.OOMKills()
+	if err != nil || kills <= a.oomKills {
+		return false
+	}
+	a.oomKills = kills
+	return true
+}
+
+func (a *Application) removeCgroup() {
+	a.cgroupErr = nil
+	if a.cgroup == nil {
+		return
+	}
+	if err := a.cgroup.Remove(); err != nil {
+		a.logger.Errorf("%q failed to remove cgroup: %v", a.Name(), err)
+	}
+	a.cgroup = nil
+}
+
+// resourcesPayload returns the cgroup of the application reported in the payload of its state.
+func (a *Application) resourcesPayload() map[string]interface{} {
+	switch {
+	case a.cgroup != nil:
+		resources := map[string]interface{}{
+			"cgroup":    a.cgroup.Path(),
+			"oom_kills": a.oomKills,
+		}
+		if limits := limitsPayload(a.cgroup.Limits()); len(limits) > 0 {
+			resources["limits"] = limits
+		}
+		return resources
+	case a.cgroupErr != nil:
+		return map[string]interface{}{
+			"error": a.cgroupErr.Error(),
+		}
+	default:
+		return nil
+	}
+}
+
+func limitsPayload(limits process.ResourceLimits) map[string]interface{} {
+	payload := make(map[string]interface{})
+	if limits.CPU > 0 {
+		payload["cpu"] = limits.CPU
+	}
+	if limits.Memory > 0 {
+		payload["memory"] = limits.Memory.String()
+	}
+	if limits.Pids > 0 {
+		payload["pids"] = limits.Pids
+	}
+	if limits.IOWeight > 0 {
+		payload["io_weight"] = limits.IOWeight
+	}
+	return payload
+}
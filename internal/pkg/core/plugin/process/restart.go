@@ -0,0 +1,119 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/app"
+	"github.com/elastic/elastic-agent/internal/pkg/core/backoff"
+	"github.com/elastic/elastic-agent/internal/pkg/core/process"
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+)
+
+// restartSettings returns the restart configuration of the agent.
+func (a *Application) restartSettings() *process.RestartConfig {
+	if a.processConfig == nil || a.processConfig.Restart == nil {
+		return process.DefaultRestartConfig()
+	}
+	return a.processConfig.Restart
+}
+
+// resetRestarts forgets the previous restarts when the application is started by the operator, a
+// pending restart is cancelled.
+//
+// This does not grab the appLock, that must be managed by the caller.
+func (a *Application) resetRestarts() {
+	a.cancelRestart()
+
+	cfg := a.restartSettings()
+	a.backoffDone = make(chan struct{})
+	a.backoff = backoff.NewExpBackoff(a.backoffDone, cfg.Backoff.Init, cfg.Backoff.Max)
+	a.restarts = 0
+	a.restartTimes = nil
+	a.crashLooping = false
+	a.exited = false
+}
+
+// cancelRestart cancels the pending restart.
+//
+// This does not grab the appLock, that must be managed by the caller.
+func (a *Application) cancelRestart() {
+	if a.backoffDone != nil {
+		close(a.backoffDone)
+		a.backoffDone = nil
+		a.backoff = nil
+	}
+}
+
+// recordRestart records a restart of the application, it returns an error when the application
+// restarted too often and must not be restarted anymore.
+//
+// This does not grab the appLock, that must be managed by the caller.
+func (a *Application) recordRestart(now time.Time) error {
+	cfg := a.restartSettings()
+	crashLoop := cfg.CrashLoop
+
+	if a.backoff != nil && now.Sub(a.startedAt) > cfg.Backoff.Max {
+		// ran for longer than the maximum backoff, it is not crashing in a loop
+		a.backoff.Reset()
+	}
+
+	recent := a.restartTimes[:0]
+	for _, t := range a.restartTimes {
+		if now.Sub(t) < crashLoop.Window {
+			recent = append(recent, t)
+		}
+	}
+	a.restartTimes = recent
+
+	if crashLoop.Threshold > 0 && len(recent) >= crashLoop.Threshold {
+		a.crashLooping = true
+		return fmt.Errorf("crash looping, restarted %d times within %s", len(recent), crashLoop.Window)
+	}
+	a.restartTimes = append(a.restartTimes, now)
+	a.restarts++
+	return nil
+}
+
+// requestRestart records the restart of the application and schedules it once the backoff expires,
+// the application is marked as failed instead when it is crash looping. The restarts after the
+// program exits and after it reports itself as failed both go through it.
+//
+// This does not grab the appLock, that must be managed by the caller.
+func (a *Application) requestRestart(t app.Taggable, cfg map[string]interface{}, msg string) {
+	if err := a.recordRestart(time.Now()); err != nil {
+		a.setState(state.Failed, fmt.Sprintf("%s, %s", msg, err), nil)
+		return
+	}
+	a.setState(state.Restarting, msg, nil)
+	a.scheduleRestart(t, cfg)
+}
+
+// scheduleRestart restarts the application once the backoff expires, the restart is dropped when the
+// application is stopped or started again in the meantime.
+//
+// This does not grab the appLock, that must be managed by the caller.
+func (a *Application) scheduleRestart(t app.Taggable, cfg map[string]interface{}) {
+	b := a.backoff
+	if b == nil {
+		return
+	}
+	go func() {
+		if !b.Wait() {
+			return
+		}
+
+		a.appLock.Lock()
+		defer a.appLock.Unlock()
+		if a.backoff != b || a.state.Status != state.Restarting || a.state.ProcessInfo != nil {
+			return
+		}
+		if err := a.start(a.startContext, t, cfg, true); err != nil {
+			a.setState(state.Crashed, fmt.Sprintf("failed to restart: %s", err), nil)
+		}
+	}()
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/core/process"
+	"github.com/elastic/elastic-agent/internal/pkg/core/state"
+	"github.com/elastic/elastic-agent/internal/pkg/core/status"
+	"github.com/elastic/elastic-agent/pkg/core/logger"
+)
+
+func TestRecordRestart(t *testing.T) {
+	cfg := process.DefaultConfig()
+	cfg.Restart.CrashLoop = process.CrashLoopConfig{Threshold: 3, Window: time.Minute}
+	a := &Application{processConfig: cfg}
+	a.resetRestarts()
+	defer a.cancelRestart()
+
+	now := time.Now()
+	a.startedAt = now
+	for i := 0; i < 3; i++ {
+		require.NoError(t, a.recordRestart(now.Add(time.Duration(i)*time.Second)))
+	}
+	assert.Equal(t, 3, a.restarts)
+	assert.Equal(t, map[string]interface{}{"restarts": 3}, a.statePayload(nil))
+
+	err := a.recordRestart(now.Add(3 * time.Second))
+	assert.EqualError(t, err, "crash looping, restarted 3 times within 1m0s")
+	assert.True(t, a.crashLooping)
+	assert.Equal(t, 3, a.restarts)
+
+	// restarts outside of the window are forgotten
+	a.crashLooping = false
+	require.NoError(t, a.recordRestart(now.Add(time.Minute+time.Second)))
+	assert.Equal(t, 4, a.restarts)
+
+	a.resetRestarts()
+	assert.Equal(t, 0, a.restarts)
+	assert.False(t, a.crashLooping)
+	assert.Nil(t, a.statePayload(nil))
+}
+
+func TestStatePayload(t *testing.T) {
+	a := &Application{restarts: 2}
+	payload := map[string]interface{}{"queue": 10}
+	assert.Equal(t, map[string]interface{}{"queue": 10, "restarts": 2}, a.statePayload(payload))
+	// payload of the application is not modified
+	assert.Equal(t, map[string]interface{}{"queue": 10}, payload)
+}
+
+func TestRestartOnFailed(t *testing.T) {
+	log, err := logger.New("", false)
+	require.NoError(t, err)
+
+	cfg := process.DefaultConfig()
+	cfg.Restart.Backoff = process.RestartBackoff{Init: time.Hour, Max: time.Hour}
+	cfg.Restart.CrashLoop = process.CrashLoopConfig{Threshold: 1, Window: time.Hour}
+	a := &Application{
+		processConfig:  cfg,
+		statusReporter: status.NewController(log).RegisterApp("test", "test"),
+	}
+	a.resetRestarts()
+	defer a.cancelRestart()
+	a.startedAt = time.Now()
+
+	// the restart waits for the backoff instead of starting the program right away
+	proc := &process.Info{}
+	a.state = state.State{Status: state.Failed, Message: "failed", ProcessInfo: proc}
+	a.restart(proc)
+	assert.Equal(t, state.Restarting, a.state.Status)
+	assert.Nil(t, a.state.ProcessInfo)
+	assert.Equal(t, 1, a.restarts)
+
+	// and is subject to the crash loop detection
+	proc = &process.Info{}
+	a.state = state.State{Status: state.Failed, Message: "failed", ProcessInfo: proc}
+	a.restart(proc)
+	assert.Equal(t, state.Failed, a.state.Status)
+	assert.Equal(t, "failed, crash looping, restarted 1 times within 1h0m0s", a.state.Message)
+	assert.True(t, a.crashLooping)
+}
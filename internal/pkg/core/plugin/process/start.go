@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
@@ -60,6 +61,9 @@ func (a *Application) start(ctx context.Context, t app.Taggable, cfg map[string]
 	if a.state.Status == state.Restarting && !isRestart {
 		return nil
 	}
+	if !isRestart {
+		a.resetRestarts()
+	}
 
 	cfgStr, err := yaml.Marshal(cfg)
 	if err != nil {
@@ -126,18 +130,33 @@ func (a *Application) start(ctx context.Context, t app.Taggable, cfg map[string]
 	// of the beat with same data path fails to start
 	spec.Args = injectDataPath(spec.Args, a.pipelineID, a.id)
 
-	a.state.ProcessInfo, err = process.Start(
+	var opts []process.Option
+	if capture := a.captureOutput(); capture != nil {
+		defer capture.Release()
+		opts = append(opts, capture.Option())
+	}
+
+	isolation := process.Isolation{
+		Cgroup:  a.setupCgroup(),
+		Sandbox: a.desc.Spec().Sandbox,
+	}
+	a.state.ProcessInfo, err = process.StartIsolated(
+		nil,
 		a.logger,
 		spec.BinaryPath,
 		a.processConfig,
 		a.uid,
 		a.gid,
-		spec.Args)
+		isolation,
+		spec.Args,
+		opts...)
 	if err != nil {
 		return fmt.Errorf("%q failed to start %q: %w",
 			a.Name(), spec.BinaryPath, err)
 	}
 
+	a.startedAt = time.Now()
+
 	// write connect info to stdin
 	go a.writeToStdin(a.srvState, a.state.ProcessInfo.Stdin)
 
@@ -160,6 +179,21 @@ func (a *Application) writeToStdin(as *server.ApplicationState, wc io.WriteClose
 	_ = wc.Close()
 }
 
+// captureOutput opens the files the stdout and stderr of the application are written to, the output is
+// dropped when they cannot be opened.
+func (a *Application) captureOutput() *process.Capture {
+	if a.processConfig == nil || a.processConfig.OutputCapture == nil || !a.processConfig.OutputCapture.Enabled {
+		return nil
+	}
+	dir := filepath.Join(paths.ProgramOutputPath(), a.pipelineID)
+	capture, err := process.CaptureOutput(dir, a.id, a.processConfig.OutputCapture)
+	if err != nil {
+		a.logger.Errorf("%q failed to capture its output: %v", a.Name(), err)
+		return nil
+	}
+	return capture
+}
+
 func injectLogLevel(logLevel string, args []string) []string {
 	var level string
 	// Translate to level beat understands
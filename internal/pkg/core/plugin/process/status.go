@@ -6,7 +6,6 @@ package process
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -37,6 +36,9 @@ func (a *Application) OnStatusChange(s *server.ApplicationState, status proto.St
 		if s.Expected() == proto.StateExpected_STOPPING {
 			return
 		}
+		if !a.restartPolicy.RestartOnFailed() {
+			return
+		}
 
 		// it was marshalled to pass into the state, so unmarshall will always succeed
 		var cfg map[string]interface{}
@@ -112,13 +114,5 @@ func (a *Application) restart(proc *process.Info) {
 	}
 
 	a.state.ProcessInfo = nil
-
-	ctx := a.startContext
-	tag := a.tag
-
-	a.setState(state.Restarting, "", nil)
-	err := a.start(ctx, tag, a.restartConfig, true)
-	if err != nil {
-		a.setState(state.Crashed, fmt.Sprintf("failed to restart: %s", err), nil)
-	}
+	a.requestRestart(a.tag, a.restartConfig, a.state.Message)
 }
@@ -257,12 +257,9 @@ func (a *Application) Stop() {
 		return
 	}
 
-	if err := srvState.Stop(a.processConfig.StopTimeout); err != nil {
+	if err := plugin.StopApplication(srvState, a.processConfig); err != nil {
 		a.appLock.Lock()
-		a.setState(
-			state.Failed,
-			fmt.Errorf("failed to stop after %s: %w", a.processConfig.StopTimeout, err).Error(),
-			nil)
+		a.setState(state.Failed, err.Error(), nil)
 	} else {
 		a.appLock.Lock()
 		a.setState(state.Stopped, "Stopped", nil)
@@ -0,0 +1,217 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// OutputCaptureConfig configures the capture of the stdout and stderr of the programs.
+type OutputCaptureConfig struct {
+	Enabled bool `yaml:"enabled" config:"enabled"`
+
+	// MaxSize is the size above which a file is rotated.
+	MaxSize ByteSize `yaml:"max_size" config:"max_size"`
+
+	// MaxFiles is the number of rotated files kept by stream, in addition to the current file.
+	MaxFiles int `yaml:"max_files" config:"max_files"`
+}
+
+// DefaultOutputCaptureConfig creates a config with pre-set default values.
+func DefaultOutputCaptureConfig() *OutputCaptureConfig {
+	return &OutputCaptureConfig{
+		Enabled:  true,
+		MaxSize:  10 * 1024 * 1024,
+		MaxFiles: 3,
+	}
+}
+
+// Validate validates the config.
+func (c *OutputCaptureConfig) Validate() error {
+	if c.MaxSize <= 0 {
+		return fmt.Errorf("max_size must be greater than 0")
+	}
+	if c.MaxFiles < 0 {
+		return fmt.Errorf("max_files cannot be negative")
+	}
+	return nil
+}
+
+// OutputFiles returns the current files the stdout and stderr of a program are captured in.
+func OutputFiles(dir, name string) (stdout string, stderr string) {
+	name = sanitizeOutputName(name)
+	return filepath.Join(dir, name+".stdout.log"), filepath.Join(dir, name+".stderr.log")
+}
+
+// Capture copies the stdout and stderr of a process into rotated files, the files are closed once the
+// process and its children exit.
+type Capture struct {
+	stdout *os.File
+	stderr *os.File
+}
+
+// CaptureOutput opens the files of the stdout and stderr of a program, the process is attached to them
+// with the option of the capture. The capture must be released once the process started or failed to.
+func CaptureOutput(dir, name string, cfg *OutputCaptureConfig) (*Capture, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create output directory %s: %w", dir, err)
+	}
+
+	stdoutPath, stderrPath := OutputFiles(dir, name)
+	stdout, err := captureStream(stdoutPath, cfg)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := captureStream(stderrPath, cfg)
+	if err != nil {
+		_ = stdout.Close()
+		return nil, err
+	}
+	return &Capture{stdout: stdout, stderr: stderr}, nil
+}
+
+// Option attaches the stdout and stderr of the process to the capture.
+func (c *Capture) Option() Option {
+	return func(cmd *exec.Cmd) {
+		cmd.Stdout = c.stdout
+		cmd.Stderr = c.stderr
+	}
+}
+
+// Release closes the pipes of the agent, the process keeps its own.
+func (c *Capture) Release() {
+	_ = c.stdout.Close()
+	_ = c.stderr.Close()
+}
+
+// captureStream returns the end of a pipe to write into the rotated file.
+func captureStream(path string, cfg *OutputCaptureConfig) (*os.File, error) {
+	f, err := openRotatedFile(path, int64(cfg.MaxSize), cfg.MaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+	go func() {
+		// failing writes are dropped, the pipe is drained to never block the process
+		_, _ = io.Copy(dropErrors{f}, r)
+		_ = r.Close()
+		_ = f.Close()
+	}()
+	return w, nil
+}
+
+type dropErrors struct {
+	w io.Writer
+}
+
+func (d dropErrors) Write(p []byte) (int, error) {
+	_, _ = d.w.Write(p)
+	return len(p), nil
+}
+
+// rotatedFile is a file rotated when it reaches its maximum size, rotated files are suffixed by their
+// index, e.g. filebeat.stderr.log.1 is the most recent rotated file.
+type rotatedFile struct {
+	mx       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+func openRotatedFile(path string, maxSize int64, maxFiles int) (*rotatedFile, error) {
+	f := &rotatedFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatedFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open %s: %w", f.path, err)
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write writes into the file, the file is rotated first when the data does not fit.
+func (f *rotatedFile) Write(p []byte) (int, error) {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if f.file == nil {
+		return 0, fmt.Errorf("%s is closed", f.path)
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatedFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	if f.maxFiles == 0 {
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		for i := f.maxFiles - 1; i > 0; i-- {
+			err := os.Rename(f.path+"."+strconv.Itoa(i), f.path+"."+strconv.Itoa(i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(f.path, f.path+".1"); err != nil {
+			return err
+		}
+	}
+	return f.open()
+}
+
+// Close closes the file.
+func (f *rotatedFile) Close() error {
+	f.mx.Lock()
+	defer f.mx.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func sanitizeOutputName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(name)
+}
@@ -0,0 +1,98 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filebeat.stderr.log")
+	f, err := openRotatedFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	assertFile(t, path, "fourth\n")
+	assertFile(t, path+".1", "third\n")
+	assertFile(t, path+".2", "second\n")
+	assert.NoFileExists(t, path+".3")
+
+	// appends to the existing file
+	f, err = openRotatedFile(path, 100, 2)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("fifth\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assertFile(t, path, "fourth\nfifth\n")
+}
+
+func TestRotatedFileNoBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filebeat.stdout.log")
+	f, err := openRotatedFile(path, 10, 0)
+	require.NoError(t, err)
+	for _, line := range []string{"first\n", "second\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	assertFile(t, path, "second\n")
+	assert.NoFileExists(t, path+".1")
+}
+
+func TestCaptureOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	capture, err := CaptureOutput(dir, "filebeat--8.2.0", DefaultOutputCaptureConfig())
+	require.NoError(t, err)
+
+	cmd := exec.Command("/bin/sh", "-c", "echo out; echo panic >&2")
+	capture.Option()(cmd)
+	require.NoError(t, cmd.Start())
+	capture.Release()
+	require.NoError(t, cmd.Wait())
+
+	stdout, stderr := OutputFiles(dir, "filebeat--8.2.0")
+	assert.Equal(t, filepath.Join(dir, "filebeat--8.2.0.stderr.log"), stderr)
+	assert.Eventually(t, func() bool {
+		out, _ := ioutil.ReadFile(stdout)
+		errOut, _ := ioutil.ReadFile(stderr)
+		return string(out) == "out\n" && string(errOut) == "panic\n"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func assertFile(t *testing.T, path, expected string) {
+	t.Helper()
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(content))
+}
+
+func TestOutputCaptureConfigValidate(t *testing.T) {
+	require.NoError(t, DefaultOutputCaptureConfig().Validate())
+
+	for _, c := range []OutputCaptureConfig{
+		{MaxSize: 0, MaxFiles: 3},
+		{MaxSize: -1, MaxFiles: 3},
+		{MaxSize: 1024, MaxFiles: -1},
+	} {
+		assert.Error(t, c.Validate(), "max_size %d, max_files %d", c.MaxSize, c.MaxFiles)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+// CgroupCommand is the hidden command of the agent placing a program in its cgroup before executing
+// it.
+const CgroupCommand = "cgroup_exec"
+
+// Cgroup is the cgroup v2 of a program, every process of the program started in it is limited by
+// the resource limits of the cgroup.
+type Cgroup struct {
+	path   string
+	limits ResourceLimits
+}
+
+// NewCgroup creates the cgroup of a program under the cgroup of the agent and applies the limits,
+// an existing cgroup is reused with the new limits.
+func NewCgroup(name string, limits ResourceLimits) (*Cgroup, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	return newCgroup(name, limits)
+}
+
+// Path returns the path of the cgroup in the cgroup filesystem.
+func (c *Cgroup) Path() string {
+	return c.path
+}
+
+// Limits returns the limits applied to the cgroup.
+func (c *Cgroup) Limits() ResourceLimits {
+	return c.limits
+}
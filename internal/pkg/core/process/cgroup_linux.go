@@ -0,0 +1,317 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// cpuPeriod is the period in microseconds of the cpu quota.
+	cpuPeriod = 100000
+
+	// agentCgroup is the leaf the processes of the agent are moved to, cgroup v2 does not allow a
+	// cgroup to both contain processes and delegate controllers to its children.
+	agentCgroup = "agent"
+	// programsCgroup is the parent of the cgroups of the programs.
+	programsCgroup = "programs"
+	// rootCgroup is created when the agent runs in the root cgroup.
+	rootCgroup = "elastic-agent"
+
+	// cgroupEnv passes the cgroup of the program to the cgroup command.
+	cgroupEnv = "ELASTIC_AGENT_CGROUP"
+)
+
+// cgroupSpec is the cgroup joined by the cgroup command, the cgroup command runs with the credentials
+// of the agent to join the cgroup and switches to the user of the program itself.
+type cgroupSpec struct {
+	Path string `json:"path"`
+	UID  int    `json:"uid"`
+	GID  int    `json:"gid"`
+}
+
+var (
+	// cgroupFS is the mount point of the cgroup v2 filesystem.
+	cgroupFS = "/sys/fs/cgroup"
+	// selfCgroup lists the cgroup of the agent.
+	selfCgroup = "/proc/self/cgroup"
+
+	controllers = []string{"cpu", "memory", "pids", "io"}
+
+	programsOnce sync.Once
+	programsPath string
+	programsErr  error
+)
+
+func newCgroup(name string, limits ResourceLimits) (*Cgroup, error) {
+	programsOnce.Do(func() {
+		programsPath, programsErr = setupPrograms()
+	})
+	if programsErr != nil {
+		return nil, programsErr
+	}
+
+	path := filepath.Join(programsPath, sanitizeCgroupName(name))
+	if err := os.Mkdir(path, 0755); err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("failed to create cgroup %s: %w", path, err)
+	}
+	c := &Cgroup{path: path, limits: limits}
+	if err := c.apply(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// setupPrograms prepares the cgroup of the agent to delegate the controllers to the cgroups of the
+// programs and returns the path of their parent.
+func setupPrograms() (string, error) {
+	if _, err := os.Stat(filepath.Join(cgroupFS, "cgroup.controllers")); err != nil {
+		return "", ErrCgroupsUnsupported
+	}
+	self, err := readSelfCgroup()
+	if err != nil {
+		return "", err
+	}
+
+	base := filepath.Join(cgroupFS, self)
+	if self == "/" && isRootCgroup(cgroupFS) {
+		// the root cgroup can contain processes, programs are grouped under their own cgroup.
+		base = filepath.Join(cgroupFS, rootCgroup)
+		if err := os.Mkdir(base, 0755); err != nil && !os.IsExist(err) {
+			return "", fmt.Errorf("failed to create cgroup %s: %w", base, err)
+		}
+		if err := enableControllers(cgroupFS); err != nil {
+			return "", err
+		}
+	} else if err := moveToLeaf(base); err != nil {
+		return "", err
+	}
+	if err := enableControllers(base); err != nil {
+		return "", err
+	}
+
+	programs := filepath.Join(base, programsCgroup)
+	if err := os.Mkdir(programs, 0755); err != nil && !os.IsExist(err) {
+		return "", fmt.Errorf("failed to create cgroup %s: %w", programs, err)
+	}
+	if err := enableControllers(programs); err != nil {
+		return "", err
+	}
+	return programs, nil
+}
+
+// isRootCgroup returns true when the cgroup is the root of the hierarchy, the cgroup of the agent also
+// reads as / at the root of a cgroup namespace, e.g. in a container. Only the root cgroup has no type.
+func isRootCgroup(path string) bool {
+	_, err := os.Stat(filepath.Join(path, "cgroup.type"))
+	return os.IsNotExist(err)
+}
+
+// readSelfCgroup returns the cgroup v2 of the agent.
+func readSelfCgroup() (string, error) {
+	content, err := ioutil.ReadFile(selfCgroup)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the cgroup of the agent: %w", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		// cgroup v2 is the hierarchy 0 without controllers, e.g. 0::/system.slice/elastic-agent.service
+		if path := strings.TrimPrefix(scanner.Text(), "0::"); path != scanner.Text() {
+			return path, nil
+		}
+	}
+	return "", ErrCgroupsUnsupported
+}
+
+// moveToLeaf moves the processes of the cgroup to its agent leaf.
+func moveToLeaf(base string) error {
+	leaf := filepath.Join(base, agentCgroup)
+	if err := os.Mkdir(leaf, 0755); err != nil && !os.IsExist(err) {
+		return fmt.Errorf("failed to create cgroup %s, the service must be allowed to delegate its cgroup: %w", leaf, err)
+	}
+	procs, err := ioutil.ReadFile(filepath.Join(base, "cgroup.procs"))
+	if err != nil {
+		return fmt.Errorf("failed to read processes of cgroup %s: %w", base, err)
+	}
+	for _, pid := range strings.Fields(string(procs)) {
+		if err := writeCgroupFile(leaf, "cgroup.procs", pid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enableControllers delegates the supported controllers of the cgroup to its children.
+func enableControllers(path string) error {
+	available, err := ioutil.ReadFile(filepath.Join(path, "cgroup.controllers"))
+	if err != nil {
+		return fmt.Errorf("failed to read controllers of cgroup %s: %w", path, err)
+	}
+	var enable []string
+	for _, available := range strings.Fields(string(available)) {
+		for _, c := range controllers {
+			if available == c {
+				enable = append(enable, "+"+c)
+			}
+		}
+	}
+	if len(enable) == 0 {
+		return nil
+	}
+	return writeCgroupFile(path, "cgroup.subtree_control", strings.Join(enable, " "))
+}
+
+// apply writes the limits to the cgroup, removing the limits that are not set anymore.
+func (c *Cgroup) apply() error {
+	files := []struct {
+		name  string
+		set   bool
+		value string
+		unset string
+	}{
+		{"cpu.max", c.limits.CPU > 0, fmt.Sprintf("%d %d", int64(c.limits.CPU*cpuPeriod), cpuPeriod), fmt.Sprintf("max %d", cpuPeriod)},
+		{"memory.max", c.limits.Memory > 0, strconv.FormatUint(uint64(c.limits.Memory), 10), "max"},
+		{"pids.max", c.limits.Pids > 0, strconv.FormatInt(c.limits.Pids, 10), "max"},
+		{"io.weight", c.limits.IOWeight > 0, fmt.Sprintf("default %d", c.limits.IOWeight), "default 100"},
+	}
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(c.path, f.name)); err != nil {
+			if !f.set {
+				// controller not available, nothing to limit
+				continue
+			}
+			return fmt.Errorf("cannot set %s of cgroup %s, controller is not available", f.name, c.path)
+		}
+		value := f.unset
+		if f.set {
+			value = f.value
+		}
+		if err := writeCgroupFile(c.path, f.name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wrap starts the command through the cgroup command of the agent, the cgroup command joins the
+// cgroup before it executes the program so the program never runs without its limits.
+func (c *Cgroup) wrap(cmd *exec.Cmd) error {
+	if c == nil {
+		return nil
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the agent executable: %w", err)
+	}
+
+	spec := cgroupSpec{Path: c.path, UID: -1, GID: -1}
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.Credential != nil {
+		// only the agent is allowed to move processes to the cgroup
+		spec.UID, spec.GID = int(cmd.SysProcAttr.Credential.Uid), int(cmd.SysProcAttr.Credential.Gid)
+		cmd.SysProcAttr.Credential = nil
+	}
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	cmd.Args = append([]string{exe, CgroupCommand, "--", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = exe
+	cmd.Env = append(cmd.Env, cgroupEnv+"="+string(encoded))
+	return nil
+}
+
+// ExecInCgroup joins the cgroup received from the agent and replaces the current process with the
+// program, args starts with the path of the program. It only returns on failure.
+func ExecInCgroup(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing program to execute")
+	}
+	var spec cgroupSpec
+	if err := json.Unmarshal([]byte(os.Getenv(cgroupEnv)), &spec); err != nil {
+		return fmt.Errorf("invalid cgroup: %w", err)
+	}
+
+	// 0 is the writing process, the cgroup is joined before anything of the program runs
+	if err := writeCgroupFile(spec.Path, "cgroup.procs", "0"); err != nil {
+		return err
+	}
+	if spec.GID >= 0 {
+		if err := syscall.Setgid(spec.GID); err != nil {
+			return fmt.Errorf("failed to set group %d: %w", spec.GID, err)
+		}
+	}
+	if spec.UID >= 0 {
+		if err := syscall.Setuid(spec.UID); err != nil {
+			return fmt.Errorf("failed to set user %d: %w", spec.UID, err)
+		}
+	}
+	// the parent death signal is reset when the credentials change
+	if err := unix.Prctl(unix.PR_SET_PDEATHSIG, uintptr(syscall.SIGKILL), 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set parent death signal: %w", err)
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, cgroupEnv+"=") {
+			env = append(env, e)
+		}
+	}
+	if err := syscall.Exec(args[0], args, env); err != nil {
+		return fmt.Errorf("failed to execute %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// OOMKills returns the number of processes of the cgroup killed by the OOM killer.
+func (c *Cgroup) OOMKills() (uint64, error) {
+	content, err := ioutil.ReadFile(filepath.Join(c.path, "memory.events"))
+	if err != nil {
+		return 0, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, nil
+}
+
+// Remove removes the cgroup, it must not contain any process anymore.
+func (c *Cgroup) Remove() error {
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove cgroup %s: %w", c.path, err)
+	}
+	return nil
+}
+
+func writeCgroupFile(path, name, value string) error {
+	if err := ioutil.WriteFile(filepath.Join(path, name), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to write %q to %s of cgroup %s: %w", value, name, path, err)
+	}
+	return nil
+}
+
+func sanitizeCgroupName(name string) string {
+	return strings.NewReplacer("/", "_", string(os.PathSeparator), "_", "..", "_").Replace(name)
+}
@@ -0,0 +1,163 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCgroupFS creates a cgroup filesystem with the agent in the given cgroup, the files of the
+// controllers are regular files.
+func fakeCgroupFS(t *testing.T, self string) string {
+	t.Helper()
+	root := t.TempDir()
+
+	prevFS, prevSelf := cgroupFS, selfCgroup
+	t.Cleanup(func() {
+		cgroupFS, selfCgroup = prevFS, prevSelf
+		programsOnce = sync.Once{}
+	})
+	cgroupFS = filepath.Join(root, "cgroup")
+	selfCgroup = filepath.Join(root, "self")
+	programsOnce = sync.Once{}
+
+	require.NoError(t, ioutil.WriteFile(selfCgroup, []byte("0::"+self+"\n"), 0644))
+	writeControllers(t, cgroupFS)
+	if self != "/" {
+		writeControllers(t, filepath.Join(cgroupFS, self))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(cgroupFS, self, "cgroup.procs"), []byte("1\n2\n"), 0644))
+	}
+	return cgroupFS
+}
+
+func writeControllers(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(path, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(path, "cgroup.controllers"), []byte("cpuset cpu io memory pids\n"), 0644))
+}
+
+func readCgroupFile(t *testing.T, path, name string) string {
+	t.Helper()
+	content, err := ioutil.ReadFile(filepath.Join(path, name))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func TestNewCgroup(t *testing.T) {
+	fs := fakeCgroupFS(t, "/system.slice/elastic-agent.service")
+	base := filepath.Join(fs, "system.slice", "elastic-agent.service")
+	programs := filepath.Join(base, programsCgroup)
+	// the kernel creates the files of the controllers of a new cgroup
+	writeControllers(t, programs)
+	cgroup := filepath.Join(programs, "filebeat-default")
+	require.NoError(t, os.MkdirAll(cgroup, 0755))
+	for _, f := range []string{"cpu.max", "memory.max", "pids.max", "io.weight", "memory.events"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(cgroup, f), nil, 0644))
+	}
+
+	c, err := NewCgroup("filebeat-default", ResourceLimits{CPU: 1.5, Memory: 1 << 30, IOWeight: 50})
+	require.NoError(t, err)
+	assert.Equal(t, cgroup, c.Path())
+
+	// agent moved to its leaf to delegate the controllers
+	assert.Equal(t, "2", readCgroupFile(t, filepath.Join(base, agentCgroup), "cgroup.procs"))
+	assert.Equal(t, "+cpu +io +memory +pids", readCgroupFile(t, base, "cgroup.subtree_control"))
+	assert.Equal(t, "+cpu +io +memory +pids", readCgroupFile(t, programs, "cgroup.subtree_control"))
+
+	assert.Equal(t, "150000 100000", readCgroupFile(t, cgroup, "cpu.max"))
+	assert.Equal(t, "1073741824", readCgroupFile(t, cgroup, "memory.max"))
+	assert.Equal(t, "max", readCgroupFile(t, cgroup, "pids.max"))
+	assert.Equal(t, "default 50", readCgroupFile(t, cgroup, "io.weight"))
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(cgroup, "memory.events"), []byte("low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n"), 0644))
+	kills, err := c.OOMKills()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), kills)
+}
+
+func TestNewCgroupRoot(t *testing.T) {
+	fs := fakeCgroupFS(t, "/")
+	base := filepath.Join(fs, rootCgroup)
+	// the kernel creates the files of the controllers of a new cgroup
+	writeControllers(t, base)
+	writeControllers(t, filepath.Join(base, programsCgroup))
+
+	c, err := NewCgroup("metricbeat/default", ResourceLimits{})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(base, programsCgroup, "metricbeat_default"), c.Path())
+	assert.Equal(t, "+cpu +io +memory +pids", readCgroupFile(t, fs, "cgroup.subtree_control"))
+
+	require.NoError(t, c.Remove())
+	assert.NoDirExists(t, c.Path())
+}
+
+func TestNewCgroupNamespace(t *testing.T) {
+	fs := fakeCgroupFS(t, "/")
+	// the root of a cgroup namespace is not the root cgroup, it cannot contain processes and delegate
+	require.NoError(t, ioutil.WriteFile(filepath.Join(fs, "cgroup.type"), []byte("domain\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(fs, "cgroup.procs"), []byte("1\n"), 0644))
+	programs := filepath.Join(fs, programsCgroup)
+	writeControllers(t, programs)
+
+	c, err := NewCgroup("filebeat-default", ResourceLimits{})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(programs, "filebeat-default"), c.Path())
+	assert.Equal(t, "1", readCgroupFile(t, filepath.Join(fs, agentCgroup), "cgroup.procs"))
+	assert.Equal(t, "+cpu +io +memory +pids", readCgroupFile(t, fs, "cgroup.subtree_control"))
+	assert.NoDirExists(t, filepath.Join(fs, rootCgroup))
+}
+
+func TestCgroupWrap(t *testing.T) {
+	cmd := exec.Command("/usr/bin/filebeat", "-e")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Pdeathsig:  syscall.SIGKILL,
+		Credential: &syscall.Credential{Uid: 1000, Gid: 1000, NoSetGroups: true},
+	}
+	cgroup := &Cgroup{path: "/sys/fs/cgroup/programs/filebeat-default"}
+	require.NoError(t, cgroup.wrap(cmd))
+
+	// the cgroup command joins the cgroup as the agent before it executes the program
+	assert.Equal(t, []string{CgroupCommand, "--", "/usr/bin/filebeat", "-e"}, cmd.Args[1:])
+	assert.Equal(t, cmd.Path, cmd.Args[0])
+	assert.Nil(t, cmd.SysProcAttr.Credential)
+
+	env := cmd.Env[len(cmd.Env)-1]
+	require.True(t, strings.HasPrefix(env, cgroupEnv+"="))
+	assert.JSONEq(t, `{"path":"/sys/fs/cgroup/programs/filebeat-default","uid":1000,"gid":1000}`, strings.TrimPrefix(env, cgroupEnv+"="))
+
+	unchanged := exec.Command("/usr/bin/filebeat")
+	require.NoError(t, (*Cgroup)(nil).wrap(unchanged))
+	assert.Equal(t, "/usr/bin/filebeat", unchanged.Path)
+}
+
+func TestNewCgroupMissingController(t *testing.T) {
+	fs := fakeCgroupFS(t, "/")
+	writeControllers(t, filepath.Join(fs, rootCgroup))
+	writeControllers(t, filepath.Join(fs, rootCgroup, programsCgroup))
+
+	_, err := NewCgroup("filebeat-default", ResourceLimits{Pids: 10})
+	assert.Error(t, err)
+}
+
+func TestNewCgroupUnsupported(t *testing.T) {
+	fakeCgroupFS(t, "/")
+	require.NoError(t, os.Remove(filepath.Join(cgroupFS, "cgroup.controllers")))
+
+	_, err := NewCgroup("filebeat-default", ResourceLimits{})
+	assert.ErrorIs(t, err, ErrCgroupsUnsupported)
+}
@@ -0,0 +1,36 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !linux
+// +build !linux
+
+package process
+
+import "os/exec"
+
+func newCgroup(string, ResourceLimits) (*Cgroup, error) {
+	return nil, ErrCgroupsUnsupported
+}
+
+func (c *Cgroup) wrap(*exec.Cmd) error {
+	if c == nil {
+		return nil
+	}
+	return ErrCgroupsUnsupported
+}
+
+// ExecInCgroup is only supported on Linux.
+func ExecInCgroup([]string) error {
+	return ErrCgroupsUnsupported
+}
+
+// OOMKills returns the number of processes of the cgroup killed by the OOM killer.
+func (c *Cgroup) OOMKills() (uint64, error) {
+	return 0, ErrCgroupsUnsupported
+}
+
+// Remove removes the cgroup, it must not contain any process anymore.
+func (c *Cgroup) Remove() error {
+	return nil
+}
@@ -12,7 +12,19 @@ type Config struct {
 	StopTimeout    time.Duration `yaml:"stop_timeout" config:"stop_timeout"`
 	FailureTimeout time.Duration `yaml:"failure_timeout" config:"failure_timeout"`
 
-	// TODO: cgroups and namespaces
+	// DrainTimeout is how long programs reporting that they drain their queued events are waited for
+	// when stopped, instead of the stop timeout. Programs are drained in parallel on shutdown, so it
+	// must stay below the stop timeout of the service manager.
+	DrainTimeout time.Duration `yaml:"drain_timeout" config:"drain_timeout"`
+
+	// OutputCapture captures the stdout and stderr of the programs into files.
+	OutputCapture *OutputCaptureConfig `yaml:"output_capture" config:"output_capture"`
+
+	// Restart configures how crashed programs are restarted.
+	Restart *RestartConfig `yaml:"restart" config:"restart"`
+
+	// Cgroups limits the resources of the programs, Linux only.
+	Cgroups *CgroupsConfig `yaml:"cgroups" config:"cgroups"`
 }
 
 // DefaultConfig creates a config with pre-set default values.
@@ -21,5 +33,9 @@ func DefaultConfig() *Config {
 		SpawnTimeout:   30 * time.Second,
 		StopTimeout:    30 * time.Second,
 		FailureTimeout: 10 * time.Second,
+		DrainTimeout:   45 * time.Second,
+		OutputCapture:  DefaultOutputCaptureConfig(),
+		Restart:        DefaultRestartConfig(),
+		Cgroups:        DefaultCgroupsConfig(),
 	}
 }
@@ -26,6 +26,8 @@ type Info struct {
 	PID     int
 	Process *os.Process
 	Stdin   io.WriteCloser
+	// Cgroup the process is placed in, nil when the process is not limited.
+	Cgroup *Cgroup
 }
 
 // Option is an option func to change the underlying command
@@ -46,10 +48,32 @@ func Start(logger *logger.Logger, path string, config *Config, uid, gid int, arg
 // - process id
 // - error
 func StartContext(ctx context.Context, logger *logger.Logger, path string, config *Config, uid, gid int, args []string, opts ...Option) (*Info, error) {
+	return StartIsolated(ctx, logger, path, config, uid, gid, Isolation{}, args, opts...)
+}
+
+// Isolation confines a process beyond its user and group.
+type Isolation struct {
+	// Cgroup the process is placed in, the process is not limited when nil.
+	Cgroup *Cgroup
+	// Sandbox the process is started in, the process is not sandboxed when nil.
+	Sandbox *Sandbox
+}
+
+// StartIsolated starts a new process with context, isolated as defined. An isolated process is started
+// through the hidden commands of the agent, the program exits with an error when it cannot be placed
+// in its cgroup.
+func StartIsolated(ctx context.Context, logger *logger.Logger, path string, config *Config, uid, gid int, isolation Isolation, args []string, opts ...Option) (*Info, error) {
 	cmd := getCmd(ctx, logger, path, []string{}, uid, gid, args...)
 	for _, o := range opts {
 		o(cmd)
 	}
+	if err := isolation.Sandbox.wrap(cmd); err != nil {
+		return nil, errors.New(err, fmt.Sprintf("failed to sandbox '%s'", path))
+	}
+	// the cgroup is joined first, the sandbox is set up by a process already limited by the cgroup
+	if err := isolation.Cgroup.wrap(cmd); err != nil {
+		return nil, errors.New(err, fmt.Sprintf("failed to place '%s' in its cgroup", path))
+	}
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return nil, err
@@ -72,6 +96,7 @@ func StartContext(ctx context.Context, logger *logger.Logger, path string, confi
 		PID:     cmd.Process.Pid,
 		Process: cmd.Process,
 		Stdin:   stdin,
+		Cgroup:  isolation.Cgroup,
 	}, err
 }
 
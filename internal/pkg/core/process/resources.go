@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
+)
+
+// ErrCgroupsUnsupported is returned when cgroups are enabled on a system without cgroup v2.
+var ErrCgroupsUnsupported = fmt.Errorf("cgroup v2 is not supported on this system")
+
+// CgroupsConfig configures the cgroup v2 each program is placed in, Linux only.
+type CgroupsConfig struct {
+	Enabled bool `yaml:"enabled" config:"enabled"`
+
+	// Limits of the programs by command, e.g. filebeat, they replace the limits set by the spec of the
+	// program.
+	Limits map[string]ResourceLimits `yaml:"limits,omitempty" config:"limits"`
+}
+
+// DefaultCgroupsConfig creates a config with pre-set default values.
+func DefaultCgroupsConfig() *CgroupsConfig {
+	return &CgroupsConfig{
+		Enabled: false,
+	}
+}
+
+// LimitsFor returns the limits of the program, limits set in the configuration take precedence over
+// the ones of its spec.
+func (c *CgroupsConfig) LimitsFor(cmd string, spec *ResourceLimits) ResourceLimits {
+	var limits ResourceLimits
+	if spec != nil {
+		limits = *spec
+	}
+	if c == nil {
+		return limits
+	}
+	override, ok := c.Limits[cmd]
+	if !ok {
+		return limits
+	}
+	if override.CPU != 0 {
+		limits.CPU = override.CPU
+	}
+	if override.Memory != 0 {
+		limits.Memory = override.Memory
+	}
+	if override.Pids != 0 {
+		limits.Pids = override.Pids
+	}
+	if override.IOWeight != 0 {
+		limits.IOWeight = override.IOWeight
+	}
+	return limits
+}
+
+// ResourceLimits are the resources a program can use, zero values are not limited.
+type ResourceLimits struct {
+	// CPU is the number of CPUs the program can use, e.g. 0.5 for half of a CPU.
+	CPU float64 `yaml:"cpu,omitempty" config:"cpu" json:"cpu,omitempty"`
+	// Memory is the memory usage above which the program is killed by the OOM killer.
+	Memory ByteSize `yaml:"memory,omitempty" config:"memory" json:"memory,omitempty"`
+	// Pids is the maximum number of processes and threads of the program.
+	Pids int64 `yaml:"pids,omitempty" config:"pids" json:"pids,omitempty"`
+	// IOWeight is the proportion of IO of the program, from 1 to 10000, the default weight is 100.
+	IOWeight int `yaml:"io_weight,omitempty" config:"io_weight" json:"io_weight,omitempty"`
+}
+
+// IsZero returns true when no limit is set.
+func (l ResourceLimits) IsZero() bool {
+	return l == ResourceLimits{}
+}
+
+// Validate validates the limits.
+func (l ResourceLimits) Validate() error {
+	if l.CPU < 0 {
+		return fmt.Errorf("cpu limit cannot be negative")
+	}
+	if l.Memory < 0 {
+		return fmt.Errorf("memory limit cannot be negative")
+	}
+	if l.Pids < 0 {
+		return fmt.Errorf("pids limit cannot be negative")
+	}
+	if l.IOWeight != 0 && (l.IOWeight < 1 || l.IOWeight > 10000) {
+		return fmt.Errorf("io_weight must be between 1 and 10000")
+	}
+	return nil
+}
+
+// ByteSize is a size in bytes, it is configured in a human readable format, e.g. 512MiB. It parses
+// sizes like the libbeat configuration and can also be read from the YAML of the program specs.
+type ByteSize cfgtype.ByteSize
+
+// Unpack converts a size defined in a human readable format into bytes.
+func (s *ByteSize) Unpack(v string) error {
+	return (*cfgtype.ByteSize)(s).Unpack(v)
+}
+
+// UnmarshalYAML converts a size defined in a human readable format into bytes.
+func (s *ByteSize) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v string
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	return s.Unpack(v)
+}
+
+// MarshalYAML returns the size in a human readable format.
+func (s ByteSize) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// String returns the size in IEC units rounded to one decimal, e.g. 1.5 GiB.
+func (s ByteSize) String() string {
+	const unit = 1024
+	if s < unit {
+		return strconv.FormatInt(int64(s), 10) + " B"
+	}
+	size := float64(s)
+	exp := 0
+	for size >= unit && exp < len(byteUnits) {
+		size /= unit
+		exp++
+	}
+	return strconv.FormatFloat(math.Round(size*10)/10, 'f', -1, 64) + " " + byteUnits[exp-1]
+}
+
+var byteUnits = []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+)
+
+func TestCgroupsConfigLimitsFor(t *testing.T) {
+	spec := &ResourceLimits{CPU: 1, Memory: 1 << 30, Pids: 100}
+	cfg := &CgroupsConfig{
+		Enabled: true,
+		Limits: map[string]ResourceLimits{
+			"filebeat": {Memory: 512 << 20, IOWeight: 50},
+		},
+	}
+
+	assert.Equal(t, ResourceLimits{CPU: 1, Memory: 512 << 20, Pids: 100, IOWeight: 50}, cfg.LimitsFor("filebeat", spec))
+	assert.Equal(t, *spec, cfg.LimitsFor("metricbeat", spec))
+	assert.Equal(t, ResourceLimits{Memory: 512 << 20, IOWeight: 50}, cfg.LimitsFor("filebeat", nil))
+
+	var noConfig *CgroupsConfig
+	assert.Equal(t, *spec, noConfig.LimitsFor("filebeat", spec))
+}
+
+func TestResourceLimitsValidate(t *testing.T) {
+	assert.NoError(t, ResourceLimits{}.Validate())
+	assert.NoError(t, ResourceLimits{CPU: 0.5, IOWeight: 10000}.Validate())
+	assert.Error(t, ResourceLimits{CPU: -1}.Validate())
+	assert.Error(t, ResourceLimits{Memory: -1}.Validate())
+	assert.Error(t, ResourceLimits{Pids: -1}.Validate())
+	assert.Error(t, ResourceLimits{IOWeight: 10001}.Validate())
+}
+
+func TestResourceLimitsUnpack(t *testing.T) {
+	const content = `
+enabled: true
+limits:
+  filebeat:
+    cpu: 0.5
+    memory: 512MiB
+    pids: 256
+    io_weight: 50
+`
+	expected := ResourceLimits{CPU: 0.5, Memory: 512 << 20, Pids: 256, IOWeight: 50}
+
+	t.Run("config", func(t *testing.T) {
+		c, err := config.NewConfigFrom(content)
+		require.NoError(t, err)
+		cgroups := DefaultCgroupsConfig()
+		require.NoError(t, c.Unpack(cgroups))
+		assert.True(t, cgroups.Enabled)
+		assert.Equal(t, expected, cgroups.Limits["filebeat"])
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		cgroups := DefaultCgroupsConfig()
+		require.NoError(t, yaml.Unmarshal([]byte(content), cgroups))
+		assert.Equal(t, expected, cgroups.Limits["filebeat"])
+
+		out, err := yaml.Marshal(cgroups.Limits["filebeat"])
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "memory: 512 MiB")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c, err := config.NewConfigFrom("limits.filebeat.io_weight: 0\nlimits.filebeat.pids: -1")
+		require.NoError(t, err)
+		assert.Error(t, c.Unpack(DefaultCgroupsConfig()))
+	})
+}
+
+func TestByteSizeString(t *testing.T) {
+	assert.Equal(t, "512 B", ByteSize(512).String())
+	assert.Equal(t, "1 KiB", ByteSize(1024).String())
+	assert.Equal(t, "1.5 GiB", ByteSize(3<<29).String())
+	assert.Equal(t, "8 EiB", ByteSize(math.MaxInt64).String())
+}
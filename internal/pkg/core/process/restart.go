@@ -0,0 +1,101 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"fmt"
+	"time"
+)
+
+// RestartPolicy defines when a program is restarted after it exits.
+type RestartPolicy string
+
+const (
+	// RestartAlways restarts the program whenever it exits, the default.
+	RestartAlways RestartPolicy = "always"
+	// RestartOnFailure restarts the program when it exits with a non-zero code, is killed or reports
+	// itself as failed.
+	RestartOnFailure RestartPolicy = "on-failure"
+	// RestartNever never restarts the program.
+	RestartNever RestartPolicy = "never"
+)
+
+// Validate validates the policy, an empty policy is the default policy.
+func (p RestartPolicy) Validate() error {
+	switch p {
+	case "", RestartAlways, RestartOnFailure, RestartNever:
+		return nil
+	}
+	return fmt.Errorf("unknown restart policy %q", p)
+}
+
+// RestartOnExit returns true when a program exiting with the code is restarted, the code of a
+// program killed by a signal is -1.
+func (p RestartPolicy) RestartOnExit(exitCode int) bool {
+	switch p {
+	case RestartNever:
+		return false
+	case RestartOnFailure:
+		return exitCode != 0
+	default:
+		return true
+	}
+}
+
+// RestartOnFailed returns true when a program reporting itself as failed is restarted.
+func (p RestartPolicy) RestartOnFailed() bool {
+	return p != RestartNever
+}
+
+// RestartConfig configures how programs are restarted.
+type RestartConfig struct {
+	// Backoff is the delay before restarting a program exiting repeatedly.
+	Backoff RestartBackoff `yaml:"backoff" config:"backoff"`
+
+	// CrashLoop marks a program restarting too often as failed, it is not restarted anymore.
+	CrashLoop CrashLoopConfig `yaml:"crash_loop" config:"crash_loop"`
+}
+
+// RestartBackoff is the exponential backoff between the restarts of a program.
+type RestartBackoff struct {
+	Init time.Duration `yaml:"init" config:"init"`
+	Max  time.Duration `yaml:"max" config:"max"`
+}
+
+// CrashLoopConfig defines when a program is crash looping.
+type CrashLoopConfig struct {
+	// Threshold is the number of restarts within the window above which a program is crash looping,
+	// zero disables the detection.
+	Threshold int           `yaml:"threshold" config:"threshold"`
+	Window    time.Duration `yaml:"window" config:"window"`
+}
+
+// DefaultRestartConfig creates a config with pre-set default values.
+func DefaultRestartConfig() *RestartConfig {
+	return &RestartConfig{
+		Backoff: RestartBackoff{
+			Init: 1 * time.Second,
+			Max:  1 * time.Minute,
+		},
+		CrashLoop: CrashLoopConfig{
+			Threshold: 10,
+			Window:    10 * time.Minute,
+		},
+	}
+}
+
+// Validate validates the config.
+func (c *RestartConfig) Validate() error {
+	if c.Backoff.Init <= 0 || c.Backoff.Max < c.Backoff.Init {
+		return fmt.Errorf("restart backoff max must be greater or equal to init and init must be positive")
+	}
+	if c.CrashLoop.Threshold < 0 {
+		return fmt.Errorf("crash_loop threshold cannot be negative")
+	}
+	if c.CrashLoop.Threshold > 0 && c.CrashLoop.Window <= 0 {
+		return fmt.Errorf("crash_loop window must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent/internal/pkg/config"
+)
+
+func TestRestartPolicy(t *testing.T) {
+	tests := []struct {
+		policy   RestartPolicy
+		exit0    bool
+		exit1    bool
+		killed   bool
+		onFailed bool
+	}{
+		{policy: "", exit0: true, exit1: true, killed: true, onFailed: true},
+		{policy: RestartAlways, exit0: true, exit1: true, killed: true, onFailed: true},
+		{policy: RestartOnFailure, exit0: false, exit1: true, killed: true, onFailed: true},
+		{policy: RestartNever, exit0: false, exit1: false, killed: false, onFailed: false},
+	}
+	for _, tc := range tests {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			require.NoError(t, tc.policy.Validate())
+			assert.Equal(t, tc.exit0, tc.policy.RestartOnExit(0))
+			assert.Equal(t, tc.exit1, tc.policy.RestartOnExit(1))
+			assert.Equal(t, tc.killed, tc.policy.RestartOnExit(-1))
+			assert.Equal(t, tc.onFailed, tc.policy.RestartOnFailed())
+		})
+	}
+
+	assert.EqualError(t, RestartPolicy("sometimes").Validate(), `unknown restart policy "sometimes"`)
+}
+
+func TestRestartConfigUnpack(t *testing.T) {
+	c, err := config.NewConfigFrom(`
+restart:
+  backoff.max: 5m
+  crash_loop.threshold: 3
+`)
+	require.NoError(t, err)
+	cfg := DefaultConfig()
+	require.NoError(t, c.Unpack(cfg))
+	assert.Equal(t, &RestartConfig{
+		Backoff:   RestartBackoff{Init: time.Second, Max: 5 * time.Minute},
+		CrashLoop: CrashLoopConfig{Threshold: 3, Window: 10 * time.Minute},
+	}, cfg.Restart)
+
+	c, err = config.NewConfigFrom("restart.backoff.init: 10m")
+	require.NoError(t, err)
+	assert.Error(t, c.Unpack(DefaultConfig()))
+}
@@ -0,0 +1,183 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SandboxCommand is the hidden command of the agent applying the sandbox of a program before
+// replacing itself with the program.
+const SandboxCommand = "sandbox_exec"
+
+// ErrSandboxUnsupported is returned when a sandboxed program is started on a system other than Linux.
+var ErrSandboxUnsupported = fmt.Errorf("sandboxing programs is only supported on Linux")
+
+// Namespace is a Linux namespace a sandboxed program runs in.
+type Namespace string
+
+const (
+	// MountNamespace gives the program its own mount points.
+	MountNamespace Namespace = "mount"
+	// PIDNamespace hides the processes of the host from the program.
+	PIDNamespace Namespace = "pid"
+	// IPCNamespace gives the program its own System V IPC and POSIX message queues.
+	IPCNamespace Namespace = "ipc"
+	// UTSNamespace gives the program its own hostname.
+	UTSNamespace Namespace = "uts"
+)
+
+// SeccompAction is the action taken when a program calls a system call.
+type SeccompAction string
+
+const (
+	// SeccompAllow allows the system call.
+	SeccompAllow SeccompAction = "allow"
+	// SeccompErrno fails the system call with EPERM.
+	SeccompErrno SeccompAction = "errno"
+	// SeccompLog allows the system call and logs it in the audit log.
+	SeccompLog SeccompAction = "log"
+	// SeccompKill kills the program.
+	SeccompKill SeccompAction = "kill"
+)
+
+// Sandbox restricts the privileges of a program beyond its user and group, Linux only.
+type Sandbox struct {
+	// Namespaces the program runs in, e.g. [mount, pid, ipc, uts].
+	Namespaces []Namespace `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+	// Capabilities of the program, the capabilities of its user are kept when nil.
+	Capabilities *Capabilities `yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
+	// Seccomp filters the system calls of the program.
+	Seccomp *SeccompProfile `yaml:"seccomp,omitempty" json:"seccomp,omitempty"`
+}
+
+// Capabilities defines the Linux capabilities of a program.
+type Capabilities struct {
+	// Keep lists the capabilities the program keeps, e.g. net_raw, all the others are dropped.
+	Keep []string `yaml:"keep" json:"keep"`
+}
+
+// SeccompProfile is the seccomp filter of a program.
+type SeccompProfile struct {
+	// DefaultAction is taken for the system calls without a rule, defaults to allow.
+	DefaultAction SeccompAction `yaml:"default_action,omitempty" json:"default_action,omitempty"`
+	// Syscalls are the rules by system call, the first rule of a system call is used.
+	Syscalls []SeccompRule `yaml:"syscalls" json:"syscalls"`
+}
+
+// SeccompRule defines the action taken for system calls.
+type SeccompRule struct {
+	Action SeccompAction `yaml:"action" json:"action"`
+	Names  []string      `yaml:"names" json:"names"`
+}
+
+// IsZero returns true when the sandbox does not restrict anything.
+func (s *Sandbox) IsZero() bool {
+	return s == nil || (len(s.Namespaces) == 0 && s.Capabilities == nil && s.Seccomp == nil)
+}
+
+// Validate validates the sandbox, system calls are validated against the architecture of the agent
+// on Linux.
+func (s *Sandbox) Validate() error {
+	if s == nil {
+		return nil
+	}
+	for _, ns := range s.Namespaces {
+		switch ns {
+		case MountNamespace, PIDNamespace, IPCNamespace, UTSNamespace:
+		default:
+			return fmt.Errorf("unknown namespace %q", ns)
+		}
+	}
+	if s.Capabilities != nil {
+		if _, err := s.Capabilities.keep(); err != nil {
+			return err
+		}
+	}
+	if s.Seccomp != nil {
+		if err := s.Seccomp.DefaultAction.validate(); err != nil {
+			return fmt.Errorf("invalid seccomp default_action: %w", err)
+		}
+		for _, rule := range s.Seccomp.Syscalls {
+			if rule.Action == "" {
+				return fmt.Errorf("missing action of seccomp rule for %s", strings.Join(rule.Names, ", "))
+			}
+			if err := rule.Action.validate(); err != nil {
+				return fmt.Errorf("invalid seccomp rule: %w", err)
+			}
+		}
+		if err := s.Seccomp.validateSyscalls(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a SeccompAction) validate() error {
+	switch a {
+	case "", SeccompAllow, SeccompErrno, SeccompLog, SeccompKill:
+		return nil
+	}
+	return fmt.Errorf("unknown action %q", a)
+}
+
+// keep returns the numbers of the capabilities to keep.
+func (c *Capabilities) keep() (map[int]bool, error) {
+	keep := make(map[int]bool, len(c.Keep))
+	for _, name := range c.Keep {
+		n, ok := capabilities[strings.TrimPrefix(strings.ToLower(name), "cap_")]
+		if !ok {
+			return nil, fmt.Errorf("unknown capability %q", name)
+		}
+		keep[n] = true
+	}
+	return keep, nil
+}
+
+// capabilities are the numbers of the Linux capabilities by name.
+var capabilities = map[string]int{
+	"chown":              0,
+	"dac_override":       1,
+	"dac_read_search":    2,
+	"fowner":             3,
+	"fsetid":             4,
+	"kill":               5,
+	"setgid":             6,
+	"setuid":             7,
+	"setpcap":            8,
+	"linux_immutable":    9,
+	"net_bind_service":   10,
+	"net_broadcast":      11,
+	"net_admin":          12,
+	"net_raw":            13,
+	"ipc_lock":           14,
+	"ipc_owner":          15,
+	"sys_module":         16,
+	"sys_rawio":          17,
+	"sys_chroot":         18,
+	"sys_ptrace":         19,
+	"sys_pacct":          20,
+	"sys_admin":          21,
+	"sys_boot":           22,
+	"sys_nice":           23,
+	"sys_resource":       24,
+	"sys_time":           25,
+	"sys_tty_config":     26,
+	"mknod":              27,
+	"lease":              28,
+	"audit_write":        29,
+	"audit_control":      30,
+	"setfcap":            31,
+	"mac_override":       32,
+	"mac_admin":          33,
+	"syslog":             34,
+	"wake_alarm":         35,
+	"block_suspend":      36,
+	"audit_read":         37,
+	"perfmon":            38,
+	"bpf":                39,
+	"checkpoint_restore": 40,
+}
@@ -0,0 +1,210 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// sandboxEnv passes the sandbox of the program to the sandbox command.
+const sandboxEnv = "ELASTIC_AGENT_SANDBOX"
+
+// sandboxSpec is the sandbox applied by the sandbox command, the sandbox command runs with the
+// credentials of the agent to set up the namespaces and switches to the user of the program itself.
+type sandboxSpec struct {
+	Sandbox
+	UID int `json:"uid"`
+	GID int `json:"gid"`
+}
+
+var namespaceFlags = map[Namespace]uintptr{
+	MountNamespace: syscall.CLONE_NEWNS,
+	PIDNamespace:   syscall.CLONE_NEWPID,
+	IPCNamespace:   syscall.CLONE_NEWIPC,
+	UTSNamespace:   syscall.CLONE_NEWUTS,
+}
+
+// wrap starts the command through the sandbox command of the agent, the namespaces are created when
+// the sandbox command is spawned, the rest of the sandbox is applied by the sandbox command before it
+// executes the program.
+func (s *Sandbox) wrap(cmd *exec.Cmd) error {
+	if s.IsZero() {
+		return nil
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.Seccomp != nil {
+		if _, err := s.Seccomp.filter(); err != nil {
+			return err
+		}
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the agent executable: %w", err)
+	}
+
+	spec := sandboxSpec{Sandbox: *s, UID: -1, GID: -1}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+	}
+	if cred := cmd.SysProcAttr.Credential; cred != nil {
+		spec.UID, spec.GID = int(cred.Uid), int(cred.Gid)
+		cmd.SysProcAttr.Credential = nil
+	}
+	for _, ns := range s.Namespaces {
+		cmd.SysProcAttr.Cloneflags |= namespaceFlags[ns]
+	}
+	encoded, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+
+	cmd.Args = append([]string{exe, SandboxCommand, "--", cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = exe
+	cmd.Env = append(cmd.Env, sandboxEnv+"="+string(encoded))
+	return nil
+}
+
+// ExecSandboxed applies the sandbox received from the agent and replaces the current process with the
+// program, args starts with the path of the program. It only returns on failure.
+func ExecSandboxed(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing program to execute")
+	}
+	var spec sandboxSpec
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxEnv)), &spec); err != nil {
+		return fmt.Errorf("invalid sandbox: %w", err)
+	}
+
+	// capabilities, securebits and seccomp filters are attributes of the thread executing the program
+	runtime.LockOSThread()
+
+	if err := setupMounts(spec.Namespaces); err != nil {
+		return err
+	}
+
+	var keep map[int]bool
+	if spec.Capabilities != nil {
+		var err error
+		if keep, err = spec.Capabilities.keep(); err != nil {
+			return err
+		}
+		if err := dropBoundingCapabilities(keep); err != nil {
+			return err
+		}
+		// keep the permitted capabilities when switching to the user of the program
+		if err := unix.Prctl(unix.PR_SET_KEEPCAPS, 1, 0, 0, 0); err != nil {
+			return fmt.Errorf("failed to keep capabilities: %w", err)
+		}
+	}
+	if spec.GID >= 0 {
+		if err := syscall.Setgid(spec.GID); err != nil {
+			return fmt.Errorf("failed to set group %d: %w", spec.GID, err)
+		}
+	}
+	if spec.UID >= 0 {
+		if err := syscall.Setuid(spec.UID); err != nil {
+			return fmt.Errorf("failed to set user %d: %w", spec.UID, err)
+		}
+	}
+	// the parent death signal is reset when the credentials change
+	if err := unix.Prctl(unix.PR_SET_PDEATHSIG, uintptr(syscall.SIGKILL), 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set parent death signal: %w", err)
+	}
+	if keep != nil {
+		if err := setCapabilities(keep); err != nil {
+			return err
+		}
+	}
+	if spec.Seccomp != nil {
+		if err := spec.Seccomp.install(); err != nil {
+			return err
+		}
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, sandboxEnv+"=") {
+			env = append(env, e)
+		}
+	}
+	if err := syscall.Exec(args[0], args, env); err != nil {
+		return fmt.Errorf("failed to execute %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// setupMounts makes the mount points private to the program, /proc is mounted again in a new PID
+// namespace to only list the processes of the namespace.
+func setupMounts(namespaces []Namespace) error {
+	var mount, pid bool
+	for _, ns := range namespaces {
+		mount = mount || ns == MountNamespace
+		pid = pid || ns == PIDNamespace
+	}
+	if !mount {
+		return nil
+	}
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mount points private: %w", err)
+	}
+	if pid {
+		if err := unix.Mount("proc", "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+			return fmt.Errorf("failed to mount /proc: %w", err)
+		}
+	}
+	return nil
+}
+
+// dropBoundingCapabilities removes the capabilities from the bounding set, they cannot be gained
+// anymore, even by executing a program as root.
+func dropBoundingCapabilities(keep map[int]bool) error {
+	for c := 0; c <= unix.CAP_LAST_CAP; c++ {
+		if keep[c] {
+			continue
+		}
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(c), 0, 0, 0); err != nil && err != unix.EINVAL {
+			// EINVAL is returned for the capabilities the kernel does not know about
+			return fmt.Errorf("failed to drop capability %d: %w", c, err)
+		}
+	}
+	return nil
+}
+
+// setCapabilities reduces the capabilities of the thread to the kept capabilities, they are raised in
+// the ambient set to be kept by a program not running as root.
+func setCapabilities(keep map[int]bool) error {
+	var data [2]unix.CapUserData
+	for c := range keep {
+		data[c/32].Effective |= 1 << (uint(c) % 32)
+		data[c/32].Permitted |= 1 << (uint(c) % 32)
+		data[c/32].Inheritable |= 1 << (uint(c) % 32)
+	}
+	hdr := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	if err := unix.Capset(&hdr, &data[0]); err != nil {
+		return fmt.Errorf("failed to set capabilities: %w", err)
+	}
+	if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to clear ambient capabilities: %w", err)
+	}
+	for c := range keep {
+		if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_RAISE, uintptr(c), 0, 0); err != nil {
+			return fmt.Errorf("failed to raise ambient capability %d: %w", c, err)
+		}
+	}
+	return nil
+}
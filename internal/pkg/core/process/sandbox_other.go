@@ -0,0 +1,26 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build !linux
+// +build !linux
+
+package process
+
+import "os/exec"
+
+func (s *Sandbox) wrap(*exec.Cmd) error {
+	if s.IsZero() {
+		return nil
+	}
+	return ErrSandboxUnsupported
+}
+
+func (p *SeccompProfile) validateSyscalls() error {
+	return nil
+}
+
+// ExecSandboxed is only supported on Linux.
+func ExecSandboxed([]string) error {
+	return ErrSandboxUnsupported
+}
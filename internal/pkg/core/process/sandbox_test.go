@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSandboxUnmarshal(t *testing.T) {
+	const content = `
+namespaces: [mount, pid, ipc, uts]
+capabilities:
+  keep: [net_raw, CAP_SYS_PTRACE]
+seccomp:
+  default_action: allow
+  syscalls:
+    - action: errno
+      names: [ptrace, mount]
+`
+	var sandbox Sandbox
+	require.NoError(t, yaml.Unmarshal([]byte(content), &sandbox))
+	require.NoError(t, sandbox.Validate())
+	assert.Equal(t, []Namespace{MountNamespace, PIDNamespace, IPCNamespace, UTSNamespace}, sandbox.Namespaces)
+	assert.False(t, sandbox.IsZero())
+
+	keep, err := sandbox.Capabilities.keep()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]bool{13: true, 19: true}, keep)
+	assert.Equal(t, SeccompErrno, sandbox.Seccomp.Syscalls[0].Action)
+}
+
+func TestSandboxValidate(t *testing.T) {
+	var noSandbox *Sandbox
+	assert.True(t, noSandbox.IsZero())
+	assert.NoError(t, noSandbox.Validate())
+	assert.True(t, (&Sandbox{}).IsZero())
+
+	tests := map[string]struct {
+		sandbox Sandbox
+		err     string
+	}{
+		"unknown namespace": {
+			sandbox: Sandbox{Namespaces: []Namespace{"net"}},
+			err:     `unknown namespace "net"`,
+		},
+		"unknown capability": {
+			sandbox: Sandbox{Capabilities: &Capabilities{Keep: []string{"net_magic"}}},
+			err:     `unknown capability "net_magic"`,
+		},
+		"unknown default action": {
+			sandbox: Sandbox{Seccomp: &SeccompProfile{DefaultAction: "deny"}},
+			err:     `invalid seccomp default_action: unknown action "deny"`,
+		},
+		"missing action": {
+			sandbox: Sandbox{Seccomp: &SeccompProfile{Syscalls: []SeccompRule{{Names: []string{"ptrace"}}}}},
+			err:     "missing action of seccomp rule for ptrace",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, tc.sandbox.Validate(), tc.err)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+//go:build linux && (386 || amd64 || arm || arm64)
+// +build linux
+// +build 386 amd64 arm arm64
+
+package process
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestSeccompProfileFilter(t *testing.T) {
+	profile := SeccompProfile{
+		DefaultAction: SeccompAllow,
+		Syscalls: []SeccompRule{
+			{Action: SeccompErrno, Names: []string{"ptrace", "mount"}},
+			{Action: SeccompKill, Names: []string{"ptrace", "kexec_load"}},
+		},
+	}
+	filter, err := profile.filter()
+	require.NoError(t, err)
+
+	header := 4
+	if runtime.GOARCH == "amd64" {
+		header += 2
+	}
+	// ptrace is only filtered by its first rule
+	require.Len(t, filter, header+3*2+1)
+	rules := filter[header:]
+	assert.Equal(t, uint32(unix.SYS_PTRACE), rules[0].K)
+	assert.Equal(t, uint32(seccompRetErrno|uint32(unix.EPERM)), rules[1].K)
+	assert.Equal(t, uint32(unix.SYS_MOUNT), rules[2].K)
+	assert.Equal(t, uint32(unix.SYS_KEXEC_LOAD), rules[4].K)
+	assert.Equal(t, uint32(seccompRetKillProcess), rules[5].K)
+	assert.Equal(t, uint32(seccompRetAllow), rules[6].K)
+
+	_, err = (&SeccompProfile{Syscalls: []SeccompRule{{Action: SeccompKill, Names: []string{"fly"}}}}).filter()
+	assert.EqualError(t, err, `unknown system call "fly" in seccomp profile`)
+
+	// unknown system calls are rejected when the sandbox is validated
+	sandbox := &Sandbox{Seccomp: &SeccompProfile{Syscalls: []SeccompRule{{Action: SeccompKill, Names: []string{"fly"}}}}}
+	assert.EqualError(t, sandbox.Validate(), `unknown system call "fly" in seccomp profile`)
+}
+
+func TestSandboxWrap(t *testing.T) {
+	cmd := exec.Command("/usr/bin/filebeat", "-e")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Pdeathsig:  syscall.SIGKILL,
+		Credential: &syscall.Credential{Uid: 1000, Gid: 1000, NoSetGroups: true},
+	}
+	sandbox := &Sandbox{Namespaces: []Namespace{PIDNamespace, UTSNamespace}}
+	require.NoError(t, sandbox.wrap(cmd))
+
+	assert.Equal(t, []string{SandboxCommand, "--", "/usr/bin/filebeat", "-e"}, cmd.Args[1:])
+	assert.Equal(t, cmd.Path, cmd.Args[0])
+	assert.Nil(t, cmd.SysProcAttr.Credential)
+	assert.Equal(t, uintptr(syscall.CLONE_NEWPID|syscall.CLONE_NEWUTS), cmd.SysProcAttr.Cloneflags)
+
+	env := cmd.Env[len(cmd.Env)-1]
+	require.True(t, strings.HasPrefix(env, sandboxEnv+"="))
+	assert.JSONEq(t, `{"namespaces":["pid","uts"],"uid":1000,"gid":1000}`, strings.TrimPrefix(env, sandboxEnv+"="))
+
+	unchanged := exec.Command("/usr/bin/filebeat")
+	require.NoError(t, (*Sandbox)(nil).wrap(unchanged))
+	assert.Equal(t, "/usr/bin/filebeat", unchanged.Path)
+}
@@ -5,7 +5,10 @@
 package status
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/google/uuid"
@@ -175,11 +178,28 @@ func (r *controller) Status() AgentStatus {
 	}
 	return AgentStatus{
 		Status:       r.status,
-		Message:      "",
+		Message:      r.componentsMessage(),
 		Applications: apps,
 	}
 }
 
+// componentsMessage returns the messages of the components that are not healthy, components are not
+// part of the applications so this is the only place their problems are visible.
+//
+// This does not grab the controller lock, that must be managed by the caller.
+func (r *controller) componentsMessage() string {
+	messages := make([]string, 0)
+	for _, rep := range r.reporters {
+		rep.mx.Lock()
+		if statusToAgentStatus(rep.status) != Healthy && rep.message != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", rep.name, rep.message))
+		}
+		rep.mx.Unlock()
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
 // StatusCode retrieves current agent status code.
 func (r *controller) StatusCode() AgentStatusCode {
 	r.mx.Lock()
@@ -192,7 +212,7 @@ func (r *controller) updateStatus() {
 
 	r.mx.Lock()
 	for id, rep := range r.reporters {
-		s := statusToAgentStatus(rep.status)
+		s := statusToAgentStatus(rep.currentStatus())
 		if s > status {
 			status = s
 		}
@@ -204,7 +224,7 @@ func (r *controller) updateStatus() {
 	}
 	if status != Failed {
 		for id, rep := range r.appReporters {
-			s := statusToAgentStatus(rep.status)
+			s := statusToAgentStatus(rep.currentStatus())
 			if s > status {
 				status = s
 			}
@@ -262,30 +282,42 @@ type reporter struct {
 // Update updates the status of a component.
 func (r *reporter) Update(s state.Status, message string, payload map[string]interface{}) {
 	r.mx.Lock()
-	defer r.mx.Unlock()
-
 	if !r.isRegistered {
+		r.mx.Unlock()
 		return
 	}
 	if state.IsStateFiltered(message, payload) {
+		r.mx.Unlock()
 		return
 	}
 
-	if r.status != s || r.message != message || !reflect.DeepEqual(r.payload, payload) {
+	changed := r.status != s || r.message != message || !reflect.DeepEqual(r.payload, payload)
+	if changed {
 		r.status = s
 		r.message = message
 		r.payload = payload
+	}
+	r.mx.Unlock()
+
+	// the controller is notified without the lock, it reads the reporters under its own lock
+	if changed {
 		r.notifyChangeFunc()
 	}
 }
 
+func (r *reporter) currentStatus() state.Status {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	return r.status
+}
+
 // Unregister unregisters status from reporter. Reporter will no longer be taken into consideration
 // for overall status computation.
 func (r *reporter) Unregister() {
 	r.mx.Lock()
-	defer r.mx.Unlock()
-
 	r.isRegistered = false
+	r.mx.Unlock()
+
 	r.unregisterFunc()
 	r.notifyChangeFunc()
 }
@@ -5,6 +5,8 @@
 package status
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -53,6 +55,7 @@ func TestReporter(t *testing.T) {
 
 		assert.Equal(t, Degraded, r.StatusCode())
 		assert.Equal(t, "degraded", r.StatusString())
+		assert.Equal(t, "r2: degraded", r.Status().Message)
 	})
 
 	t.Run("failed when one failed", func(t *testing.T) {
@@ -97,5 +100,31 @@ func TestReporter(t *testing.T) {
 
 		assert.Equal(t, Degraded, r.StatusCode())
 		assert.Equal(t, "degraded", r.StatusString())
+		assert.Equal(t, "r3: degraded", r.Status().Message)
+
+		r3.Update(state.Healthy, "", nil)
+		assert.Equal(t, "", r.Status().Message)
+	})
+
+	t.Run("status while components update", func(t *testing.T) {
+		r := NewController(l)
+		r1 := r.RegisterComponent("r1")
+		a1 := r.RegisterApp("app-1", "app")
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				r1.Update(state.Degraded, fmt.Sprintf("degraded %d", i), nil)
+				a1.Update(state.Healthy, fmt.Sprintf("running %d", i), nil)
+			}
+		}()
+		for i := 0; i < 100; i++ {
+			_ = r.Status()
+		}
+		wg.Wait()
+
+		assert.Equal(t, "r1: degraded 99", r.Status().Message)
 	})
 }
@@ -0,0 +1,348 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eql
+
+import (
+	"fmt"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+
+	"github.com/elastic/elastic-agent/internal/pkg/eql/parser"
+)
+
+// Analysis is the result of the static analysis of an expression. The analysis is done without a
+// variable store, the type of a variable is only known when the variable falls back to a constant.
+type Analysis struct {
+	// Variables are the names of the variables referenced by the expression, in order of appearance.
+	Variables []string
+	// UnknownFunctions are the functions called by the expression that are not defined.
+	UnknownFunctions []string
+	// TypeErrors describes the operations receiving operands of the wrong type.
+	TypeErrors []string
+}
+
+// Warnings returns a message for every problem found in the expression, variables are not
+// considered a problem.
+func (a *Analysis) Warnings() []string {
+	var warnings []string
+	for _, name := range a.UnknownFunctions {
+		warnings = append(warnings, fmt.Sprintf("call to unknown function %s", name))
+	}
+	return append(warnings, a.TypeErrors...)
+}
+
+// SyntaxError is returned by Analyze when the expression cannot be parsed.
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Analyze parses the expression and reports the variables it references, the unknown functions it
+// calls and the operations that fail whatever the values of the variables are. Unlike New, an error
+// is returned when the expression is not syntactically valid.
+func Analyze(expression string) (analysis *Analysis, err error) {
+	if len(expression) == 0 {
+		return nil, ErrEmptyExpression
+	}
+
+	// Antlr can panic on errors so we have to recover somehow.
+	defer func() {
+		r := recover()
+		if r != nil {
+			analysis = nil
+			err = fmt.Errorf("error in while parsing the expression %s, error %+v", expression, r)
+		}
+	}()
+
+	listener := &syntaxErrorListener{DefaultErrorListener: antlr.NewDefaultErrorListener()}
+	input := antlr.NewInputStream(expression)
+	lexer := parser.NewEqlLexer(input)
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(listener)
+	tokens := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	p := parser.NewEqlParser(tokens)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(listener)
+	tree := p.ExpList()
+
+	if listener.err != nil {
+		return nil, listener.err
+	}
+
+	visitor := &analyzeVisitor{
+		analysis: &Analysis{},
+		seen:     make(map[string]bool),
+		unknown:  make(map[string]bool),
+	}
+	tree.Accept(visitor)
+	return visitor.analysis, nil
+}
+
+// syntaxErrorListener keeps the first syntax error reported by the lexer or the parser.
+type syntaxErrorListener struct {
+	*antlr.DefaultErrorListener
+	err *SyntaxError
+}
+
+func (l *syntaxErrorListener) SyntaxError(_ antlr.Recognizer, _ interface{}, line, column int, msg string, _ antlr.RecognitionException) {
+	if l.err == nil {
+		l.err = &SyntaxError{Line: line, Column: column, Message: msg}
+	}
+}
+
+// kind is the type of a value as known before the evaluation.
+type kind int
+
+const (
+	kindUnknown kind = iota
+	kindBool
+	kindNumber
+	kindString
+	kindArray
+	kindDict
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindBool:
+		return "a boolean"
+	case kindNumber:
+		return "a number"
+	case kindString:
+		return "a string"
+	case kindArray:
+		return "an array"
+	case kindDict:
+		return "a dictionary"
+	default:
+		return "unknown"
+	}
+}
+
+// analyzeVisitor walks the parse tree and returns the kind of every visited expression.
+type analyzeVisitor struct {
+	antlr.ParseTreeVisitor
+	analysis *Analysis
+	seen     map[string]bool
+	unknown  map[string]bool
+}
+
+func (v *analyzeVisitor) typeError(format string, args ...interface{}) {
+	v.analysis.TypeErrors = append(v.analysis.TypeErrors, fmt.Sprintf(format, args...))
+}
+
+// expect reports the operands of an operator that are known not to be of the expected kind.
+func (v *analyzeVisitor) expect(operator string, expected kind, operands ...kind) {
+	for _, k := range operands {
+		if k != kindUnknown && k != expected {
+			v.typeError("operator %s expects %s, received %s", operator, expected, k)
+		}
+	}
+}
+
+func (v *analyzeVisitor) compare(operator string, left, right kind) kind {
+	if left != kindUnknown && right != kindUnknown && left != right {
+		v.typeError("operator %s compares %s with %s", operator, left, right)
+	}
+	return kindBool
+}
+
+func (v *analyzeVisitor) order(operator string, left, right kind) kind {
+	v.expect(operator, kindNumber, left, right)
+	return kindBool
+}
+
+func (v *analyzeVisitor) VisitExpList(ctx *parser.ExpListContext) interface{} {
+	k := ctx.Exp().Accept(v).(kind)
+	if k != kindUnknown && k != kindBool {
+		v.typeError("expression must evaluate to a boolean, received %s", k)
+	}
+	return k
+}
+
+func (v *analyzeVisitor) VisitBoolean(_ *parser.BooleanContext) interface{} {
+	return kindBool
+}
+
+func (v *analyzeVisitor) VisitConstant(ctx *parser.ConstantContext) interface{} {
+	switch {
+	case ctx.STEXT() != nil, ctx.DTEXT() != nil:
+		return kindString
+	case ctx.FLOAT() != nil, ctx.NUMBER() != nil:
+		return kindNumber
+	case ctx.Boolean() != nil:
+		return kindBool
+	}
+	return kindUnknown
+}
+
+func (v *analyzeVisitor) VisitVariable(ctx *parser.VariableContext) interface{} {
+	if ctx.Constant() != nil {
+		return ctx.Constant().Accept(v)
+	}
+
+	var name string
+	if ctx.NAME() != nil {
+		name = ctx.NAME().GetText()
+	} else if ctx.VNAME() != nil {
+		name = ctx.VNAME().GetText()
+	}
+	if !v.seen[name] {
+		v.seen[name] = true
+		v.analysis.Variables = append(v.analysis.Variables, name)
+	}
+	return kindUnknown
+}
+
+func (v *analyzeVisitor) VisitVariableExp(ctx *parser.VariableExpContext) interface{} {
+	// the first entry resolving to a value wins, only a leading constant gives a known kind.
+	k := kindUnknown
+	for i, entry := range ctx.AllVariable() {
+		r := entry.Accept(v).(kind)
+		if i == 0 {
+			k = r
+		}
+	}
+	return k
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticNEQ(ctx *parser.ExpArithmeticNEQContext) interface{} {
+	return v.compare("!=", ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticEQ(ctx *parser.ExpArithmeticEQContext) interface{} {
+	return v.compare("==", ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticGTE(ctx *parser.ExpArithmeticGTEContext) interface{} {
+	return v.order(">=", ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticLTE(ctx *parser.ExpArithmeticLTEContext) interface{} {
+	return v.order("<=", ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticGT(ctx *parser.ExpArithmeticGTContext) interface{} {
+	return v.order(">", ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticLT(ctx *parser.ExpArithmeticLTContext) interface{} {
+	return v.order("<", ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticMulDivMod(ctx *parser.ExpArithmeticMulDivModContext) interface{} {
+	operator := "%"
+	if ctx.MUL() != nil {
+		operator = "*"
+	} else if ctx.DIV() != nil {
+		operator = "/"
+	}
+	v.expect(operator, kindNumber, ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+	return kindNumber
+}
+
+func (v *analyzeVisitor) VisitExpArithmeticAddSub(ctx *parser.ExpArithmeticAddSubContext) interface{} {
+	operator := "-"
+	if ctx.ADD() != nil {
+		operator = "+"
+	}
+	v.expect(operator, kindNumber, ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+	return kindNumber
+}
+
+func (v *analyzeVisitor) VisitExpDict(_ *parser.ExpDictContext) interface{} {
+	return kindDict
+}
+
+func (v *analyzeVisitor) VisitExpText(_ *parser.ExpTextContext) interface{} {
+	return kindString
+}
+
+func (v *analyzeVisitor) VisitExpNumber(_ *parser.ExpNumberContext) interface{} {
+	return kindNumber
+}
+
+func (v *analyzeVisitor) VisitExpFloat(_ *parser.ExpFloatContext) interface{} {
+	return kindNumber
+}
+
+func (v *analyzeVisitor) VisitExpLogicalAnd(ctx *parser.ExpLogicalAndContext) interface{} {
+	v.expect("and", kindBool, ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+	return kindBool
+}
+
+func (v *analyzeVisitor) VisitExpLogicalOR(ctx *parser.ExpLogicalORContext) interface{} {
+	v.expect("or", kindBool, ctx.GetLeft().Accept(v).(kind), ctx.GetRight().Accept(v).(kind))
+	return kindBool
+}
+
+func (v *analyzeVisitor) VisitExpVariable(ctx *parser.ExpVariableContext) interface{} {
+	if ctx.VariableExp() != nil {
+		return ctx.VariableExp().Accept(v)
+	}
+	return kindUnknown
+}
+
+func (v *analyzeVisitor) VisitExpArray(_ *parser.ExpArrayContext) interface{} {
+	return kindArray
+}
+
+func (v *analyzeVisitor) VisitExpNot(ctx *parser.ExpNotContext) interface{} {
+	v.expect("not", kindBool, ctx.Exp().Accept(v).(kind))
+	return kindBool
+}
+
+func (v *analyzeVisitor) VisitExpInParen(ctx *parser.ExpInParenContext) interface{} {
+	return ctx.Exp().Accept(v)
+}
+
+func (v *analyzeVisitor) VisitExpBoolean(_ *parser.ExpBooleanContext) interface{} {
+	return kindBool
+}
+
+func (v *analyzeVisitor) VisitExpFunction(ctx *parser.ExpFunctionContext) interface{} {
+	if ctx.Arguments() != nil {
+		ctx.Arguments().Accept(v)
+	}
+
+	name := ctx.NAME().GetText()
+	method, ok := methods[name]
+	if !ok {
+		if !v.unknown[name] {
+			v.unknown[name] = true
+			v.analysis.UnknownFunctions = append(v.analysis.UnknownFunctions, name)
+		}
+		return kindUnknown
+	}
+	return method.returns
+}
+
+func (v *analyzeVisitor) VisitArguments(ctx *parser.ArgumentsContext) interface{} {
+	kinds := make([]kind, 0, len(ctx.AllExp()))
+	for _, val := range ctx.AllExp() {
+		kinds = append(kinds, val.Accept(v).(kind))
+	}
+	return kinds
+}
+
+func (v *analyzeVisitor) VisitArray(_ *parser.ArrayContext) interface{} {
+	return kindArray
+}
+
+func (v *analyzeVisitor) VisitKey(_ *parser.KeyContext) interface{} {
+	return kindUnknown
+}
+
+func (v *analyzeVisitor) VisitDict(_ *parser.DictContext) interface{} {
+	return kindDict
+}
+
+// ensure interface is implemented
+var _ parser.EqlVisitor = (*analyzeVisitor)(nil)
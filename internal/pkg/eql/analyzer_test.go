@@ -0,0 +1,113 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eql
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	testcases := []struct {
+		expression       string
+		variables        []string
+		unknownFunctions []string
+		typeErrors       []string
+	}{
+		{expression: "true"},
+		{expression: "${host.name} == 'mac'", variables: []string{"host.name"}},
+		{
+			expression: "${kubernetes.namespace|host.name|'default'} == 'default' and ${host.name} != ''",
+			variables:  []string{"kubernetes.namespace", "host.name"},
+		},
+		{expression: "${'value'} == 'value'"},
+		{
+			expression: "startsWith(${host.name}, 'db') and versionAtLeast(${agent.version}, '8.0.0')",
+			variables:  []string{"host.name", "agent.version"},
+		},
+		{expression: "length(${host.ip}) > 1 + 2 * 3", variables: []string{"host.ip"}},
+		{
+			expression:       "isLinux(${host.platform}) or isLinux()",
+			variables:        []string{"host.platform"},
+			unknownFunctions: []string{"isLinux"},
+		},
+		{
+			expression: "${host.cpus} > 'four'",
+			variables:  []string{"host.cpus"},
+			typeErrors: []string{"operator > expects a number, received a string"},
+		},
+		{
+			expression: "'a' + 1 == 2",
+			typeErrors: []string{"operator + expects a number, received a string"},
+		},
+		{
+			expression: "hour() == '10'",
+			typeErrors: []string{"operator == compares a number with a string"},
+		},
+		{
+			expression: "1 == 1.5",
+		},
+		{
+			expression: "[1, 2] != {a: 1}",
+			typeErrors: []string{"operator != compares an array with a dictionary"},
+		},
+		{
+			expression: "true and 'yes' or not 1",
+			typeErrors: []string{
+				"operator and expects a boolean, received a string",
+				"operator not expects a boolean, received a number",
+			},
+		},
+		{
+			expression: "concat('a', 'b')",
+			typeErrors: []string{"expression must evaluate to a boolean, received a string"},
+		},
+		{expression: "${host.name}", variables: []string{"host.name"}},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			analysis, err := Analyze(test.expression)
+			require.NoError(t, err)
+			assert.Equal(t, test.variables, analysis.Variables)
+			assert.Equal(t, test.unknownFunctions, analysis.UnknownFunctions)
+			assert.Equal(t, test.typeErrors, analysis.TypeErrors)
+		})
+	}
+}
+
+func TestAnalyzeWarnings(t *testing.T) {
+	analysis, err := Analyze("unknown() and 'a' > 1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"call to unknown function unknown",
+		"operator > expects a number, received a string",
+	}, analysis.Warnings())
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	_, err := Analyze("")
+	assert.Equal(t, ErrEmptyExpression, err)
+
+	for _, expression := range []string{"${host.name} ==", "(true", "true true", "${host.name == 'a'"} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := Analyze(expression)
+			var syntaxErr *SyntaxError
+			require.True(t, errors.As(err, &syntaxErr), "unexpected error %v", err)
+			assert.Equal(t, 1, syntaxErr.Line)
+		})
+	}
+}
+
+func TestAnalyzeMethodKinds(t *testing.T) {
+	for name, method := range methods {
+		assert.NotNil(t, method.call, "missing function of method %s", name)
+		assert.NotEqual(t, kindUnknown, method.returns, "missing return kind of method %s", name)
+	}
+}
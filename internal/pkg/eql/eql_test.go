@@ -5,9 +5,11 @@
 package eql
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/antlr/antlr4/runtime/Go/antlr"
 	"github.com/stretchr/testify/assert"
@@ -298,6 +300,59 @@ func TestEql(t *testing.T) {
 		{expression: "stringContains(0, 'o w', 'too many')", err: true},
 		{expression: "stringContains('hello world', 0)", result: false},
 
+		// net
+		{expression: "cidrMatch('10.1.2.3', '10.0.0.0/8')", result: true},
+		{expression: "cidrMatch('192.168.1.3', '10.0.0.0/8')", result: false},
+		{expression: "cidrMatch('192.168.1.3', '10.0.0.0/8', '192.168.0.0/16')", result: true},
+		{expression: "cidrMatch('2001:db8::1', '2001:db8::/32')", result: true},
+		{expression: "cidrMatch('10.1.2.3', '2001:db8::/32')", result: false},
+		{expression: "cidrMatch(${host.ip}, '172.16.0.0/12')", result: true},
+		{expression: "cidrMatch(${host.ip}, '10.0.0.0/8')", result: false},
+		{expression: "cidrMatch(${missing}, '10.0.0.0/8')", result: false},
+		{expression: "cidrMatch('10.1.2.3')", err: true},
+		{expression: "cidrMatch('10.1.2.3', '10.0.0.0')", err: true},
+		{expression: "cidrMatch('not an ip', '10.0.0.0/8')", err: true},
+		{expression: "cidrMatch(10, '10.0.0.0/8')", err: true},
+		{expression: "cidrMatch('10.1.2.3', 8)", err: true},
+
+		// time
+		{expression: "now() > date('2020-01-01')", result: true},
+		{expression: "now() < date('2020-01-01T00:00:00Z')", result: false},
+		{expression: "date('2020-01-02') - date('2020-01-01') == 86400", result: true},
+		{expression: "date('2020-01-01T01:00:00+01:00') == date('2020-01-01')", result: true},
+		{expression: "date('01/01/2020') == 0", err: true},
+		{expression: "date(2020) == 0", err: true},
+		{expression: "now('too many') > 0", err: true},
+		{expression: "weekday() >= 1 and weekday() <= 7", result: true},
+		{expression: "weekday('Invalid/Zone') == 1", err: true},
+		{expression: "hour('UTC') >= 0 and hour('UTC') < 24", result: true},
+		{expression: "hour('UTC', 'too many') == 0", err: true},
+		{expression: "timeBetween('00:00', '00:00') or true", result: true},
+		{expression: "timeBetween('25:00', '08:00')", err: true},
+		{expression: "timeBetween('08:00')", err: true},
+
+		// version
+		{expression: "semverCompare('8.2.0', '8.2.0') == 0", result: true},
+		{expression: "semverCompare('8.2.1', '8.2.0') == 1", result: true},
+		{expression: "semverCompare('8.10.0', '8.2.0') == 1", result: true},
+		{expression: "semverCompare('7.17.3', '8.0.0') == -1", result: true},
+		{expression: "semverCompare('8.2', '8.2.0') == 0", result: true},
+		{expression: "semverCompare('v8.2.0', '8.2.0+build.1') == 0", result: true},
+		{expression: "semverCompare('8.2.0-SNAPSHOT', '8.2.0') == -1", result: true},
+		{expression: "semverCompare('8.2.0-alpha.1', '8.2.0-alpha.beta') == -1", result: true},
+		{expression: "semverCompare('8.2.0-alpha.2', '8.2.0-alpha.10') == -1", result: true},
+		{expression: "semverCompare('8.2.0-alpha', '8.2.0-alpha.1') == -1", result: true},
+		{expression: "semverCompare('8.2.0-rc.1', '8.2.0-beta.2') == 1", result: true},
+		{expression: "semverCompare('8.2.x', '8.2.0') == 0", err: true},
+		{expression: "semverCompare('8.2.0.1', '8.2.0') == 0", err: true},
+		{expression: "semverCompare('8.2.0') == 0", err: true},
+		{expression: "versionAtLeast(${agent.version}, '8.2')", result: true},
+		{expression: "versionAtLeast(${agent.version}, '8.3.0')", result: false},
+		{expression: "versionAtLeast('8.2.0-SNAPSHOT', '8.2.0')", result: false},
+		{expression: "versionAtLeast(${missing}, '8.2.0')", result: false},
+		{expression: "versionAtLeast(8, '8.2.0')", err: true},
+		{expression: "versionAtLeast('8.2.0', 'latest')", err: true},
+
 		// Bad expression and malformed expression
 		{expression: "length('hello')", err: true},
 		{expression: "length()", err: true},
@@ -306,9 +361,11 @@ func TestEql(t *testing.T) {
 
 	store := &testVarStore{
 		vars: map[string]interface{}{
-			"env.HOSTNAME": "my-hostname",
-			"host.name":    "host-name",
-			"data.array":   []interface{}{"array1", "array2", "array3"},
+			"env.HOSTNAME":  "my-hostname",
+			"host.name":     "host-name",
+			"host.ip":       []interface{}{"127.0.0.1/8", "172.17.0.2/16", "fe80::1/64"},
+			"agent.version": "8.2.1",
+			"data.array":    []interface{}{"array1", "array2", "array3"},
 			"data.dict": map[string]interface{}{
 				"key1": "dict1",
 				"key2": "dict2",
@@ -343,6 +400,72 @@ func TestEql(t *testing.T) {
 	}
 }
 
+func TestEqlTime(t *testing.T) {
+	defer func() {
+		timeNow = time.Now
+	}()
+	// Saturday 2022-05-14 23:30 UTC
+	timeNow = func() time.Time {
+		return time.Date(2022, time.May, 14, 23, 30, 0, 0, time.UTC)
+	}
+
+	testcases := []struct {
+		expression string
+		result     bool
+	}{
+		{expression: "now() == date('2022-05-14T23:30:00Z')", result: true},
+		{expression: "weekday() == 6", result: true},
+		{expression: "weekday() <= 5", result: false},
+		{expression: "weekday('Asia/Tokyo') == 7", result: true},
+		{expression: "hour() == 23", result: true},
+		{expression: "hour('Europe/Paris') == 1", result: true},
+		{expression: "timeBetween('22:00', '23:59')", result: true},
+		{expression: "timeBetween('08:00', '18:00')", result: false},
+		{expression: "timeBetween('22:00', '06:00')", result: true},
+		{expression: "timeBetween('23:30', '23:31')", result: true},
+		{expression: "timeBetween('23:00', '23:30')", result: false},
+		{expression: "timeBetween('08:00', '18:00', 'Asia/Tokyo')", result: true},
+	}
+
+	for _, test := range testcases {
+		test := test
+		t.Run(fmt.Sprintf("%s => return %v", test.expression, test.result), func(t *testing.T) {
+			r, err := Eval(test.expression, nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.result, r)
+		})
+	}
+}
+
+func TestEqlMethodsErrors(t *testing.T) {
+	t.Run("argument count", func(t *testing.T) {
+		_, err := Eval("cidrMatch('10.0.0.1')", nil)
+		var countErr *ArgumentCountError
+		require.True(t, errors.As(err, &countErr), "unexpected error %v", err)
+		assert.Equal(t, "cidrMatch", countErr.Method)
+		assert.Equal(t, 1, countErr.Received)
+		assert.EqualError(t, err, "cidrMatch: accepts minimum of 2 arguments; received 1")
+	})
+
+	t.Run("argument type", func(t *testing.T) {
+		_, err := Eval("semverCompare('8.2.0', 8)", nil)
+		var typeErr *ArgumentTypeError
+		require.True(t, errors.As(err, &typeErr), "unexpected error %v", err)
+		assert.Equal(t, "semverCompare", typeErr.Method)
+		assert.Equal(t, 1, typeErr.Position)
+		assert.EqualError(t, err, "semverCompare: argument 1 must be a string; received int")
+	})
+
+	t.Run("argument value", func(t *testing.T) {
+		_, err := Eval("weekday('Invalid/Zone') == 1", nil)
+		var valueErr *ArgumentValueError
+		require.True(t, errors.As(err, &valueErr), "unexpected error %v", err)
+		assert.Equal(t, "weekday", valueErr.Method)
+		assert.Equal(t, "Invalid/Zone", valueErr.Value)
+		assert.Error(t, errors.Unwrap(valueErr))
+	})
+}
+
 func debug(expression string) {
 	raw := antlr.NewInputStream(expression)
 
@@ -8,31 +8,51 @@ package eql
 // of doing the type conversion and allow checking the arity of the function.
 type callFunc func(args []interface{}) (interface{}, error)
 
+// method is a function enabled in EQL along the kind of the value it returns, used by the analyzer.
+type method struct {
+	call    callFunc
+	returns kind
+}
+
 // methods are the methods enabled in EQL.
-var methods = map[string]callFunc{
+var methods = map[string]method{
 	// array
-	"arrayContains": arrayContains,
+	"arrayContains": {arrayContains, kindBool},
 
 	// dict
-	"hasKey": hasKey,
+	"hasKey": {hasKey, kindBool},
 
 	// length:
-	"length": length,
+	"length": {length, kindNumber},
+
+	// net
+	"cidrMatch": {cidrMatch, kindBool},
 
 	// math
-	"add":      add,
-	"subtract": subtract,
-	"multiply": multiply,
-	"divide":   divide,
-	"modulo":   modulo,
+	"add":      {add, kindNumber},
+	"subtract": {subtract, kindNumber},
+	"multiply": {multiply, kindNumber},
+	"divide":   {divide, kindNumber},
+	"modulo":   {modulo, kindNumber},
 
 	// str
-	"concat":         concat,
-	"endsWith":       endsWith,
-	"indexOf":        indexOf,
-	"match":          match,
-	"number":         number,
-	"startsWith":     startsWith,
-	"string":         str,
-	"stringContains": stringContains,
+	"concat":         {concat, kindString},
+	"endsWith":       {endsWith, kindBool},
+	"indexOf":        {indexOf, kindNumber},
+	"match":          {match, kindBool},
+	"number":         {number, kindNumber},
+	"startsWith":     {startsWith, kindBool},
+	"string":         {str, kindString},
+	"stringContains": {stringContains, kindBool},
+
+	// time
+	"date":        {date, kindNumber},
+	"hour":        {hour, kindNumber},
+	"now":         {now, kindNumber},
+	"timeBetween": {timeBetween, kindBool},
+	"weekday":     {weekday, kindNumber},
+
+	// version
+	"semverCompare":  {semverCompare, kindNumber},
+	"versionAtLeast": {versionAtLeast, kindBool},
 }
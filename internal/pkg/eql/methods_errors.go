@@ -0,0 +1,57 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eql
+
+import "fmt"
+
+// ArgumentCountError is returned when a method is called with an unexpected number of arguments.
+type ArgumentCountError struct {
+	Method   string
+	Expected string
+	Received int
+}
+
+func (e *ArgumentCountError) Error() string {
+	return fmt.Sprintf("%s: accepts %s; received %d", e.Method, e.Expected, e.Received)
+}
+
+// ArgumentTypeError is returned when an argument of a method is not of the expected type.
+type ArgumentTypeError struct {
+	Method   string
+	Position int
+	Expected string
+	Received interface{}
+}
+
+func (e *ArgumentTypeError) Error() string {
+	return fmt.Sprintf("%s: argument %d must be %s; received %T", e.Method, e.Position, e.Expected, e.Received)
+}
+
+// ArgumentValueError is returned when an argument of a method has the expected type but its value
+// cannot be interpreted, like a malformed IP address or version.
+type ArgumentValueError struct {
+	Method   string
+	Position int
+	Value    string
+	Err      error
+}
+
+func (e *ArgumentValueError) Error() string {
+	return fmt.Sprintf("%s: argument %d '%s' is invalid: %s", e.Method, e.Position, e.Value, e.Err)
+}
+
+// Unwrap returns the error describing why the value is invalid.
+func (e *ArgumentValueError) Unwrap() error {
+	return e.Err
+}
+
+// stringArg returns the argument at the position as a string.
+func stringArg(method string, args []interface{}, position int) (string, error) {
+	s, ok := args[position].(string)
+	if !ok {
+		return "", &ArgumentTypeError{Method: method, Position: position, Expected: "a string", Received: args[position]}
+	}
+	return s, nil
+}
@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eql
+
+import (
+	"fmt"
+	"net"
+)
+
+// cidrMatch returns true if the IP address, or any of the addresses of an array, is part of any
+// of the provided CIDR blocks. Addresses can be in CIDR notation as reported for `${host.ip}`.
+func cidrMatch(args []interface{}) (interface{}, error) {
+	if len(args) < 2 {
+		return nil, &ArgumentCountError{Method: "cidrMatch", Expected: "minimum of 2 arguments", Received: len(args)}
+	}
+
+	networks := make([]*net.IPNet, 0, len(args)-1)
+	for i := range args[1:] {
+		block, err := stringArg("cidrMatch", args, i+1)
+		if err != nil {
+			return nil, err
+		}
+		_, network, err := net.ParseCIDR(block)
+		if err != nil {
+			return nil, &ArgumentValueError{Method: "cidrMatch", Position: i + 1, Value: block, Err: err}
+		}
+		networks = append(networks, network)
+	}
+
+	var addresses []interface{}
+	switch a := args[0].(type) {
+	case *null:
+		return false, nil
+	case string:
+		addresses = []interface{}{a}
+	case []interface{}:
+		addresses = a
+	default:
+		return nil, &ArgumentTypeError{Method: "cidrMatch", Position: 0, Expected: "a string or an array", Received: args[0]}
+	}
+
+	for _, address := range addresses {
+		s, ok := address.(string)
+		if !ok {
+			return nil, &ArgumentTypeError{Method: "cidrMatch", Position: 0, Expected: "an array of strings", Received: address}
+		}
+		ip := parseIP(s)
+		if ip == nil {
+			return nil, &ArgumentValueError{Method: "cidrMatch", Position: 0, Value: s, Err: fmt.Errorf("not an IP address")}
+		}
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func parseIP(s string) net.IP {
+	if ip := net.ParseIP(s); ip != nil {
+		return ip
+	}
+	ip, _, err := net.ParseCIDR(s)
+	if err != nil {
+		return nil
+	}
+	return ip
+}
@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eql
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeNow returns the current time, replaced in tests.
+var timeNow = time.Now
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// now returns the current time as seconds since the epoch
+func now(args []interface{}) (interface{}, error) {
+	if len(args) != 0 {
+		return nil, &ArgumentCountError{Method: "now", Expected: "no arguments", Received: len(args)}
+	}
+	return int(timeNow().Unix()), nil
+}
+
+// date converts a date into seconds since the epoch, dates without timezone are in UTC
+func date(args []interface{}) (interface{}, error) {
+	if len(args) != 1 {
+		return nil, &ArgumentCountError{Method: "date", Expected: "exactly 1 argument", Received: len(args)}
+	}
+	s, err := stringArg("date", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return int(t.Unix()), nil
+		}
+	}
+	return nil, &ArgumentValueError{Method: "date", Position: 0, Value: s, Err: fmt.Errorf("expecting RFC3339 or YYYY-MM-DD format")}
+}
+
+// weekday returns the current day of the week, from 1 for Monday to 7 for Sunday
+func weekday(args []interface{}) (interface{}, error) {
+	if len(args) > 1 {
+		return nil, &ArgumentCountError{Method: "weekday", Expected: "0-1 arguments", Received: len(args)}
+	}
+	t, err := localNow("weekday", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if t.Weekday() == time.Sunday {
+		return 7, nil
+	}
+	return int(t.Weekday()), nil
+}
+
+// hour returns the current hour of the day, from 0 to 23
+func hour(args []interface{}) (interface{}, error) {
+	if len(args) > 1 {
+		return nil, &ArgumentCountError{Method: "hour", Expected: "0-1 arguments", Received: len(args)}
+	}
+	t, err := localNow("hour", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return t.Hour(), nil
+}
+
+// timeBetween returns true if the current time of the day is between the start inclusively and
+// the end exclusively, times are in the HH:MM format and the range can span over midnight
+func timeBetween(args []interface{}) (interface{}, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, &ArgumentCountError{Method: "timeBetween", Expected: "2-3 arguments", Received: len(args)}
+	}
+	start, err := timeOfDayArg("timeBetween", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := timeOfDayArg("timeBetween", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	t, err := localNow("timeBetween", args[2:], 2)
+	if err != nil {
+		return nil, err
+	}
+
+	current := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if start <= end {
+		return current >= start && current < end, nil
+	}
+	return current >= start || current < end, nil
+}
+
+// localNow returns the current time in the timezone optionally provided as the only argument, the
+// position is the position of that argument in the method call.
+func localNow(method string, args []interface{}, position int) (time.Time, error) {
+	t := timeNow()
+	if len(args) == 0 {
+		return t, nil
+	}
+
+	name, ok := args[0].(string)
+	if !ok {
+		return time.Time{}, &ArgumentTypeError{Method: method, Position: position, Expected: "a string", Received: args[0]}
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Time{}, &ArgumentValueError{Method: method, Position: position, Value: name, Err: err}
+	}
+	return t.In(loc), nil
+}
+
+func timeOfDayArg(method string, args []interface{}, position int) (time.Duration, error) {
+	s, err := stringArg(method, args, position)
+	if err != nil {
+		return 0, err
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, &ArgumentValueError{Method: method, Position: position, Value: s, Err: fmt.Errorf("expecting HH:MM format")}
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,147 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package eql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semverCompare returns -1, 0 or 1 when the first version is respectively lower, equal or greater
+// than the second version.
+func semverCompare(args []interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, &ArgumentCountError{Method: "semverCompare", Expected: "exactly 2 arguments", Received: len(args)}
+	}
+	a, err := versionArg("semverCompare", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := versionArg("semverCompare", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return a.compare(b), nil
+}
+
+// versionAtLeast returns true if the version is greater or equal to the minimum version.
+func versionAtLeast(args []interface{}) (interface{}, error) {
+	if len(args) != 2 {
+		return nil, &ArgumentCountError{Method: "versionAtLeast", Expected: "exactly 2 arguments", Received: len(args)}
+	}
+	if _, ok := args[0].(*null); ok {
+		return false, nil
+	}
+	v, err := versionArg("versionAtLeast", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	min, err := versionArg("versionAtLeast", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return v.compare(min) >= 0, nil
+}
+
+// semver is a semantic version, missing minor or patch numbers are considered to be 0 and build
+// metadata is ignored.
+type semver struct {
+	numbers    [3]int
+	prerelease []string
+}
+
+func versionArg(method string, args []interface{}, position int) (*semver, error) {
+	s, err := stringArg(method, args, position)
+	if err != nil {
+		return nil, err
+	}
+	v, err := parseSemver(s)
+	if err != nil {
+		return nil, &ArgumentValueError{Method: method, Position: position, Value: s, Err: err}
+	}
+	return v, nil
+}
+
+func parseSemver(s string) (*semver, error) {
+	s = strings.TrimPrefix(s, "v")
+	if idx := strings.Index(s, "+"); idx != -1 {
+		s = s[:idx]
+	}
+
+	v := &semver{}
+	if idx := strings.Index(s, "-"); idx != -1 {
+		if idx == len(s)-1 {
+			return nil, fmt.Errorf("empty pre-release")
+		}
+		v.prerelease = strings.Split(s[idx+1:], ".")
+		s = s[:idx]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("expecting at most 3 version numbers")
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("'%s' is not a version number", part)
+		}
+		v.numbers[i] = n
+	}
+	return v, nil
+}
+
+// compare follows the semantic versioning precedence rules.
+func (v *semver) compare(o *semver) int {
+	for i := range v.numbers {
+		if c := compareInt(v.numbers[i], o.numbers[i]); c != 0 {
+			return c
+		}
+	}
+
+	// a version without pre-release has higher precedence
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		a, b := v.prerelease[i], o.prerelease[i]
+		an, aErr := strconv.Atoi(a)
+		bn, bErr := strconv.Atoi(b)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInt(an, bn)
+		case aErr == nil:
+			// numeric identifiers have lower precedence
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(a, b)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.prerelease), len(o.prerelease))
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -197,9 +197,9 @@ func (v *expVisitor) VisitExpFunction(ctx *parser.ExpFunctionContext) interface{
 	var val interface{}
 	if ctx.Arguments() != nil {
 		args := ctx.Arguments().Accept(v).([]interface{})
-		val, err = method(args)
+		val, err = method.call(args)
 	} else {
-		val, err = method(make([]interface{}, 0))
+		val, err = method.call(make([]interface{}, 0))
 	}
 
 	if err != nil {
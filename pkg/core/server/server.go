@@ -52,6 +52,9 @@ var (
 	ErrApplicationStopping = errors.New("application stopping", errors.TypeApplication)
 	// ErrApplicationStopTimedOut returned when calling Stop and the application timed out stopping.
 	ErrApplicationStopTimedOut = errors.New("application stopping timed out", errors.TypeApplication)
+	// ErrApplicationDrainTimedOut returned when calling StopAndDrain and the application did not drain within
+	// the drain timeout.
+	ErrApplicationDrainTimedOut = errors.New("application draining timed out", errors.TypeApplication)
 	// ErrActionTimedOut returned on PerformAction when the action timed out.
 	ErrActionTimedOut = errors.New("application action timed out", errors.TypeApplication)
 	// ErrActionCancelled returned on PerformAction when an action is cancelled, normally due to the application
@@ -59,6 +62,41 @@ var (
 	ErrActionCancelled = errors.New("application action cancelled", errors.TypeApplication)
 )
 
+// DrainPayloadKey is the key of the status payload an application reports its drain progress under while
+// stopping, e.g. {"drain": {"pending": 42, "drained": false}}.
+const DrainPayloadKey = "drain"
+
+// DrainProgress is the progress of an application flushing its queued events before it stops.
+type DrainProgress struct {
+	// Pending is the number of events still queued.
+	Pending int64
+	// Drained is true once every queued event is handled.
+	Drained bool
+}
+
+// DrainStatus returns the drain progress reported in the status payload, false when the application does
+// not report one.
+func DrainStatus(payload map[string]interface{}) (DrainProgress, bool) {
+	raw, ok := payload[DrainPayloadKey]
+	if !ok {
+		return DrainProgress{}, false
+	}
+	drain, ok := raw.(map[string]interface{})
+	if !ok {
+		return DrainProgress{}, false
+	}
+	var progress DrainProgress
+	if pending, ok := drain["pending"].(float64); ok {
+		progress.Pending = int64(pending)
+	}
+	if drained, ok := drain["drained"].(bool); ok {
+		progress.Drained = drained
+	} else {
+		progress.Drained = progress.Pending == 0
+	}
+	return progress, true
+}
+
 // ApplicationState represents the applications state according to the server.
 type ApplicationState struct {
 	srv *Server
@@ -568,6 +606,16 @@ func (as *ApplicationState) WriteConnInfo(w io.Writer) error {
 // Once the application is stopped or the timeout is reached the application is destroyed. Even in the case
 // the application times out during stop and ErrApplication
 func (as *ApplicationState) Stop(timeout time.Duration) error {
+	return as.StopAndDrain(timeout, 0)
+}
+
+// StopAndDrain instructs the application to stop gracefully, an application reporting that it drains its
+// queued events is given up to the drain timeout to complete instead of the stop timeout.
+//
+// The application reports its progress in the payload of its STOPPING status under DrainPayloadKey. Once
+// it reports being drained it has the stop timeout left to disconnect. When a timeout is reached the
+// application is destroyed and ErrApplicationStopTimedOut or ErrApplicationDrainTimedOut is returned.
+func (as *ApplicationState) StopAndDrain(timeout, drainTimeout time.Duration) error {
 	as.checkinLock.Lock()
 	wasConn := as.checkinDone != nil
 	cfgIdx := as.statusConfigIdx
@@ -582,14 +630,12 @@ func (as *ApplicationState) Stop(timeout time.Duration) error {
 	}, false)
 
 	started := time.Now().UTC()
+	deadline := started.Add(timeout)
+	draining := false
 	for {
-		if time.Now().UTC().Sub(started) > timeout {
-			as.Destroy()
-			return ErrApplicationStopTimedOut
-		}
-
 		as.checkinLock.RLock()
 		s := as.status
+		payload := as.statusPayload
 		doneChan := as.checkinDone
 		as.checkinLock.RUnlock()
 		if (wasConn && doneChan == nil) || (!wasConn && s == proto.StateObserved_STOPPING && doneChan == nil) {
@@ -600,6 +646,28 @@ func (as *ApplicationState) Stop(timeout time.Duration) error {
 			return nil
 		}
 
+		if progress, ok := DrainStatus(payload); ok && s == proto.StateObserved_STOPPING {
+			now := time.Now().UTC()
+			if !draining && !progress.Drained && drainTimeout > timeout {
+				draining = true
+				deadline = started.Add(drainTimeout)
+			} else if draining && progress.Drained {
+				// drained, only the time to disconnect is left
+				draining = false
+				if d := now.Add(timeout); d.Before(deadline) {
+					deadline = d
+				}
+			}
+		}
+
+		if time.Now().UTC().After(deadline) {
+			as.Destroy()
+			if draining {
+				return ErrApplicationDrainTimedOut
+			}
+			return ErrApplicationStopTimedOut
+		}
+
 		<-time.After(500 * time.Millisecond)
 	}
 }
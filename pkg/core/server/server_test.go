@@ -510,6 +510,162 @@ func TestServer_StopTimeout(t *testing.T) {
 	assert.Equal(t, ErrApplicationStopTimedOut, stopErr)
 }
 
+func TestServer_StopAndDrain(t *testing.T) {
+	initConfig := "initial_config"
+	app := &StubApp{}
+	srv := createAndStartServer(t, &StubHandler{})
+	defer srv.Stop()
+	as, err := srv.Register(app, initConfig)
+	require.NoError(t, err)
+	cImpl := &StubClientImpl{}
+	c := newClientFromApplicationState(t, as, cImpl)
+	require.NoError(t, c.Start(context.Background()))
+	defer c.Stop()
+
+	// clients should get initial check-ins then set as healthy
+	require.NoError(t, waitFor(func() error {
+		if cImpl.Config() != initConfig {
+			return fmt.Errorf("client never got initial config")
+		}
+		return nil
+	}))
+	c.Status(proto.StateObserved_HEALTHY, "Running", nil)
+	assert.NoError(t, waitFor(func() error {
+		if app.Status() != proto.StateObserved_HEALTHY {
+			return fmt.Errorf("server never updated currect application state")
+		}
+		return nil
+	}))
+
+	// send stop to the client, the stop timeout is shorter than the drain
+	done := make(chan bool)
+	var stopErr error
+	go func() {
+		stopErr = as.StopAndDrain(500*time.Millisecond, 10*time.Second)
+		close(done)
+	}()
+
+	// process of testing the flow
+	//   1. server sends stop
+	//   2. client reports draining past the stop timeout
+	//   3. client reports drained
+	//   4. client disconnects
+	require.NoError(t, waitFor(func() error {
+		if cImpl.Stop() == 0 {
+			return fmt.Errorf("client never got expected stop")
+		}
+		return nil
+	}))
+	c.Status(proto.StateObserved_STOPPING, "Draining", map[string]interface{}{
+		DrainPayloadKey: map[string]interface{}{"pending": 42, "drained": false},
+	})
+	<-time.After(1500 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatalf("stop returned while the application was draining: %v", stopErr)
+	default:
+	}
+	c.Status(proto.StateObserved_STOPPING, "Drained", map[string]interface{}{
+		DrainPayloadKey: map[string]interface{}{"pending": 0, "drained": true},
+	})
+	require.NoError(t, waitFor(func() error {
+		if app.Message() != "Drained" {
+			return fmt.Errorf("server never updated to drained")
+		}
+		return nil
+	}))
+	c.Stop()
+	<-done
+
+	// no error on stop
+	assert.NoError(t, stopErr)
+}
+
+func TestServer_StopAndDrainTimeout(t *testing.T) {
+	initConfig := "initial_config"
+	app := &StubApp{}
+	srv := createAndStartServer(t, &StubHandler{})
+	defer srv.Stop()
+	as, err := srv.Register(app, initConfig)
+	require.NoError(t, err)
+	cImpl := &StubClientImpl{}
+	c := newClientFromApplicationState(t, as, cImpl)
+	require.NoError(t, c.Start(context.Background()))
+	defer c.Stop()
+
+	// clients should get initial check-ins then set as healthy
+	require.NoError(t, waitFor(func() error {
+		if cImpl.Config() != initConfig {
+			return fmt.Errorf("client never got initial config")
+		}
+		return nil
+	}))
+	c.Status(proto.StateObserved_HEALTHY, "Running", nil)
+	assert.NoError(t, waitFor(func() error {
+		if app.Status() != proto.StateObserved_HEALTHY {
+			return fmt.Errorf("server never updated currect application state")
+		}
+		return nil
+	}))
+
+	// send stop to the client
+	done := make(chan bool)
+	var stopErr error
+	go func() {
+		stopErr = as.StopAndDrain(time.Millisecond, time.Second)
+		close(done)
+	}()
+
+	// never finish draining
+	require.NoError(t, waitFor(func() error {
+		if cImpl.Stop() == 0 {
+			return fmt.Errorf("client never got expected stop")
+		}
+		return nil
+	}))
+	c.Status(proto.StateObserved_STOPPING, "Draining", map[string]interface{}{
+		DrainPayloadKey: map[string]interface{}{"pending": 42},
+	})
+
+	// drain timeout error on stop
+	<-done
+	assert.Equal(t, ErrApplicationDrainTimedOut, stopErr)
+}
+
+func TestDrainStatus(t *testing.T) {
+	cases := map[string]struct {
+		payload  map[string]interface{}
+		progress DrainProgress
+		ok       bool
+	}{
+		"no payload": {},
+		"no drain":   {payload: map[string]interface{}{"other": 1}},
+		"invalid":    {payload: map[string]interface{}{DrainPayloadKey: "yes"}},
+		"pending": {
+			payload:  map[string]interface{}{DrainPayloadKey: map[string]interface{}{"pending": float64(42), "drained": false}},
+			progress: DrainProgress{Pending: 42},
+			ok:       true,
+		},
+		"drained": {
+			payload:  map[string]interface{}{DrainPayloadKey: map[string]interface{}{"pending": float64(0), "drained": true}},
+			progress: DrainProgress{Drained: true},
+			ok:       true,
+		},
+		"pending only": {
+			payload:  map[string]interface{}{DrainPayloadKey: map[string]interface{}{"pending": float64(0)}},
+			progress: DrainProgress{Drained: true},
+			ok:       true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			progress, ok := DrainStatus(tc.payload)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.progress, progress)
+		})
+	}
+}
+
 func TestServer_WatchdogFailApp(t *testing.T) {
 	initConfig := "initial_config"
 	checkMinTimeout := 300 * time.Millisecond